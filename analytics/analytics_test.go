@@ -0,0 +1,406 @@
+package analytics
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// monday the capture time of the first snapshot, a Monday
+var monday = time.Date(2019, 6, 3, 9, 0, 0, 0, time.UTC)
+
+// day the capture time of the snapshot taken n days after the first
+func day(n int) time.Time {
+	return monday.AddDate(0, 0, n)
+}
+
+// board a workflow of To Do, Doing, Review and Done
+func board() *glo.Board {
+	return &glo.Board{
+		ID:   "b1",
+		Name: "Team",
+		Columns: []*glo.Column{
+			{ID: "todo", Name: "To Do"},
+			{ID: "doing", Name: "Doing", Position: 1},
+			{ID: "review", Name: "Review", Position: 2},
+			{ID: "done", Name: "Done", Position: 3},
+		},
+	}
+}
+
+// snapshot a snapshot n days after the first placing cards, by ID,
+// in columns, a column prefixed with "archived:" archives the card
+func snapshot(n int, columns map[string]string) *glo.BoardSnapshot {
+	snap := &glo.BoardSnapshot{CapturedAt: day(n), Board: board()}
+	for _, id := range sortedKeys(columns) {
+		card := &glo.Card{ID: id, Name: "card " + id, ColumnID: columns[id]}
+		if strings.HasPrefix(card.ColumnID, "archived:") {
+			card.ColumnID = strings.TrimPrefix(card.ColumnID, "archived:")
+			card.ArchivedDate = day(n).Format(time.RFC3339)
+		}
+		snap.Cards = append(snap.Cards, card)
+	}
+
+	return snap
+}
+
+func sortedKeys(m map[string]string) []string {
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+func mustHistory(t *testing.T, snaps ...*glo.BoardSnapshot) *History {
+	t.Helper()
+	h, err := NewHistory(snaps)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func TestNewHistory(t *testing.T) {
+	later := snapshot(1, nil)
+	earlier := snapshot(0, nil)
+	earlier.Board.Columns = append(earlier.Board.Columns, &glo.Column{ID: "old", Name: "Old", Position: 4})
+
+	h := mustHistory(t, later, earlier)
+	if h.Snapshots[0] != earlier || h.Latest() != later {
+		t.Errorf("snapshots were not ordered by capture time")
+	}
+
+	var names []string
+	for _, col := range h.Columns() {
+		names = append(names, col.Name)
+	}
+	if want := []string{"To Do", "Doing", "Review", "Done", "Old"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Columns = %v, want %v", names, want)
+	}
+
+	other := snapshot(2, nil)
+	other.Board.ID = "b2"
+	for name, snaps := range map[string][]*glo.BoardSnapshot{
+		"none":            nil,
+		"different board": {earlier, other},
+		"no board":        {{CapturedAt: day(0)}},
+	} {
+		if _, err := NewHistory(snaps); err == nil {
+			t.Errorf("%s: NewHistory did not fail", name)
+		}
+	}
+}
+
+func TestCumulativeFlow(t *testing.T) {
+	tests := []struct {
+		name    string
+		snaps   []*glo.BoardSnapshot
+		columns []string
+		counts  [][]int
+	}{
+		{
+			name: "cards move through the workflow",
+			snaps: []*glo.BoardSnapshot{
+				snapshot(0, map[string]string{"a": "todo", "b": "todo", "c": "todo"}),
+				snapshot(1, map[string]string{"a": "doing", "b": "todo", "c": "todo", "d": "todo"}),
+				snapshot(2, map[string]string{"a": "done", "b": "review", "c": "doing", "d": "todo"}),
+			},
+			columns: []string{"To Do", "Doing", "Review", "Done"},
+			counts:  [][]int{{3, 0, 0, 0}, {3, 1, 0, 0}, {1, 1, 1, 1}},
+		},
+		{
+			name: "archived cards stay in their last column",
+			snaps: []*glo.BoardSnapshot{
+				snapshot(0, map[string]string{"a": "done", "b": "doing"}),
+				snapshot(1, map[string]string{"a": "archived:done", "b": "done"}),
+				// captured without archived cards
+				snapshot(2, map[string]string{"c": "todo"}),
+			},
+			columns: []string{"To Do", "Doing", "Review", "Done"},
+			counts:  [][]int{{0, 1, 0, 1}, {0, 0, 0, 2}, {1, 0, 0, 2}},
+		},
+		{
+			name: "removed columns are included while they held cards",
+			snaps: []*glo.BoardSnapshot{
+				func() *glo.BoardSnapshot {
+					snap := snapshot(0, map[string]string{"a": "qa", "b": "todo"})
+					snap.Board.Columns = append(snap.Board.Columns, &glo.Column{ID: "qa", Name: "QA", Position: 4})
+					snap.Board.Columns = append(snap.Board.Columns, &glo.Column{ID: "empty", Name: "Empty", Position: 5})
+					return snap
+				}(),
+				snapshot(1, map[string]string{"a": "done", "b": "todo"}),
+			},
+			columns: []string{"To Do", "Doing", "Review", "Done", "QA"},
+			counts:  [][]int{{1, 0, 0, 0, 1}, {1, 0, 0, 1, 0}},
+		},
+	}
+
+	for _, test := range tests {
+		flow := mustHistory(t, test.snaps...).CumulativeFlow()
+		if !reflect.DeepEqual(flow.Columns, test.columns) {
+			t.Errorf("%s: columns = %v, want %v", test.name, flow.Columns, test.columns)
+		}
+		var counts [][]int
+		for i, point := range flow.Points {
+			if !point.Time.Equal(test.snaps[i].CapturedAt) {
+				t.Errorf("%s: point %d at %s", test.name, i, point.Time)
+			}
+			counts = append(counts, point.Counts)
+		}
+		if !reflect.DeepEqual(counts, test.counts) {
+			t.Errorf("%s: counts = %v, want %v", test.name, counts, test.counts)
+		}
+	}
+}
+
+// dayOf a time as the number of days after the first snapshot, -1 when nil
+func dayOf(t *time.Time) int {
+	if t == nil {
+		return -1
+	}
+
+	return int(t.Sub(monday).Hours() / 24)
+}
+
+func TestCycleTimes(t *testing.T) {
+	h := mustHistory(
+		t,
+		snapshot(0, map[string]string{"started": "doing", "finished": "done", "waiting": "todo", "reopened": "todo", "back": "todo"}),
+		snapshot(1, map[string]string{"started": "doing", "finished": "done", "waiting": "todo", "reopened": "doing", "back": "doing"}),
+		snapshot(3, map[string]string{"started": "review", "finished": "archived:done", "waiting": "todo", "reopened": "done", "back": "todo", "new": "todo"}),
+		snapshot(5, map[string]string{"started": "done", "waiting": "todo", "reopened": "review", "back": "todo", "new": "doing"}),
+		snapshot(8, map[string]string{"started": "done", "waiting": "todo", "reopened": "done", "back": "todo", "new": "done"}),
+	)
+
+	type want struct {
+		started, finished int
+		lead, cycle       float64
+	}
+	tests := []struct {
+		name string
+		opts *CycleOptions
+		want map[string]want
+	}{
+		{
+			name: "default columns",
+			want: map[string]want{
+				// started before the history began
+				"started": {-1, 5, 5, -1},
+				// finished before the history began
+				"finished": {-1, -1, -1, -1},
+				"waiting":  {-1, -1, -1, -1},
+				// finished on day 3, moved back and finished again
+				"reopened": {1, 8, 8, 7},
+				// started, then moved back before the start column
+				"back": {1, -1, -1, -1},
+				"new":  {5, 8, 5, 3},
+			},
+		},
+		{
+			name: "review to done",
+			opts: &CycleOptions{Start: "review", End: "Done"},
+			want: map[string]want{
+				"started":  {3, 5, 5, 2},
+				"finished": {-1, -1, -1, -1},
+				"waiting":  {-1, -1, -1, -1},
+				"reopened": {3, 8, 8, 5},
+				"back":     {-1, -1, -1, -1},
+				"new":      {8, 8, 5, 0},
+			},
+		},
+		{
+			name: "doing to review",
+			opts: &CycleOptions{End: "review"},
+			want: map[string]want{
+				"started":  {-1, 3, 3, -1},
+				"finished": {-1, -1, -1, -1},
+				"waiting":  {-1, -1, -1, -1},
+				"reopened": {1, 3, 3, 2},
+				"back":     {1, -1, -1, -1},
+				"new":      {5, 8, 5, 3},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		times, err := h.CycleTimes(test.opts)
+		if err != nil {
+			t.Errorf("%s: CycleTimes err:%s", test.name, err)
+			continue
+		}
+
+		got := map[string]want{}
+		for _, ct := range times {
+			w := want{started: dayOf(ct.Started), finished: dayOf(ct.Finished), lead: -1, cycle: -1}
+			if ct.LeadDays != nil {
+				w.lead = *ct.LeadDays
+			}
+			if ct.CycleDays != nil {
+				w.cycle = *ct.CycleDays
+			}
+			got[ct.CardID] = w
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: CycleTimes =\n%v\nwant\n%v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCycleTimesOrder(t *testing.T) {
+	h := mustHistory(
+		t,
+		snapshot(0, map[string]string{"c": "done", "a": "todo", "b": "doing"}),
+		snapshot(1, map[string]string{"c": "done", "a": "todo", "b": "doing", "d": "todo"}),
+	)
+
+	times, err := h.CycleTimes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, ct := range times {
+		ids = append(ids, ct.CardID)
+	}
+	if want := []string{"a", "b", "c", "d"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("cards ordered %v, want %v", ids, want)
+	}
+}
+
+func TestCycleOptionsErrors(t *testing.T) {
+	h := mustHistory(t, snapshot(0, nil))
+
+	for _, opts := range []*CycleOptions{
+		{Start: "missing"},
+		{End: "missing"},
+		{Start: "Done", End: "Doing"},
+	} {
+		if _, err := h.CycleTimes(opts); err == nil {
+			t.Errorf("CycleTimes(%+v) did not fail", opts)
+		}
+	}
+
+	single := snapshot(0, nil)
+	single.Board.Columns = single.Board.Columns[:1]
+	if _, err := mustHistory(t, single).CycleTimes(nil); err == nil {
+		t.Error("CycleTimes of a board with one column did not fail")
+	}
+}
+
+func TestThroughput(t *testing.T) {
+	tests := []struct {
+		name  string
+		snaps []*glo.BoardSnapshot
+		want  []string
+	}{
+		{
+			name: "single week",
+			snaps: []*glo.BoardSnapshot{
+				snapshot(0, map[string]string{"a": "doing", "b": "done"}),
+				snapshot(2, map[string]string{"a": "done", "b": "done"}),
+			},
+			// b finished before the history began
+			want: []string{"2019-06-03:1"},
+		},
+		{
+			name: "weeks without finished cards",
+			snaps: []*glo.BoardSnapshot{
+				snapshot(0, map[string]string{"a": "todo", "b": "todo", "c": "todo"}),
+				snapshot(6, map[string]string{"a": "done", "b": "doing", "c": "todo"}),
+				snapshot(7, map[string]string{"a": "done", "b": "done", "c": "done"}),
+				snapshot(20, map[string]string{"a": "done", "b": "done", "c": "done"}),
+			},
+			want: []string{"2019-06-03:1", "2019-06-10:2", "2019-06-17:0"},
+		},
+		{
+			name: "weeks start on monday in UTC",
+			snaps: []*glo.BoardSnapshot{
+				snapshot(-1, map[string]string{"a": "todo"}),
+				snapshot(0, map[string]string{"a": "done"}),
+			},
+			want: []string{"2019-05-27:0", "2019-06-03:1"},
+		},
+	}
+
+	for _, test := range tests {
+		h := mustHistory(t, test.snaps...)
+		times, err := h.CycleTimes(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		for _, week := range h.Throughput(times) {
+			got = append(got, week.Week.Format("2006-01-02")+":"+strconv.Itoa(week.Finished))
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Throughput = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestAgingWIP(t *testing.T) {
+	h := mustHistory(
+		t,
+		snapshot(0, map[string]string{"old": "doing", "done": "doing", "back": "todo"}),
+		snapshot(2, map[string]string{"old": "review", "done": "done", "back": "doing", "new": "todo"}),
+		snapshot(4, map[string]string{"old": "review", "done": "done", "back": "todo", "new": "doing"}),
+	)
+	times, err := h.CycleTimes(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, card := range h.AgingWIP(times) {
+		got = append(got, fmt.Sprintf("%s %s %.0f/%.0f", card.CardID, card.Column, card.AgeDays, card.ColumnDays))
+	}
+	want := []string{"old Review 4/2", "new Doing 0/0"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AgingWIP = %v, want %v", got, want)
+	}
+}
+
+func TestSaveLoadHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	first := snapshot(0, map[string]string{"a": "todo"})
+	// captured within the same second
+	second := snapshot(0, map[string]string{"a": "doing"})
+	second.CapturedAt = second.CapturedAt.Add(300 * time.Millisecond)
+	other := snapshot(0, map[string]string{"x": "todo"})
+	other.Board.ID = "b2"
+
+	paths := map[string]bool{}
+	for _, snap := range []*glo.BoardSnapshot{second, first, other} {
+		path, err := Save(snap, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[path] = true
+	}
+	if len(paths) != 3 {
+		t.Fatalf("snapshots were saved to %d files, want 3", len(paths))
+	}
+
+	h, err := LoadHistory("b1", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(h.Snapshots) != 2 || h.Snapshots[0].Cards[0].ColumnID != "todo" || h.Latest().Cards[0].ColumnID != "doing" {
+		t.Errorf("LoadHistory read %d snapshots out of order", len(h.Snapshots))
+	}
+
+	if _, err = LoadHistory("b3", dir); err == nil {
+		t.Error("LoadHistory of a board without snapshots did not fail")
+	}
+}
@@ -0,0 +1,354 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CycleOptions the columns between which cycle time is measured
+type CycleOptions struct {
+	// Start the column, by ID or name, in which work on a card starts,
+	// defaults to the second column
+	Start string
+
+	// End the column, by ID or name, in which a card is finished,
+	// defaults to the last column
+	End string
+}
+
+// CardTimes when a card was created, started and finished, a card
+// is started once it reaches the start column or any column after it
+// and finished once it reaches the end column or any column after it.
+// Cards started or finished before the first snapshot have no start
+// or finish time
+type CardTimes struct {
+	CardID   string     `json:"card_id"`
+	Name     string     `json:"name"`
+	Column   string     `json:"column"`
+	Created  time.Time  `json:"created"`
+	Started  *time.Time `json:"started,omitempty"`
+	Finished *time.Time `json:"finished,omitempty"`
+
+	// LeadDays the days from creation to finishing
+	LeadDays *float64 `json:"lead_days,omitempty"`
+
+	// CycleDays the days from starting to finishing
+	CycleDays *float64 `json:"cycle_days,omitempty"`
+
+	// entered when the card entered its current column
+	entered time.Time
+
+	// inProgress the card is between the start and end
+	// columns in the latest snapshot
+	inProgress bool
+}
+
+// CycleTimes the lead and cycle times of cards
+type CycleTimes []*CardTimes
+
+// stages resolves the start and end columns
+func (h *History) stages(opts *CycleOptions) (start, end int, err error) {
+	if opts == nil {
+		opts = &CycleOptions{}
+	}
+	if h.active < 2 {
+		err = fmt.Errorf("board %s needs at least two columns to measure cycle time", h.Board)
+		return
+	}
+
+	start, end = 1, h.active-1
+	if opts.Start != "" {
+		start, err = h.findColumn(opts.Start)
+		if err != nil {
+			return
+		}
+	}
+	if opts.End != "" {
+		end, err = h.findColumn(opts.End)
+		if err != nil {
+			return
+		}
+	}
+	if end < start {
+		err = fmt.Errorf("end column %q is before start column %q", h.columns[end].Name, h.columns[start].Name)
+	}
+
+	return
+}
+
+// CycleTimes the times of every card in the history, a card which
+// moves back before the end column is finished again once it returns
+func (h *History) CycleTimes(opts *CycleOptions) (times CycleTimes, err error) {
+	start, end, err := h.stages(opts)
+	if err != nil {
+		return
+	}
+
+	ids, timelines := h.timelines()
+	times = CycleTimes{}
+	for _, id := range ids {
+		timeline := timelines[id]
+		latest := timeline[len(timeline)-1].card
+
+		t := &CardTimes{
+			CardID:  id,
+			Name:    latest.Name,
+			Column:  columnName(h, latest.ColumnID),
+			Created: timeline[0].at,
+		}
+		if created, parseErr := time.Parse(time.RFC3339, latest.CreatedDate); parseErr == nil {
+			t.Created = created
+		}
+
+		// a card already started or finished in the first snapshot
+		// did so at an unknown time before the history begins
+		var columnID string
+		var startedBefore, finishedBefore bool
+		for _, obs := range timeline {
+			if obs.card.ColumnID != columnID {
+				columnID = obs.card.ColumnID
+				t.entered = obs.at
+			}
+
+			stage := h.stage(obs.card.ColumnID)
+			if stage < 0 {
+				continue
+			}
+			at := obs.at
+			baseline := at.Equal(h.Snapshots[0].CapturedAt)
+			if stage >= start && t.Started == nil && !startedBefore {
+				if baseline {
+					startedBefore = true
+				} else {
+					t.Started = &at
+				}
+			}
+			if stage >= end && t.Finished == nil && !finishedBefore {
+				if baseline {
+					finishedBefore = true
+				} else {
+					t.Finished = &at
+				}
+			}
+			if stage < end {
+				t.Finished = nil
+				finishedBefore = false
+			}
+		}
+		stage := h.stage(latest.ColumnID)
+		t.inProgress = stage >= start && stage < end && latest.ArchivedDate == "" &&
+			timeline[len(timeline)-1].at.Equal(h.Latest().CapturedAt)
+
+		if t.Finished != nil {
+			lead := days(t.Finished.Sub(t.Created))
+			t.LeadDays = &lead
+		}
+		if t.Finished != nil && t.Started != nil {
+			cycle := days(t.Finished.Sub(*t.Started))
+			t.CycleDays = &cycle
+		}
+		times = append(times, t)
+	}
+
+	return
+}
+
+// WriteCSV writes a row per card, times of cards
+// which have not started or finished are empty
+func (times CycleTimes) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{
+		"card_id",
+		"name",
+		"column",
+		"created",
+		"started",
+		"finished",
+		"lead_days",
+		"cycle_days",
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range times {
+		err = cw.Write([]string{
+			t.CardID,
+			t.Name,
+			t.Column,
+			formatTime(&t.Created),
+			formatTime(t.Started),
+			formatTime(t.Finished),
+			formatDays(t.LeadDays),
+			formatDays(t.CycleDays),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// WeekCount the cards finished in the week starting Monday
+type WeekCount struct {
+	Week     time.Time `json:"week"`
+	Finished int       `json:"finished"`
+}
+
+// Throughput the cards finished each week
+type Throughput []WeekCount
+
+// Throughput counts the finished cards per week, in UTC, from
+// the week of the first snapshot to the week of the latest
+func (h *History) Throughput(times CycleTimes) Throughput {
+	first := weekOf(h.Snapshots[0].CapturedAt)
+	last := weekOf(h.Latest().CapturedAt)
+
+	throughput := Throughput{}
+	index := map[time.Time]int{}
+	for week := first; !week.After(last); week = week.AddDate(0, 0, 7) {
+		index[week] = len(throughput)
+		throughput = append(throughput, WeekCount{Week: week})
+	}
+
+	for _, t := range times {
+		if t.Finished == nil {
+			continue
+		}
+		if i, ok := index[weekOf(*t.Finished)]; ok {
+			throughput[i].Finished++
+		}
+	}
+
+	return throughput
+}
+
+// WriteCSV writes a row per week
+func (throughput Throughput) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"week", "finished"})
+	if err != nil {
+		return err
+	}
+
+	for _, week := range throughput {
+		err = cw.Write([]string{week.Week.Format("2006-01-02"), strconv.Itoa(week.Finished)})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// AgingCard a started card which is not yet finished
+type AgingCard struct {
+	CardID  string    `json:"card_id"`
+	Name    string    `json:"name"`
+	Column  string    `json:"column"`
+	Started time.Time `json:"started"`
+
+	// AgeDays the days since the card started
+	AgeDays float64 `json:"age_days"`
+
+	// ColumnDays the days since the card entered its column
+	ColumnDays float64 `json:"column_days"`
+}
+
+// WIP cards in progress, oldest first
+type WIP []*AgingCard
+
+// AgingWIP the cards of the latest snapshot which have started but
+// not finished, aged to the latest snapshot's capture time. Cards in
+// progress in the first snapshot are aged from its capture time
+func (h *History) AgingWIP(times CycleTimes) WIP {
+	latest := h.Latest()
+
+	wip := WIP{}
+	for _, t := range times {
+		if !t.inProgress {
+			continue
+		}
+
+		started := h.Snapshots[0].CapturedAt
+		if t.Started != nil {
+			started = *t.Started
+		}
+		wip = append(wip, &AgingCard{
+			CardID:     t.CardID,
+			Name:       t.Name,
+			Column:     t.Column,
+			Started:    started,
+			AgeDays:    days(latest.CapturedAt.Sub(started)),
+			ColumnDays: days(latest.CapturedAt.Sub(t.entered)),
+		})
+	}
+	sort.SliceStable(wip, func(i, j int) bool {
+		return wip[i].AgeDays > wip[j].AgeDays
+	})
+
+	return wip
+}
+
+// WriteCSV writes a row per card in progress
+func (wip WIP) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"card_id", "name", "column", "started", "age_days", "column_days"})
+	if err != nil {
+		return err
+	}
+
+	for _, card := range wip {
+		err = cw.Write([]string{
+			card.CardID,
+			card.Name,
+			card.Column,
+			formatTime(&card.Started),
+			formatDays(&card.AgeDays),
+			formatDays(&card.ColumnDays),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// weekOf the start of the UTC week, Monday, containing t
+func weekOf(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatDays(d *float64) string {
+	if d == nil {
+		return ""
+	}
+
+	return strconv.FormatFloat(*d, 'f', 2, 64)
+}
@@ -0,0 +1,96 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// FlowPoint the number of cards in each column when a snapshot was captured
+type FlowPoint struct {
+	Time   time.Time `json:"time"`
+	Counts []int     `json:"counts"`
+}
+
+// CumulativeFlow the cards in each column over time, counts
+// are in the order of Columns
+type CumulativeFlow struct {
+	Columns []string    `json:"columns"`
+	Points  []FlowPoint `json:"points"`
+}
+
+// CumulativeFlow counts the cards of each column in every snapshot,
+// columns since removed or archived are included while they held cards.
+// Archived cards, and cards missing from later snapshots captured without
+// archived cards, remain counted in the last column they were seen in so
+// finished work keeps accumulating. Deleted cards can not be told apart
+// from them and are counted likewise
+func (h *History) CumulativeFlow() *CumulativeFlow {
+	index := map[string]int{}
+	counts := make([][]int, len(h.Snapshots))
+	used := make([]bool, len(h.columns))
+	for i, col := range h.columns {
+		index[col.ID] = i
+		used[i] = i < h.active
+	}
+
+	// last the column each card was last seen in
+	last := map[string]int{}
+	for i, snap := range h.Snapshots {
+		for _, card := range snap.Cards {
+			if col, ok := index[card.ColumnID]; ok {
+				last[card.ID] = col
+			}
+		}
+
+		counts[i] = make([]int, len(h.columns))
+		for _, col := range last {
+			counts[i][col]++
+			used[col] = true
+		}
+	}
+
+	flow := &CumulativeFlow{Columns: []string{}, Points: []FlowPoint{}}
+	for i, col := range h.columns {
+		if used[i] {
+			flow.Columns = append(flow.Columns, col.Name)
+		}
+	}
+	for i, snap := range h.Snapshots {
+		point := FlowPoint{Time: snap.CapturedAt, Counts: []int{}}
+		for col, n := range counts[i] {
+			if used[col] {
+				point.Counts = append(point.Counts, n)
+			}
+		}
+		flow.Points = append(flow.Points, point)
+	}
+
+	return flow
+}
+
+// WriteCSV writes a row per snapshot with a column of counts per board column
+func (f *CumulativeFlow) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(append([]string{"time"}, f.Columns...))
+	if err != nil {
+		return err
+	}
+
+	for _, point := range f.Points {
+		row := []string{point.Time.UTC().Format(time.RFC3339)}
+		for _, n := range point.Counts {
+			row = append(row, strconv.Itoa(n))
+		}
+		err = cw.Write(row)
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
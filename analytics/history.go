@@ -0,0 +1,250 @@
+// Package analytics computes flow metrics of a board from periodic
+// snapshots: cumulative flow per column, lead and cycle time per card,
+// weekly throughput and the age of work in progress.
+//
+// Snapshots only record where each card was when they were captured,
+// so a card is taken to have entered a column at the capture time of
+// the first snapshot placing it there. Metrics are as precise as the
+// interval between snapshots.
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/boarddiff"
+)
+
+// History snapshots of a board, oldest first
+type History struct {
+	BoardID   string
+	Board     string
+	Snapshots []*glo.BoardSnapshot
+
+	// columns the active columns of the latest snapshot in
+	// position order, followed by columns since removed or archived
+	columns []*glo.Column
+	active  int
+}
+
+// NewHistory orders snapshots of a board by capture time
+func NewHistory(snaps []*glo.BoardSnapshot) (h *History, err error) {
+	if len(snaps) == 0 {
+		err = fmt.Errorf("no snapshots")
+		return
+	}
+
+	h = &History{Snapshots: append([]*glo.BoardSnapshot{}, snaps...)}
+	sort.SliceStable(h.Snapshots, func(i, j int) bool {
+		return h.Snapshots[i].CapturedAt.Before(h.Snapshots[j].CapturedAt)
+	})
+
+	for _, snap := range h.Snapshots {
+		if snap.Board == nil {
+			err = fmt.Errorf("snapshot captured at %s has no board", snap.CapturedAt)
+			return
+		}
+		if h.BoardID != "" && snap.Board.ID != h.BoardID {
+			err = fmt.Errorf("snapshots are of different boards %s and %s", h.BoardID, snap.Board.ID)
+			return
+		}
+		h.BoardID = snap.Board.ID
+		h.Board = snap.Board.Name
+	}
+
+	latest := h.Latest()
+	seen := map[string]bool{}
+	for _, col := range latest.SortedColumns() {
+		if col.ArchivedDate == "" && !archivedColumn(latest, col.ID) {
+			h.columns = append(h.columns, col)
+			seen[col.ID] = true
+		}
+	}
+	h.active = len(h.columns)
+
+	for i := len(h.Snapshots) - 1; i >= 0; i-- {
+		for _, col := range h.Snapshots[i].SortedColumns() {
+			if !seen[col.ID] {
+				h.columns = append(h.columns, col)
+				seen[col.ID] = true
+			}
+		}
+	}
+
+	return
+}
+
+// LoadHistory reads the snapshots of a board saved as JSON files,
+// directories are expanded to the .json files they contain. When
+// boardID is not empty snapshots of other boards are ignored
+func LoadHistory(boardID string, paths ...string) (h *History, err error) {
+	var files []string
+	for _, path := range paths {
+		info, statErr := os.Stat(path)
+		if statErr != nil {
+			return nil, statErr
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		matches, globErr := filepath.Glob(filepath.Join(path, "*.json"))
+		if globErr != nil {
+			return nil, globErr
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	var snaps []*glo.BoardSnapshot
+	for _, file := range files {
+		snap, loadErr := boarddiff.Load(file)
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		if boardID != "" && snap.Board.ID != boardID {
+			continue
+		}
+		snaps = append(snaps, snap)
+	}
+
+	return NewHistory(snaps)
+}
+
+// Save writes a snapshot to dir, named by its board and
+// capture time to the nanosecond, for LoadHistory to read
+func Save(snap *glo.BoardSnapshot, dir string) (path string, err error) {
+	data, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(dir, 0700)
+	if err != nil {
+		return
+	}
+
+	path = filepath.Join(
+		dir,
+		fmt.Sprintf("%s-%s.json", snap.Board.ID, snap.CapturedAt.UTC().Format("20060102T150405.000000000Z")),
+	)
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return
+	}
+	err = os.Rename(tmp, path)
+
+	return
+}
+
+// Latest the most recent snapshot
+func (h *History) Latest() *glo.BoardSnapshot {
+	return h.Snapshots[len(h.Snapshots)-1]
+}
+
+// Columns the active columns of the latest snapshot in position
+// order, followed by the columns since removed or archived
+func (h *History) Columns() []*glo.Column {
+	return append([]*glo.Column{}, h.columns...)
+}
+
+// stage the position of an active column in the workflow,
+// -1 for columns since removed or archived
+func (h *History) stage(columnID string) int {
+	for i, col := range h.columns[:h.active] {
+		if col.ID == columnID {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// findColumn resolves a column by ID or case-insensitive name
+func (h *History) findColumn(ref string) (stage int, err error) {
+	for i, col := range h.columns[:h.active] {
+		if col.ID == ref || strings.EqualFold(col.Name, ref) {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("column %q is not an active column of board %s", ref, h.Board)
+}
+
+// observation a card as captured by a snapshot
+type observation struct {
+	at   time.Time
+	card *glo.Card
+}
+
+// timelines the observations of each card, ordered by the
+// card's first appearance then its position on the board
+func (h *History) timelines() (ids []string, timelines map[string][]observation) {
+	timelines = map[string][]observation{}
+	for _, snap := range h.Snapshots {
+		var added []*glo.Card
+		for _, card := range snap.Cards {
+			if _, ok := timelines[card.ID]; !ok {
+				added = append(added, card)
+			}
+			timelines[card.ID] = append(timelines[card.ID], observation{at: snap.CapturedAt, card: card})
+		}
+
+		sort.SliceStable(added, func(i, j int) bool {
+			si, sj := h.stage(added[i].ColumnID), h.stage(added[j].ColumnID)
+			if si != sj {
+				return si < sj
+			}
+			return added[i].Position < added[j].Position
+		})
+		for _, card := range added {
+			ids = append(ids, card.ID)
+		}
+	}
+
+	return
+}
+
+func archivedColumn(snap *glo.BoardSnapshot, id string) bool {
+	for _, col := range snap.Board.ArchivedColumns {
+		if col.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func columnName(h *History, id string) string {
+	for _, col := range h.columns {
+		if col.ID == id {
+			return col.Name
+		}
+	}
+
+	return id
+}
+
+// days a duration in days
+func days(d time.Duration) float64 {
+	return d.Hours() / 24
+}
+
+// DefaultDir the directory snapshots are captured to, alongside the config file
+func DefaultDir() (dir string, err error) {
+	config, err := glo.ConfigPath()
+	if err != nil {
+		return
+	}
+
+	return filepath.Join(filepath.Dir(config), "snapshots"), nil
+}
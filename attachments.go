@@ -6,8 +6,6 @@ import (
 	"io"
 	"net/http"
 	"strconv"
-
-	"github.com/jackmcguire1/go-glo/internal/utils"
 )
 
 // BaseAttachment contains generic attachment details
@@ -60,13 +58,17 @@ func (a *Glo) GetAttachments(
 	page int,
 	limit int,
 	sortDesc bool,
+	opts ...CallOption,
 ) (
 	attachmentsResp *AttachmentsResp,
 	err error,
 ) {
 	addr := fmt.Sprintf("%s/boards/%s/cards/%s/attachments", a.BaseURI, boardID, cardID)
 
-	q := utils.AddFields(attachmentFields)
+	q, err := fieldsQuery(attachmentResource, attachmentFields, opts)
+	if err != nil {
+		return
+	}
 	q.Set("page", fmt.Sprint(page))
 	q.Set("per_page", fmt.Sprint(limit))
 
@@ -94,6 +96,28 @@ func (a *Glo) GetAttachments(
 	return
 }
 
+// DownloadAttachment Get the contents of an attachment
+// https://gloapi.gitkraken.com/v1/docs/#/Attachments/get_boards__board_id__cards__card_id__attachments__attachment_id_
+func (a *Glo) DownloadAttachment(
+	boardID string,
+	cardID string,
+	attachmentID string,
+) (
+	data []byte,
+	mimeType string,
+	err error,
+) {
+	addr := fmt.Sprintf("%s/boards/%s/cards/%s/attachments/%s", a.BaseURI, boardID, cardID, attachmentID)
+
+	data, headers, err := a.jsonReq(http.MethodGet, addr, nil, nil)
+	if err != nil {
+		return
+	}
+	mimeType = headers.Get("Content-Type")
+
+	return
+}
+
 // CreateAttachment Will create an attachment and create
 // a new comment on the provided card so that the attachment
 // does not have a Time To Live.
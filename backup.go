@@ -0,0 +1,392 @@
+package glo
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"time"
+)
+
+// BackupVersion the version of the backup archive format
+const BackupVersion = 1
+
+// Backup archive entry names
+const (
+	backupManifestName    = "manifest.json"
+	backupBoardName       = "board.json"
+	backupCardName        = "card.json"
+	backupCommentsName    = "comments.json"
+	backupAttachmentsName = "attachments.json"
+)
+
+// BackupManifest describes the contents of a backup archive,
+// it is the final entry of the archive
+type BackupManifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Incremental set when only cards which changed since
+	// the base backup are included in the archive
+	Incremental   bool       `json:"incremental"`
+	BaseCreatedAt *time.Time `json:"base_created_at,omitempty"`
+
+	Boards []*BackupBoard `json:"boards"`
+}
+
+// BackupBoard a board within a backup archive
+type BackupBoard struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Archived bool          `json:"archived"`
+	Path     string        `json:"path"`
+	Cards    []*BackupCard `json:"cards"`
+}
+
+// BackupCard a card within a backup archive
+type BackupCard struct {
+	ID          string `json:"id"`
+	UpdatedDate string `json:"updated_date"`
+	Archived    bool   `json:"archived"`
+
+	// Included set when the card's data is part of this archive,
+	// unchanged cards of an incremental backup are only listed
+	Included    bool                `json:"included"`
+	Path        string              `json:"path,omitempty"`
+	Attachments []*BackupAttachment `json:"attachments,omitempty"`
+}
+
+// BackupAttachment an attachment within a backup archive
+type BackupAttachment struct {
+	ID       string `json:"id"`
+	Filename string `json:"filename"`
+	MimeType string `json:"mime_type"`
+	Path     string `json:"path,omitempty"`
+
+	// Error set when the attachment could not be downloaded
+	Error string `json:"error,omitempty"`
+}
+
+// Backup writes a tar archive of every board, including archived boards,
+// columns and cards, with their comments and attachments to w
+func (a *Glo) Backup(
+	ctx context.Context,
+	w io.Writer,
+) (
+	manifest *BackupManifest,
+	err error,
+) {
+	return a.backup(ctx, w, nil)
+}
+
+// BackupIncremental writes a backup archive which only includes cards
+// whose UpdatedDate changed since the base backup was taken
+func (a *Glo) BackupIncremental(
+	ctx context.Context,
+	w io.Writer,
+	base *BackupManifest,
+) (
+	manifest *BackupManifest,
+	err error,
+) {
+	if base == nil {
+		err = fmt.Errorf("incremental backup requires a base manifest")
+		return
+	}
+
+	return a.backup(ctx, w, base)
+}
+
+// ReadBackupManifest reads the manifest of a backup archive
+func ReadBackupManifest(r io.Reader) (manifest *BackupManifest, err error) {
+	tr := tar.NewReader(r)
+	for {
+		hdr, nextErr := tr.Next()
+		if nextErr == io.EOF {
+			break
+		}
+		if nextErr != nil {
+			err = nextErr
+			return
+		}
+		if hdr.Name != backupManifestName {
+			continue
+		}
+
+		manifest = &BackupManifest{}
+		err = json.NewDecoder(tr).Decode(manifest)
+		if err != nil {
+			return
+		}
+		if manifest.Version > BackupVersion {
+			err = fmt.Errorf("unsupported backup version:%d", manifest.Version)
+		}
+		return
+	}
+
+	err = fmt.Errorf("backup archive does not contain a manifest")
+
+	return
+}
+
+type backupWriter struct {
+	tw      *tar.Writer
+	modTime time.Time
+}
+
+func (bw *backupWriter) writeJSON(name string, v interface{}) (err error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return
+	}
+
+	return bw.writeFile(name, data)
+}
+
+func (bw *backupWriter) writeFile(name string, data []byte) (err error) {
+	err = bw.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0600,
+		Size:    int64(len(data)),
+		ModTime: bw.modTime,
+	})
+	if err != nil {
+		return
+	}
+
+	_, err = bw.tw.Write(data)
+
+	return
+}
+
+func (a *Glo) backup(
+	ctx context.Context,
+	w io.Writer,
+	base *BackupManifest,
+) (
+	manifest *BackupManifest,
+	err error,
+) {
+	a = a.WithContext(ctx)
+
+	manifest = &BackupManifest{
+		Version:   BackupVersion,
+		CreatedAt: time.Now().UTC(),
+	}
+	if base != nil {
+		manifest.Incremental = true
+		manifest.BaseCreatedAt = &base.CreatedAt
+	}
+
+	previous := map[string]string{}
+	if base != nil {
+		for _, board := range base.Boards {
+			for _, card := range board.Cards {
+				previous[card.ID] = card.UpdatedDate
+			}
+		}
+	}
+
+	bw := &backupWriter{
+		tw:      tar.NewWriter(w),
+		modTime: manifest.CreatedAt,
+	}
+
+	boards, err := a.backupBoards(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, board := range boards {
+		var entry *BackupBoard
+		entry, err = a.backupBoard(ctx, bw, board, previous)
+		if err != nil {
+			err = fmt.Errorf("failed to backup board:%s err:%s", board.ID, err)
+			return
+		}
+		manifest.Boards = append(manifest.Boards, entry)
+	}
+
+	err = bw.writeJSON(backupManifestName, manifest)
+	if err != nil {
+		return
+	}
+
+	err = bw.tw.Close()
+
+	return
+}
+
+// backupBoards lists every active and archived board
+func (a *Glo) backupBoards(ctx context.Context) (boards []*Board, err error) {
+	seen := map[string]bool{}
+	for _, archived := range []bool{false, true} {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var page []*Board
+		page, err = a.AllBoards(archived, Fields(BoardFieldName))
+		if err != nil {
+			return
+		}
+		for _, board := range page {
+			if !seen[board.ID] {
+				seen[board.ID] = true
+				boards = append(boards, board)
+			}
+		}
+	}
+
+	return
+}
+
+func (a *Glo) backupBoard(
+	ctx context.Context,
+	bw *backupWriter,
+	listed *Board,
+	previous map[string]string,
+) (
+	entry *BackupBoard,
+	err error,
+) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	board, err := a.GetBoard(listed.ID)
+	if err != nil {
+		return
+	}
+
+	dir := path.Join("boards", board.ID)
+	entry = &BackupBoard{
+		ID:       board.ID,
+		Name:     board.Name,
+		Archived: board.ArchivedDate != "",
+		Path:     path.Join(dir, backupBoardName),
+	}
+
+	err = bw.writeJSON(entry.Path, board)
+	if err != nil {
+		return
+	}
+
+	seen := map[string]bool{}
+	for _, archived := range []bool{false, true} {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var cards []*Card
+		cards, err = a.AllCards(board.ID, archived)
+		if err != nil {
+			return
+		}
+
+		for _, card := range cards {
+			if seen[card.ID] {
+				continue
+			}
+			seen[card.ID] = true
+
+			cardEntry := &BackupCard{
+				ID:          card.ID,
+				UpdatedDate: card.UpdatedDate,
+				Archived:    card.ArchivedDate != "",
+			}
+			entry.Cards = append(entry.Cards, cardEntry)
+
+			if updated, ok := previous[card.ID]; ok && updated == card.UpdatedDate {
+				continue
+			}
+
+			err = a.backupCard(ctx, bw, path.Join(dir, "cards", card.ID), board.ID, card, cardEntry)
+			if err != nil {
+				err = fmt.Errorf("failed to backup card:%s err:%s", card.ID, err)
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func (a *Glo) backupCard(
+	ctx context.Context,
+	bw *backupWriter,
+	dir string,
+	boardID string,
+	card *Card,
+	entry *BackupCard,
+) (err error) {
+	if err = ctx.Err(); err != nil {
+		return
+	}
+
+	entry.Included = true
+	entry.Path = path.Join(dir, backupCardName)
+
+	err = bw.writeJSON(entry.Path, card)
+	if err != nil {
+		return
+	}
+
+	comments, err := a.AllComments(boardID, card.ID)
+	if err != nil {
+		return
+	}
+	if comments == nil {
+		comments = []*Comment{}
+	}
+	err = bw.writeJSON(path.Join(dir, backupCommentsName), comments)
+	if err != nil {
+		return
+	}
+
+	attachments, err := a.AllAttachments(boardID, card.ID)
+	if err != nil {
+		return
+	}
+	if attachments == nil {
+		attachments = []*Attachment{}
+	}
+	err = bw.writeJSON(path.Join(dir, backupAttachmentsName), attachments)
+	if err != nil {
+		return
+	}
+
+	for _, attachment := range attachments {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		attachmentEntry := &BackupAttachment{
+			ID:       attachment.ID,
+			Filename: attachment.Filename,
+			MimeType: attachment.MimeType,
+		}
+		entry.Attachments = append(entry.Attachments, attachmentEntry)
+
+		// a missing attachment should not prevent the rest of the backup
+		data, _, downloadErr := a.DownloadAttachment(boardID, card.ID, attachment.ID)
+		if downloadErr != nil {
+			attachmentEntry.Error = downloadErr.Error()
+			continue
+		}
+
+		filename := path.Base("/" + attachment.Filename)
+		if filename == "/" {
+			filename = attachment.ID
+		}
+		attachmentEntry.Path = path.Join(dir, "attachments", attachment.ID, filename)
+		err = bw.writeFile(attachmentEntry.Path, data)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,441 @@
+// Package boarddiff compares two snapshots of a board, reporting the
+// changes to its columns, labels, members and cards.
+//
+// Cards are matched by ID. A card missing from the newer snapshot is
+// reported as removed, snapshots captured without archived cards report
+// cards archived in between as removed. Changes to the position of a
+// card within its column are not reported.
+package boarddiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Kind a kind of change
+type Kind string
+
+// Kinds of change
+const (
+	ColumnAdded      Kind = "column_added"
+	ColumnRemoved    Kind = "column_removed"
+	ColumnRenamed    Kind = "column_renamed"
+	ColumnArchived   Kind = "column_archived"
+	ColumnUnarchived Kind = "column_unarchived"
+
+	LabelAdded     Kind = "label_added"
+	LabelRemoved   Kind = "label_removed"
+	LabelRenamed   Kind = "label_renamed"
+	LabelRecolored Kind = "label_recolored"
+
+	MemberAdded       Kind = "member_added"
+	MemberRemoved     Kind = "member_removed"
+	MemberRoleChanged Kind = "member_role_changed"
+
+	CardAdded              Kind = "card_added"
+	CardRemoved            Kind = "card_removed"
+	CardMoved              Kind = "card_moved"
+	CardRenamed            Kind = "card_renamed"
+	CardArchived           Kind = "card_archived"
+	CardUnarchived         Kind = "card_unarchived"
+	CardLabelsChanged      Kind = "card_labels_changed"
+	CardAssigneesChanged   Kind = "card_assignees_changed"
+	CardDueDateChanged     Kind = "card_due_date_changed"
+	CardDescriptionChanged Kind = "card_description_changed"
+	CardTasksChanged       Kind = "card_tasks_changed"
+	CardCommented          Kind = "card_commented"
+	CardAttachmentsChanged Kind = "card_attachments_changed"
+)
+
+// Change a change between the snapshots
+type Change struct {
+	Kind Kind `json:"kind"`
+
+	// ID and Name the changed column, label, member or card,
+	// Name is its name in the newer snapshot when it still exists
+	ID   string `json:"id"`
+	Name string `json:"name"`
+
+	// Column the name of the card's column
+	Column string `json:"column,omitempty"`
+
+	// From and To the previous and current values of a changed field
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+
+	// Added and Removed the labels or assignees added to
+	// and removed from a card
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+
+	// Lines the line diff of a card's description
+	Lines []Line `json:"lines,omitempty"`
+}
+
+// Report the changes between two snapshots of a board
+type Report struct {
+	BoardID string    `json:"board_id"`
+	Board   string    `json:"board"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Changes []*Change `json:"changes"`
+}
+
+// Diff compares two snapshots of the same board, changes are
+// ordered columns, labels and members then cards in board order
+func Diff(old, new *glo.BoardSnapshot) (report *Report, err error) {
+	if old.Board == nil || new.Board == nil {
+		err = fmt.Errorf("snapshots must include their board")
+		return
+	}
+	if old.Board.ID != "" && new.Board.ID != "" && old.Board.ID != new.Board.ID {
+		err = fmt.Errorf("snapshots are of different boards %s and %s", old.Board.ID, new.Board.ID)
+		return
+	}
+
+	d := &differ{old: old, new: new}
+	report = &Report{
+		BoardID: new.Board.ID,
+		Board:   new.Board.Name,
+		From:    old.CapturedAt,
+		To:      new.CapturedAt,
+	}
+
+	d.columns()
+	d.labels()
+	d.members()
+	d.cards()
+	report.Changes = d.changes
+	if report.Changes == nil {
+		report.Changes = []*Change{}
+	}
+
+	return
+}
+
+// Load reads a snapshot saved as JSON
+func Load(path string) (snap *glo.BoardSnapshot, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	snap = &glo.BoardSnapshot{}
+	err = json.Unmarshal(data, snap)
+	if err != nil {
+		err = fmt.Errorf("%s: %s", path, err)
+		return
+	}
+	if snap.Board == nil {
+		err = fmt.Errorf("%s: not a board snapshot", path)
+	}
+
+	return
+}
+
+type differ struct {
+	old, new *glo.BoardSnapshot
+	changes  []*Change
+}
+
+func (d *differ) add(change *Change) {
+	d.changes = append(d.changes, change)
+}
+
+func (d *differ) columns() {
+	archived := func(snap *glo.BoardSnapshot, col *glo.Column) bool {
+		if col.ArchivedDate != "" {
+			return true
+		}
+		for _, a := range snap.Board.ArchivedColumns {
+			if a.ID == col.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, col := range d.new.SortedColumns() {
+		prev := d.old.Column(col.ID)
+		if prev == nil {
+			d.add(&Change{Kind: ColumnAdded, ID: col.ID, Name: col.Name})
+			continue
+		}
+		if prev.Name != col.Name {
+			d.add(&Change{Kind: ColumnRenamed, ID: col.ID, Name: col.Name, From: prev.Name, To: col.Name})
+		}
+		was, is := archived(d.old, prev), archived(d.new, col)
+		if !was && is {
+			d.add(&Change{Kind: ColumnArchived, ID: col.ID, Name: col.Name})
+		}
+		if was && !is {
+			d.add(&Change{Kind: ColumnUnarchived, ID: col.ID, Name: col.Name})
+		}
+	}
+
+	for _, col := range d.old.SortedColumns() {
+		if d.new.Column(col.ID) == nil {
+			d.add(&Change{Kind: ColumnRemoved, ID: col.ID, Name: col.Name})
+		}
+	}
+}
+
+func (d *differ) labels() {
+	for _, label := range d.new.Board.Labels {
+		prev := d.old.Label(label.ID)
+		if prev == nil {
+			d.add(&Change{Kind: LabelAdded, ID: label.ID, Name: label.Name, To: label.Color.Hex()})
+			continue
+		}
+		if prev.Name != label.Name {
+			d.add(&Change{Kind: LabelRenamed, ID: label.ID, Name: label.Name, From: prev.Name, To: label.Name})
+		}
+		if prev.Color.Hex() != label.Color.Hex() {
+			d.add(&Change{
+				Kind: LabelRecolored,
+				ID:   label.ID,
+				Name: label.Name,
+				From: prev.Color.Hex(),
+				To:   label.Color.Hex(),
+			})
+		}
+	}
+
+	for _, label := range d.old.Board.Labels {
+		if d.new.Label(label.ID) == nil {
+			d.add(&Change{Kind: LabelRemoved, ID: label.ID, Name: label.Name})
+		}
+	}
+}
+
+func (d *differ) members() {
+	for _, member := range d.new.Board.Members {
+		prev := d.old.Member(member.ID)
+		if prev == nil {
+			d.add(&Change{Kind: MemberAdded, ID: member.ID, Name: memberName(member), To: member.Role})
+			continue
+		}
+		if prev.Role != member.Role {
+			d.add(&Change{
+				Kind: MemberRoleChanged,
+				ID:   member.ID,
+				Name: memberName(member),
+				From: prev.Role,
+				To:   member.Role,
+			})
+		}
+	}
+
+	for _, member := range d.old.Board.Members {
+		if d.new.Member(member.ID) == nil {
+			d.add(&Change{Kind: MemberRemoved, ID: member.ID, Name: memberName(member), From: member.Role})
+		}
+	}
+}
+
+func (d *differ) cards() {
+	old := map[string]*glo.Card{}
+	for _, card := range d.old.Cards {
+		old[card.ID] = card
+	}
+
+	seen := map[string]bool{}
+	for _, card := range ordered(d.new) {
+		seen[card.ID] = true
+		prev, ok := old[card.ID]
+		if !ok {
+			d.add(d.cardChange(CardAdded, d.new, card))
+			continue
+		}
+		d.card(prev, card)
+	}
+
+	for _, card := range ordered(d.old) {
+		if !seen[card.ID] {
+			d.add(d.cardChange(CardRemoved, d.old, card))
+		}
+	}
+}
+
+// card compares the fields of a card
+func (d *differ) card(prev, card *glo.Card) {
+	change := func(kind Kind) *Change {
+		c := d.cardChange(kind, d.new, card)
+		d.add(c)
+		return c
+	}
+
+	if prev.ColumnID != card.ColumnID {
+		c := change(CardMoved)
+		c.From, c.To = columnName(d.old, prev.ColumnID), c.Column
+	}
+	if prev.Name != card.Name {
+		c := change(CardRenamed)
+		c.From, c.To = prev.Name, card.Name
+	}
+	if prev.ArchivedDate == "" && card.ArchivedDate != "" {
+		change(CardArchived)
+	}
+	if prev.ArchivedDate != "" && card.ArchivedDate == "" {
+		change(CardUnarchived)
+	}
+
+	added, removed := compareSets(d.labelNames(d.old, prev), d.labelNames(d.new, card))
+	if len(added) > 0 || len(removed) > 0 {
+		c := change(CardLabelsChanged)
+		c.Added, c.Removed = added, removed
+	}
+
+	added, removed = compareSets(d.userNames(d.old, prev.Assignees), d.userNames(d.new, card.Assignees))
+	if len(added) > 0 || len(removed) > 0 {
+		c := change(CardAssigneesChanged)
+		c.Added, c.Removed = added, removed
+	}
+
+	if prev.DueDate != card.DueDate {
+		c := change(CardDueDateChanged)
+		c.From, c.To = prev.DueDate, card.DueDate
+	}
+
+	if before, after := description(prev), description(card); before != after {
+		c := change(CardDescriptionChanged)
+		c.Lines = LineDiff(before, after)
+	}
+
+	if prev.CompletedTaskCount != card.CompletedTaskCount || prev.TotalTaskCount != card.TotalTaskCount {
+		c := change(CardTasksChanged)
+		c.From = fmt.Sprintf("%d/%d", prev.CompletedTaskCount, prev.TotalTaskCount)
+		c.To = fmt.Sprintf("%d/%d", card.CompletedTaskCount, card.TotalTaskCount)
+	}
+
+	if prev.CommentCount != card.CommentCount {
+		c := change(CardCommented)
+		c.From, c.To = fmt.Sprint(prev.CommentCount), fmt.Sprint(card.CommentCount)
+	}
+
+	if prev.AttachmentCount != card.AttachmentCount {
+		c := change(CardAttachmentsChanged)
+		c.From, c.To = fmt.Sprint(prev.AttachmentCount), fmt.Sprint(card.AttachmentCount)
+	}
+}
+
+func (d *differ) cardChange(kind Kind, snap *glo.BoardSnapshot, card *glo.Card) *Change {
+	return &Change{
+		Kind:   kind,
+		ID:     card.ID,
+		Name:   card.Name,
+		Column: columnName(snap, card.ColumnID),
+	}
+}
+
+// labelNames resolves a card's labels to their names
+func (d *differ) labelNames(snap *glo.BoardSnapshot, card *glo.Card) []string {
+	var names []string
+	for _, partial := range card.Labels {
+		switch label := snap.Label(partial.ID); {
+		case label != nil:
+			names = append(names, label.Name)
+		case partial.Name != "":
+			names = append(names, partial.Name)
+		default:
+			names = append(names, partial.ID)
+		}
+	}
+
+	return names
+}
+
+// userNames resolves users to their usernames when they are board members
+func (d *differ) userNames(snap *glo.BoardSnapshot, users []*glo.PartialUser) []string {
+	var names []string
+	for _, user := range users {
+		if user == nil {
+			continue
+		}
+		if member := snap.Member(user.ID); member != nil {
+			names = append(names, memberName(member))
+			continue
+		}
+		names = append(names, user.ID)
+	}
+
+	return names
+}
+
+// ordered the snapshot's cards by column then position,
+// cards of unknown columns are last
+func ordered(snap *glo.BoardSnapshot) []*glo.Card {
+	var cards []*glo.Card
+	known := map[string]bool{}
+	for _, col := range snap.SortedColumns() {
+		known[col.ID] = true
+		cards = append(cards, snap.CardsByColumn(col.ID)...)
+	}
+
+	var orphans []*glo.Card
+	for _, card := range snap.Cards {
+		if !known[card.ColumnID] {
+			orphans = append(orphans, card)
+		}
+	}
+	sort.SliceStable(orphans, func(i, j int) bool {
+		return orphans[i].Position < orphans[j].Position
+	})
+
+	return append(cards, orphans...)
+}
+
+func columnName(snap *glo.BoardSnapshot, id string) string {
+	if col := snap.Column(id); col != nil {
+		return col.Name
+	}
+
+	return id
+}
+
+func memberName(member *glo.BoardMember) string {
+	if member.Username != "" {
+		return member.Username
+	}
+
+	return member.ID
+}
+
+func description(card *glo.Card) string {
+	if card.Description == nil {
+		return ""
+	}
+
+	return strings.TrimRight(card.Description.Text, "\n")
+}
+
+// compareSets the values added to and removed from a set
+func compareSets(before, after []string) (added, removed []string) {
+	in := func(values []string, v string) bool {
+		for _, value := range values {
+			if value == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, v := range after {
+		if !in(before, v) {
+			added = append(added, v)
+		}
+	}
+	for _, v := range before {
+		if !in(after, v) {
+			removed = append(removed, v)
+		}
+	}
+
+	return
+}
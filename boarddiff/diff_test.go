@@ -0,0 +1,317 @@
+package boarddiff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// testSnapshot a board with two columns, two labels, two members and three cards
+func testSnapshot() *glo.BoardSnapshot {
+	return &glo.BoardSnapshot{
+		CapturedAt: time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC),
+		Board: &glo.Board{
+			ID:   "b1",
+			Name: "Team",
+			Columns: []*glo.Column{
+				{ID: "c1", Name: "To Do"},
+				{ID: "c2", Name: "Done", Position: 1},
+			},
+			Labels: []*glo.Label{
+				{ID: "l1", Name: "bug", Color: glo.Color{R: 255, A: 1}},
+				{ID: "l2", Name: "ui", Color: glo.Color{B: 255, A: 1}},
+			},
+			Members: []*glo.BoardMember{
+				{ID: "u1", Username: "alice", Role: "owner"},
+				{ID: "u2", Username: "bob", Role: "member"},
+			},
+		},
+		Cards: []*glo.Card{
+			{
+				ID:          "k1",
+				Name:        "Login",
+				ColumnID:    "c1",
+				Labels:      []*glo.PartialLabel{{ID: "l1"}},
+				Assignees:   []*glo.PartialUser{{ID: "u1"}},
+				Description: &glo.Description{Text: "- [ ] form\n- [ ] submit\n"},
+			},
+			{ID: "k2", Name: "Logout", ColumnID: "c1", Position: 1},
+			{ID: "k3", Name: "Setup", ColumnID: "c2"},
+		},
+	}
+}
+
+// clone a deep copy of a snapshot
+func clone(snap *glo.BoardSnapshot) *glo.BoardSnapshot {
+	data, _ := json.Marshal(snap)
+	copied := &glo.BoardSnapshot{}
+	json.Unmarshal(data, copied)
+
+	return copied
+}
+
+func card(snap *glo.BoardSnapshot, id string) *glo.Card {
+	for _, card := range snap.Cards {
+		if card.ID == id {
+			return card
+		}
+	}
+
+	return nil
+}
+
+// summarize formats changes as their kind, ID and changed values
+func summarize(changes []*Change) []string {
+	var out []string
+	for _, c := range changes {
+		s := fmt.Sprintf("%s %s", c.Kind, c.ID)
+		if c.From != "" || c.To != "" {
+			s += fmt.Sprintf(" %s>%s", c.From, c.To)
+		}
+		if len(c.Added) > 0 || len(c.Removed) > 0 {
+			s += fmt.Sprintf(" +%v -%v", c.Added, c.Removed)
+		}
+		out = append(out, s)
+	}
+
+	return out
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(snap *glo.BoardSnapshot)
+		want   []string
+	}{
+		{
+			name:   "unchanged",
+			change: func(snap *glo.BoardSnapshot) {},
+		},
+		{
+			name: "position only",
+			change: func(snap *glo.BoardSnapshot) {
+				card(snap, "k1").Position, card(snap, "k2").Position = 1, 0
+			},
+		},
+		{
+			name: "columns",
+			change: func(snap *glo.BoardSnapshot) {
+				snap.Board.Columns[0].Name = "Backlog"
+				snap.Board.ArchivedColumns = []*glo.Column{snap.Board.Columns[1]}
+				snap.Board.Columns = []*glo.Column{snap.Board.Columns[0], {ID: "c3", Name: "Review", Position: 2}}
+			},
+			want: []string{
+				"column_renamed c1 To Do>Backlog",
+				"column_added c3",
+				"column_archived c2",
+			},
+		},
+		{
+			name: "column removed",
+			change: func(snap *glo.BoardSnapshot) {
+				snap.Board.Columns = snap.Board.Columns[:1]
+				snap.Cards = snap.Cards[:2]
+			},
+			want: []string{"column_removed c2", "card_removed k3"},
+		},
+		{
+			name: "labels",
+			change: func(snap *glo.BoardSnapshot) {
+				snap.Board.Labels[0].Name = "defect"
+				snap.Board.Labels[1].Color = glo.Color{G: 255, A: 1}
+				snap.Board.Labels = append(snap.Board.Labels[:2:2], &glo.Label{ID: "l3", Name: "docs", Color: glo.Color{A: 0.5}})
+			},
+			want: []string{
+				"label_renamed l1 bug>defect",
+				"label_recolored l2 #0000ff>#00ff00",
+				"label_added l3 >#00000080",
+				"card_labels_changed k1 +[defect] -[bug]",
+			},
+		},
+		{
+			name: "members",
+			change: func(snap *glo.BoardSnapshot) {
+				snap.Board.Members = []*glo.BoardMember{
+					{ID: "u1", Username: "alice", Role: "member"},
+					{ID: "u3", Username: "carol", Role: "member"},
+				}
+			},
+			want: []string{
+				"member_role_changed u1 owner>member",
+				"member_added u3 >member",
+				"member_removed u2 member>",
+			},
+		},
+		{
+			name: "card fields",
+			change: func(snap *glo.BoardSnapshot) {
+				k1 := card(snap, "k1")
+				k1.ColumnID = "c2"
+				k1.Name = "Login form"
+				k1.Labels = append(k1.Labels, &glo.PartialLabel{ID: "l2"})
+				k1.Assignees = []*glo.PartialUser{{ID: "u2"}, {ID: "u9"}}
+				k1.DueDate = "2019-07-01T00:00:00Z"
+				k1.CompletedTaskCount, k1.TotalTaskCount = 1, 2
+				k1.CommentCount = 2
+				k1.AttachmentCount = 1
+			},
+			want: []string{
+				"card_moved k1 To Do>Done",
+				"card_renamed k1 Login>Login form",
+				"card_labels_changed k1 +[ui] -[]",
+				"card_assignees_changed k1 +[bob u9] -[alice]",
+				"card_due_date_changed k1 >2019-07-01T00:00:00Z",
+				"card_tasks_changed k1 0/0>1/2",
+				"card_commented k1 0>2",
+				"card_attachments_changed k1 0>1",
+			},
+		},
+		{
+			name: "card description",
+			change: func(snap *glo.BoardSnapshot) {
+				card(snap, "k1").Description.Text = "- [x] form\n- [ ] submit"
+				card(snap, "k2").Description = &glo.Description{Text: "\n"}
+			},
+			want: []string{"card_description_changed k1"},
+		},
+		{
+			name: "cards added, removed and archived",
+			change: func(snap *glo.BoardSnapshot) {
+				snap.Cards = []*glo.Card{
+					{ID: "k4", Name: "New", ColumnID: "c2", Position: 1},
+					card(snap, "k1"),
+					card(snap, "k3"),
+					{ID: "k5", Name: "Orphan", ColumnID: "c9"},
+				}
+				card(snap, "k3").ArchivedDate = "2019-06-02T00:00:00Z"
+			},
+			want: []string{
+				"card_archived k3",
+				"card_added k4",
+				"card_added k5",
+				"card_removed k2",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		old := testSnapshot()
+		new := clone(old)
+		new.CapturedAt = old.CapturedAt.Add(24 * time.Hour)
+		test.change(new)
+
+		report, err := Diff(old, new)
+		if err != nil {
+			t.Errorf("%s: Diff err:%s", test.name, err)
+			continue
+		}
+		if got := summarize(report.Changes); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: Diff =\n%s\nwant\n%s", test.name, strings.Join(got, "\n"), strings.Join(test.want, "\n"))
+		}
+		if report.BoardID != "b1" || !report.From.Equal(old.CapturedAt) || !report.To.Equal(new.CapturedAt) {
+			t.Errorf("%s: report of %s from %s to %s", test.name, report.BoardID, report.From, report.To)
+		}
+	}
+}
+
+func TestDiffDescriptionLines(t *testing.T) {
+	old := testSnapshot()
+	new := clone(old)
+	card(new, "k1").Description.Text = "- [x] form\n- [ ] submit"
+
+	report, err := Diff(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"-- [ ] form", "+- [x] form", " - [ ] submit"}
+	if got := ops(report.Changes[0].Lines); !reflect.DeepEqual(got, want) {
+		t.Errorf("description lines = %q, want %q", got, want)
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	other := testSnapshot()
+	other.Board.ID = "b2"
+
+	tests := []struct {
+		name     string
+		old, new *glo.BoardSnapshot
+	}{
+		{"no old board", &glo.BoardSnapshot{}, testSnapshot()},
+		{"no new board", testSnapshot(), &glo.BoardSnapshot{}},
+		{"different boards", testSnapshot(), other},
+	}
+
+	for _, test := range tests {
+		if _, err := Diff(test.old, test.new); err == nil {
+			t.Errorf("%s: Diff did not fail", test.name)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	snap := testSnapshot()
+	data, _ := json.Marshal(snap)
+	path := filepath.Join(dir, "snap.json")
+	ioutil.WriteFile(path, data, 0600)
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := Diff(snap, loaded)
+	if err != nil || len(report.Changes) != 0 {
+		t.Errorf("a loaded snapshot differs from the saved one: %v err:%v", summarize(report.Changes), err)
+	}
+
+	notSnapshot := filepath.Join(dir, "cards.json")
+	ioutil.WriteFile(notSnapshot, []byte(`[{"id":"k1"}]`), 0600)
+	if _, err = Load(notSnapshot); err == nil {
+		t.Error("a file which is not a snapshot was loaded")
+	}
+
+	empty := filepath.Join(dir, "empty.json")
+	ioutil.WriteFile(empty, []byte(`{}`), 0600)
+	if _, err = Load(empty); err == nil {
+		t.Error("a snapshot without a board was loaded")
+	}
+}
+
+func TestRender(t *testing.T) {
+	old := testSnapshot()
+	new := clone(old)
+	card(new, "k1").ColumnID = "c2"
+	card(new, "k1").Description.Text = "- [x] form\n- [ ] submit"
+
+	report, err := Diff(old, new)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, render := range map[string]func(*bytes.Buffer) error{
+		"text":     func(b *bytes.Buffer) error { return Text(b, report) },
+		"markdown": func(b *bytes.Buffer) error { return Markdown(b, report) },
+		"json":     func(b *bytes.Buffer) error { return JSON(b, report) },
+	} {
+		b := &bytes.Buffer{}
+		if err := render(b); err != nil {
+			t.Errorf("%s err:%s", name, err)
+			continue
+		}
+		for _, want := range []string{"Login", "Done", "[x] form"} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("%s does not contain %q:\n%s", name, want, b.String())
+			}
+		}
+	}
+}
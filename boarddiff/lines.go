@@ -0,0 +1,139 @@
+package boarddiff
+
+import (
+	"strings"
+)
+
+// Line operations
+const (
+	LineEqual   = " "
+	LineDeleted = "-"
+	LineAdded   = "+"
+)
+
+// Line a line of a text diff
+type Line struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxDiffCells bounds the work of comparing two texts, longer
+// texts which differ throughout are reported as replaced
+const maxDiffCells = 4 << 20
+
+// LineDiff compares two texts line by line, returning
+// every line of both marked as equal, deleted or added
+func LineDiff(before, after string) []Line {
+	a, b := splitLines(before), splitLines(after)
+
+	// common prefix and suffix need not be compared
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var lines []Line
+	for _, text := range a[:prefix] {
+		lines = append(lines, Line{Op: LineEqual, Text: text})
+	}
+	lines = append(lines, lcsDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, text := range a[len(a)-suffix:] {
+		lines = append(lines, Line{Op: LineEqual, Text: text})
+	}
+
+	return lines
+}
+
+// lcsDiff diffs lines using their longest common subsequence
+func lcsDiff(a, b []string) (lines []Line) {
+	if len(a)*len(b) > maxDiffCells {
+		for _, text := range a {
+			lines = append(lines, Line{Op: LineDeleted, Text: text})
+		}
+		for _, text := range b {
+			lines = append(lines, Line{Op: LineAdded, Text: text})
+		}
+		return
+	}
+
+	// lcs[i][j] the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, Line{Op: LineEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, Line{Op: LineDeleted, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, Line{Op: LineAdded, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, Line{Op: LineDeleted, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, Line{Op: LineAdded, Text: b[j]})
+	}
+
+	return
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// hunks the changed lines of a diff with up to context
+// equal lines around them, nil separates hunks
+func hunks(lines []Line, context int) (shown []*Line) {
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if line.Op == LineEqual {
+			continue
+		}
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(lines) {
+				keep[j] = true
+			}
+		}
+	}
+
+	for i := range lines {
+		if !keep[i] {
+			continue
+		}
+		if len(shown) > 0 && i > 0 && !keep[i-1] {
+			shown = append(shown, nil)
+		}
+		shown = append(shown, &lines[i])
+	}
+
+	return
+}
@@ -0,0 +1,126 @@
+package boarddiff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// ops formats a diff as one op and text per line
+func ops(lines []Line) []string {
+	var out []string
+	for _, line := range lines {
+		out = append(out, line.Op+line.Text)
+	}
+
+	return out
+}
+
+func TestLineDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after string
+		want          []string
+	}{
+		{"both empty", "", "", nil},
+		{"equal", "a\nb", "a\nb", []string{" a", " b"}},
+		{"added to empty", "", "a\nb", []string{"+a", "+b"}},
+		{"emptied", "a\nb", "", []string{"-a", "-b"}},
+		{"appended", "a\nb", "a\nb\nc", []string{" a", " b", "+c"}},
+		{"prepended", "b\nc", "a\nb\nc", []string{"+a", " b", " c"}},
+		{"removed middle", "a\nb\nc", "a\nc", []string{" a", "-b", " c"}},
+		{"replaced middle", "a\nb\nc", "a\nx\nc", []string{" a", "-b", "+x", " c"}},
+		{"replaced all", "a\nb", "x\ny", []string{"-a", "-b", "+x", "+y"}},
+		{"moved line", "a\nb\nc", "b\nc\na", []string{"-a", " b", " c", "+a"}},
+		{
+			"interleaved",
+			"a\nb\nc\nd\ne",
+			"a\nc\nx\ne\nf",
+			[]string{" a", "-b", " c", "-d", "+x", " e", "+f"},
+		},
+		{"windows line endings", "a\r\nb", "a\nc", []string{" a", "-b", "+c"}},
+		{"trailing newline", "a", "a\n", []string{" a", "+"}},
+		{
+			"task checked",
+			"- [ ] write\n- [ ] test",
+			"- [x] write\n- [ ] test",
+			[]string{"-- [ ] write", "+- [x] write", " - [ ] test"},
+		},
+	}
+
+	for _, test := range tests {
+		if got := ops(LineDiff(test.before, test.after)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: LineDiff = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+// TestLineDiffReconstructs checks the equal and deleted lines of a diff
+// form the old text and the equal and added lines the new text
+func TestLineDiffReconstructs(t *testing.T) {
+	texts := []string{
+		"",
+		"a",
+		"a\nb\nc\nd",
+		"d\nc\nb\na",
+		"a\na\nb\nb\na",
+		"x\na\ny\nb\nz",
+	}
+
+	for _, before := range texts {
+		for _, after := range texts {
+			var old, new []string
+			for _, line := range LineDiff(before, after) {
+				if line.Op != LineAdded {
+					old = append(old, line.Text)
+				}
+				if line.Op != LineDeleted {
+					new = append(new, line.Text)
+				}
+			}
+			if strings.Join(old, "\n") != before || strings.Join(new, "\n") != after {
+				t.Errorf("LineDiff(%q, %q) does not reconstruct the texts", before, after)
+			}
+		}
+	}
+}
+
+func TestLineDiffLimit(t *testing.T) {
+	var before, after []string
+	for i := 0; i < 3000; i++ {
+		before = append(before, "old")
+		after = append(after, "new")
+	}
+
+	lines := LineDiff(strings.Join(before, "\n"), strings.Join(after, "\n"))
+	if len(lines) != 6000 || lines[0].Op != LineDeleted || lines[5999].Op != LineAdded {
+		t.Errorf("a large replaced text was not reported as replaced")
+	}
+}
+
+func TestHunks(t *testing.T) {
+	lines := LineDiff("1\n2\n3\n4\n5\n6\n7\n8\n9", "1\nx\n3\n4\n5\n6\n7\n8\ny")
+
+	tests := []struct {
+		context int
+		want    []string
+	}{
+		{0, []string{"-2", "+x", "", "-9", "+y"}},
+		{1, []string{" 1", "-2", "+x", " 3", "", " 8", "-9", "+y"}},
+		{3, []string{" 1", "-2", "+x", " 3", " 4", " 5", " 6", " 7", " 8", "-9", "+y"}},
+	}
+
+	for _, test := range tests {
+		var got []string
+		for _, line := range hunks(lines, test.context) {
+			if line == nil {
+				got = append(got, "")
+				continue
+			}
+			got = append(got, line.Op+line.Text)
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("hunks with context %d = %q, want %q", test.context, got, test.want)
+		}
+	}
+}
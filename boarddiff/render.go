@@ -0,0 +1,199 @@
+package boarddiff
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// descriptionContext the equal lines shown around description changes
+const descriptionContext = 2
+
+// Summary describes a change in a single line,
+// quote formats the names of items
+func (c *Change) Summary(quote func(string) string) string {
+	name := quote(c.Name)
+
+	switch c.Kind {
+	case ColumnAdded:
+		return "column " + name + " added"
+	case ColumnRemoved:
+		return "column " + name + " removed"
+	case ColumnRenamed:
+		return fmt.Sprintf("column %s renamed from %s", name, quote(c.From))
+	case ColumnArchived:
+		return "column " + name + " archived"
+	case ColumnUnarchived:
+		return "column " + name + " unarchived"
+
+	case LabelAdded:
+		return fmt.Sprintf("label %s added (%s)", name, c.To)
+	case LabelRemoved:
+		return "label " + name + " removed"
+	case LabelRenamed:
+		return fmt.Sprintf("label %s renamed from %s", name, quote(c.From))
+	case LabelRecolored:
+		return fmt.Sprintf("label %s recolored %s → %s", name, c.From, c.To)
+
+	case MemberAdded:
+		return fmt.Sprintf("member %s added as %s", name, c.To)
+	case MemberRemoved:
+		return "member " + name + " removed"
+	case MemberRoleChanged:
+		return fmt.Sprintf("member %s changed from %s to %s", name, c.From, c.To)
+
+	case CardAdded:
+		return fmt.Sprintf("card %s added to %s", name, quote(c.Column))
+	case CardRemoved:
+		return fmt.Sprintf("card %s removed from %s", name, quote(c.Column))
+	case CardMoved:
+		return fmt.Sprintf("card %s moved %s → %s", name, quote(c.From), quote(c.To))
+	case CardRenamed:
+		return fmt.Sprintf("card %s renamed from %s", name, quote(c.From))
+	case CardArchived:
+		return "card " + name + " archived"
+	case CardUnarchived:
+		return "card " + name + " unarchived"
+	case CardLabelsChanged:
+		return "card " + name + " labels " + setChanges(c, quote)
+	case CardAssigneesChanged:
+		return "card " + name + " assignees " + setChanges(c, quote)
+	case CardDueDateChanged:
+		return fmt.Sprintf("card %s due %s → %s", name, orNone(c.From), orNone(c.To))
+	case CardDescriptionChanged:
+		return "card " + name + " description edited"
+	case CardTasksChanged:
+		return fmt.Sprintf("card %s tasks %s → %s", name, c.From, c.To)
+	case CardCommented:
+		return fmt.Sprintf("card %s comments %s → %s", name, c.From, c.To)
+	case CardAttachmentsChanged:
+		return fmt.Sprintf("card %s attachments %s → %s", name, c.From, c.To)
+	}
+
+	return fmt.Sprintf("%s %s", c.Kind, name)
+}
+
+func setChanges(c *Change, quote func(string) string) string {
+	var parts []string
+	for _, v := range c.Added {
+		parts = append(parts, "+"+quote(v))
+	}
+	for _, v := range c.Removed {
+		parts = append(parts, "-"+quote(v))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func orNone(v string) string {
+	if v == "" {
+		return "(none)"
+	}
+
+	return v
+}
+
+// Text writes a change per line, description
+// edits are followed by their changed lines
+func Text(w io.Writer, report *Report) error {
+	bw := bufio.NewWriter(w)
+	quote := func(s string) string {
+		return fmt.Sprintf("%q", s)
+	}
+
+	fmt.Fprintf(
+		bw,
+		"%s: %d changes from %s to %s\n",
+		report.Board,
+		len(report.Changes),
+		report.From.Format("2006-01-02 15:04 MST"),
+		report.To.Format("2006-01-02 15:04 MST"),
+	)
+	for _, change := range report.Changes {
+		fmt.Fprintln(bw, change.Summary(quote))
+		for _, line := range hunks(change.Lines, descriptionContext) {
+			if line == nil {
+				fmt.Fprintln(bw, "    ...")
+				continue
+			}
+			fmt.Fprintf(bw, "    %s %s\n", line.Op, line.Text)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// Markdown writes a change report with a section for the board's
+// structure and its cards, description edits are shown as diff blocks
+func Markdown(w io.Writer, report *Report) error {
+	bw := bufio.NewWriter(w)
+	quote := func(s string) string {
+		return "**" + strings.TrimSpace(s) + "**"
+	}
+
+	fmt.Fprintf(bw, "# Changes to %s\n\n", report.Board)
+	fmt.Fprintf(
+		bw,
+		"_%s to %s_\n",
+		report.From.Format("2006-01-02 15:04 MST"),
+		report.To.Format("2006-01-02 15:04 MST"),
+	)
+
+	if len(report.Changes) == 0 {
+		fmt.Fprintf(bw, "\n_No changes_\n")
+		return bw.Flush()
+	}
+
+	var board, cards []*Change
+	for _, change := range report.Changes {
+		if strings.HasPrefix(string(change.Kind), "card_") {
+			cards = append(cards, change)
+			continue
+		}
+		board = append(board, change)
+	}
+
+	for _, section := range []struct {
+		heading string
+		changes []*Change
+	}{
+		{"Board", board},
+		{"Cards", cards},
+	} {
+		if len(section.changes) == 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "\n## %s\n\n", section.heading)
+
+		for _, change := range section.changes {
+			fmt.Fprintf(bw, "- %s\n", change.Summary(quote))
+
+			shown := hunks(change.Lines, descriptionContext)
+			if len(shown) == 0 {
+				continue
+			}
+			fmt.Fprintf(bw, "\n  ```diff\n")
+			for _, line := range shown {
+				if line == nil {
+					fmt.Fprintf(bw, "  ...\n")
+					continue
+				}
+				fmt.Fprintf(bw, "  %s%s\n", line.Op, line.Text)
+			}
+			fmt.Fprintf(bw, "  ```\n\n")
+		}
+	}
+
+	return bw.Flush()
+}
+
+// JSON writes the report as indented JSON, description
+// edits include every line of both descriptions
+func JSON(w io.Writer, report *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(report)
+}
@@ -105,13 +105,17 @@ func (a *Glo) GetBoards(
 	limit int,
 	sortDesc bool,
 	archived bool,
+	opts ...CallOption,
 ) (
 	boardsResp *BoardsResp,
 	err error,
 ) {
 	addr := fmt.Sprintf("%s/boards", a.BaseURI)
 
-	q := utils.AddFields(boardFields)
+	q, err := fieldsQuery(boardResource, boardFields, opts)
+	if err != nil {
+		return
+	}
 	q.Set("page", fmt.Sprint(page))
 	q.Set("per_page", fmt.Sprint(limit))
 
@@ -147,13 +151,17 @@ func (a *Glo) GetBoards(
 // https://gloapi.gitkraken.com/v1/docs/#/Boards/get_boards__board_id_
 func (a *Glo) GetBoard(
 	boardID string,
+	opts ...CallOption,
 ) (
 	board *Board,
 	err error,
 ) {
 	addr := fmt.Sprintf("%s/boards/%s", a.BaseURI, boardID)
 
-	q := utils.AddFields(boardFields)
+	q, err := fieldsQuery(boardResource, boardFields, opts)
+	if err != nil {
+		return
+	}
 
 	resp, _, err := a.jsonReq(http.MethodGet, addr, nil, q)
 	if err != nil {
@@ -0,0 +1,80 @@
+package boardspec
+
+import (
+	"fmt"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Apply applies a plan's actions in order, manual actions are skipped.
+//
+// Plans are computed against the live board so applying the
+// same spec again results in an empty plan.
+func Apply(client *glo.Glo, plan *Plan) (err error) {
+	for _, action := range plan.Actions {
+		err = apply(client, plan, action)
+		if err != nil {
+			err = fmt.Errorf("failed to apply %s err:%s", action, err)
+			return
+		}
+	}
+
+	return
+}
+
+func apply(client *glo.Glo, plan *Plan, action *Action) (err error) {
+	if action.Op == OpManual {
+		return
+	}
+	if action.Kind != KindBoard && plan.BoardID == "" {
+		return fmt.Errorf("board has not been created")
+	}
+
+	switch action.Kind {
+	case KindBoard:
+		var board *glo.Board
+		if action.Op == OpCreate {
+			board, err = client.CreateBoard(action.board)
+		} else {
+			board, err = client.EditBoard(action.ID, action.board)
+		}
+		if err != nil {
+			return
+		}
+		action.ID = board.ID
+		plan.BoardID = board.ID
+
+	case KindColumn:
+		switch action.Op {
+		case OpCreate:
+			var col *glo.Column
+			col, err = client.CreateColumn(plan.BoardID, action.column)
+			if err == nil {
+				action.ID = col.ID
+			}
+		case OpUpdate:
+			_, err = client.EditColumn(plan.BoardID, action.ID, action.column)
+		case OpDelete:
+			err = client.DeteleColumn(plan.BoardID, action.ID)
+		}
+
+	case KindLabel:
+		switch action.Op {
+		case OpCreate:
+			var label *glo.Label
+			label, err = client.CreateLabel(plan.BoardID, action.label)
+			if err == nil {
+				action.ID = label.ID
+			}
+		case OpUpdate:
+			_, err = client.EditLabel(plan.BoardID, action.ID, action.label)
+		case OpDelete:
+			err = client.DeleteLabel(plan.BoardID, action.ID)
+		}
+
+	default:
+		err = fmt.Errorf("unsupported action kind:%s", action.Kind)
+	}
+
+	return
+}
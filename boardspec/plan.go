@@ -0,0 +1,496 @@
+package boardspec
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Operations performed by plan actions
+const (
+	OpCreate = "create"
+	OpUpdate = "update"
+	OpDelete = "delete"
+
+	// OpManual changes which cannot be made through the API,
+	// they are reported but never applied
+	OpManual = "manual"
+)
+
+// Kinds of objects changed by plan actions
+const (
+	KindBoard  = "board"
+	KindColumn = "column"
+	KindLabel  = "label"
+	KindMember = "member"
+)
+
+// Options control how a plan is computed
+type Options struct {
+	// Prune deletes columns and labels, and reports members,
+	// which are not part of the spec
+	Prune bool
+}
+
+// Action a single change required to match the spec
+type Action struct {
+	Op     string `json:"op"`
+	Kind   string `json:"kind"`
+	ID     string `json:"id,omitempty"`
+	Name   string `json:"name"`
+	Detail string `json:"detail,omitempty"`
+
+	board  *glo.BoardInput
+	column *glo.ColumnInput
+	label  *glo.LabelInput
+}
+
+// Plan the changes required for a live board to match a spec
+type Plan struct {
+	// BoardID empty when the board is yet to be created
+	BoardID string    `json:"board_id,omitempty"`
+	Actions []*Action `json:"actions"`
+}
+
+// Find resolves the live board managed by the spec, by its ID or
+// otherwise its name, nil is returned when the board does not exist
+func Find(client *glo.Glo, spec *Spec) (board *glo.Board, err error) {
+	if spec.ID != "" {
+		return client.GetBoard(spec.ID)
+	}
+
+	for page := 1; ; page++ {
+		resp, listErr := client.GetBoards(page, 100, false, false, glo.Fields(glo.BoardFieldName))
+		if listErr != nil {
+			err = listErr
+			return
+		}
+
+		for _, b := range resp.Boards {
+			if b.Name != spec.Name {
+				continue
+			}
+			if board != nil {
+				err = fmt.Errorf("more than one board is named %q, set the board id in the spec", spec.Name)
+				return
+			}
+			board = b
+		}
+
+		if !resp.HasMore {
+			break
+		}
+	}
+
+	if board == nil {
+		return
+	}
+
+	return client.GetBoard(board.ID)
+}
+
+// Compute computes the plan for the live board to match the spec,
+// board is nil when the board does not exist yet
+func Compute(spec *Spec, board *glo.Board, opts *Options) (plan *Plan, err error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if err = spec.Validate(); err != nil {
+		return
+	}
+	if board == nil {
+		board = &glo.Board{}
+	}
+
+	plan = &Plan{BoardID: board.ID}
+
+	switch {
+	case board.ID == "":
+		plan.add(&Action{
+			Op:    OpCreate,
+			Kind:  KindBoard,
+			Name:  spec.Name,
+			board: &glo.BoardInput{Name: spec.Name},
+		})
+	case board.Name != spec.Name:
+		plan.add(&Action{
+			Op:     OpUpdate,
+			Kind:   KindBoard,
+			ID:     board.ID,
+			Name:   spec.Name,
+			Detail: fmt.Sprintf("rename from %q", board.Name),
+			board:  &glo.BoardInput{Name: spec.Name},
+		})
+	}
+
+	planColumns(plan, spec, board, opts)
+
+	err = planLabels(plan, spec, board, opts)
+	if err != nil {
+		return
+	}
+
+	planMembers(plan, spec, board, opts)
+
+	return
+}
+
+func (p *Plan) add(action *Action) {
+	p.Actions = append(p.Actions, action)
+}
+
+// planColumns matches spec columns to live columns and orders them
+// relative to one another, columns which are not part of the spec
+// keep their place unless they are pruned. Positions are simulated
+// against the board as each action is applied, so that applying
+// the plan leaves every managed column in spec order.
+func planColumns(plan *Plan, spec *Spec, board *glo.Board, opts *Options) {
+	live := append([]*glo.Column{}, board.Columns...)
+	sort.SliceStable(live, func(i, j int) bool {
+		return live[i].Position < live[j].Position
+	})
+
+	matched := map[string]bool{}
+	matches := make([]*glo.Column, len(spec.Columns))
+	for i, want := range spec.Columns {
+		for _, col := range live {
+			if matched[col.ID] {
+				continue
+			}
+			if (want.ID != "" && col.ID == want.ID) || (want.ID == "" && col.Name == want.Name) {
+				matches[i] = col
+				matched[col.ID] = true
+				break
+			}
+		}
+	}
+
+	// pruned columns are deleted first so that they
+	// do not affect the positions of the others
+	var order []string
+	for _, col := range live {
+		if matched[col.ID] {
+			order = append(order, col.ID)
+			continue
+		}
+		if !opts.Prune {
+			order = append(order, col.ID)
+			continue
+		}
+		plan.add(&Action{
+			Op:   OpDelete,
+			Kind: KindColumn,
+			ID:   col.ID,
+			Name: col.Name,
+		})
+	}
+
+	anchored := inOrder(matches, order)
+
+	for i, want := range spec.Columns {
+		col := matches[i]
+
+		var changes []string
+		if col != nil && col.Name != want.Name {
+			changes = append(changes, fmt.Sprintf("rename from %q", col.Name))
+		}
+
+		position := indexOf(order, columnKey(col, i))
+		if !anchored[i] {
+			if position >= 0 {
+				order = append(order[:position], order[position+1:]...)
+			}
+			position = 0
+			if i > 0 {
+				position = indexOf(order, columnKey(matches[i-1], i-1)) + 1
+			} else {
+				for j := range spec.Columns {
+					if anchored[j] {
+						position = indexOf(order, columnKey(matches[j], j))
+						break
+					}
+				}
+			}
+			order = append(order[:position], append([]string{columnKey(col, i)}, order[position:]...)...)
+			if col != nil {
+				changes = append(changes, fmt.Sprintf("move to position %d", position))
+			}
+		}
+
+		input := &glo.ColumnInput{Name: want.Name, Position: position}
+		if col == nil {
+			plan.add(&Action{
+				Op:     OpCreate,
+				Kind:   KindColumn,
+				Name:   want.Name,
+				Detail: fmt.Sprintf("at position %d", position),
+				column: input,
+			})
+			continue
+		}
+		if len(changes) > 0 {
+			plan.add(&Action{
+				Op:     OpUpdate,
+				Kind:   KindColumn,
+				ID:     col.ID,
+				Name:   want.Name,
+				Detail: strings.Join(changes, ", "),
+				column: input,
+			})
+		}
+	}
+}
+
+// inOrder the matched spec columns which can keep their place, the
+// longest run of columns already in spec order on the live board
+func inOrder(matches []*glo.Column, order []string) (anchored map[int]bool) {
+	var specIndexes, livePositions []int
+	for i, col := range matches {
+		if col != nil {
+			specIndexes = append(specIndexes, i)
+			livePositions = append(livePositions, indexOf(order, col.ID))
+		}
+	}
+
+	// longest increasing subsequence of live positions
+	lengths := make([]int, len(livePositions))
+	prev := make([]int, len(livePositions))
+	best := -1
+	for i := range livePositions {
+		lengths[i], prev[i] = 1, -1
+		for j := 0; j < i; j++ {
+			if livePositions[j] < livePositions[i] && lengths[j]+1 > lengths[i] {
+				lengths[i], prev[i] = lengths[j]+1, j
+			}
+		}
+		if best < 0 || lengths[i] > lengths[best] {
+			best = i
+		}
+	}
+
+	anchored = map[int]bool{}
+	for i := best; i >= 0; i = prev[i] {
+		anchored[specIndexes[i]] = true
+	}
+
+	return
+}
+
+// columnKey identifies a spec column within the simulated
+// board order, columns yet to be created have no ID
+func columnKey(col *glo.Column, specIndex int) string {
+	if col != nil {
+		return col.ID
+	}
+
+	return fmt.Sprintf("new column %d", specIndex)
+}
+
+func indexOf(order []string, key string) int {
+	for i, k := range order {
+		if k == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func planLabels(plan *Plan, spec *Spec, board *glo.Board, opts *Options) (err error) {
+	matched := map[string]bool{}
+
+	for _, want := range spec.Labels {
+		color, colorErr := ParseColor(want.Color)
+		if colorErr != nil {
+			return colorErr
+		}
+
+		var live *glo.Label
+		for _, label := range board.Labels {
+			if matched[label.ID] {
+				continue
+			}
+			if (want.ID != "" && label.ID == want.ID) || (want.ID == "" && label.Name == want.Name) {
+				live = label
+				break
+			}
+		}
+
+		input := &glo.LabelInput{Name: want.Name, Color: color}
+		if live == nil {
+			plan.add(&Action{
+				Op:     OpCreate,
+				Kind:   KindLabel,
+				Name:   want.Name,
+				Detail: FormatColor(color),
+				label:  input,
+			})
+			continue
+		}
+		matched[live.ID] = true
+
+		var changes []string
+		if live.Name != want.Name {
+			changes = append(changes, fmt.Sprintf("rename from %q", live.Name))
+		}
+		if FormatColor(live.Color) != FormatColor(color) {
+			changes = append(changes, fmt.Sprintf(
+				"colour %s to %s",
+				FormatColor(live.Color),
+				FormatColor(color),
+			))
+		}
+		if len(changes) > 0 {
+			plan.add(&Action{
+				Op:     OpUpdate,
+				Kind:   KindLabel,
+				ID:     live.ID,
+				Name:   want.Name,
+				Detail: strings.Join(changes, ", "),
+				label:  input,
+			})
+		}
+	}
+
+	if !opts.Prune {
+		return
+	}
+	for _, label := range board.Labels {
+		if !matched[label.ID] {
+			plan.add(&Action{
+				Op:   OpDelete,
+				Kind: KindLabel,
+				ID:   label.ID,
+				Name: label.Name,
+			})
+		}
+	}
+
+	return
+}
+
+// planMembers reports membership changes, the API does not support
+// managing members so they must be made by a board owner
+func planMembers(plan *Plan, spec *Spec, board *glo.Board, opts *Options) {
+	matched := map[string]bool{}
+
+	for _, want := range spec.Members {
+		var live *glo.BoardMember
+		for _, member := range board.Members {
+			if (want.ID != "" && member.ID == want.ID) ||
+				(want.ID == "" && strings.EqualFold(member.Username, want.Username)) {
+				live = member
+				break
+			}
+		}
+
+		switch {
+		case live == nil:
+			plan.add(&Action{
+				Op:     OpManual,
+				Kind:   KindMember,
+				ID:     want.ID,
+				Name:   want.key(),
+				Detail: fmt.Sprintf("invite as %s", want.Role),
+			})
+		case want.Role != "" && !strings.EqualFold(live.Role, want.Role):
+			matched[live.ID] = true
+			plan.add(&Action{
+				Op:     OpManual,
+				Kind:   KindMember,
+				ID:     live.ID,
+				Name:   want.key(),
+				Detail: fmt.Sprintf("change role from %s to %s", live.Role, want.Role),
+			})
+		default:
+			matched[live.ID] = true
+		}
+	}
+
+	if !opts.Prune {
+		return
+	}
+	for _, member := range board.Members {
+		if matched[member.ID] {
+			continue
+		}
+		name := member.Username
+		if name == "" {
+			name = member.ID
+		}
+		plan.add(&Action{
+			Op:     OpManual,
+			Kind:   KindMember,
+			ID:     member.ID,
+			Name:   name,
+			Detail: "remove from board",
+		})
+	}
+}
+
+// Changes the number of actions which will be applied
+func (p *Plan) Changes() (n int) {
+	for _, action := range p.Actions {
+		if action.Op != OpManual {
+			n++
+		}
+	}
+
+	return
+}
+
+var opSymbols = map[string]string{
+	OpCreate: "+",
+	OpUpdate: "~",
+	OpDelete: "-",
+	OpManual: "!",
+}
+
+// String renders the plan as a diff
+func (a *Action) String() string {
+	s := fmt.Sprintf("%s %s %q", opSymbols[a.Op], a.Kind, a.Name)
+	if a.ID != "" {
+		s += fmt.Sprintf(" (%s)", a.ID)
+	}
+	if a.Detail != "" {
+		s += ": " + a.Detail
+	}
+
+	return s
+}
+
+// WriteTo writes the plan as a diff followed by a summary
+func (p *Plan) WriteTo(w io.Writer) (n int64, err error) {
+	var b strings.Builder
+	for _, action := range p.Actions {
+		b.WriteString(action.String())
+		b.WriteString("\n")
+	}
+
+	counts := map[string]int{}
+	for _, action := range p.Actions {
+		counts[action.Op]++
+	}
+	if len(p.Actions) == 0 {
+		b.WriteString("no changes, the board matches the spec\n")
+	} else {
+		fmt.Fprintf(
+			&b,
+			"\n%d to create, %d to update, %d to delete",
+			counts[OpCreate],
+			counts[OpUpdate],
+			counts[OpDelete],
+		)
+		if counts[OpManual] > 0 {
+			fmt.Fprintf(&b, ", %d to make manually", counts[OpManual])
+		}
+		b.WriteString("\n")
+	}
+
+	written, err := io.WriteString(w, b.String())
+
+	return int64(written), err
+}
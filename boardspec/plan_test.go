@@ -0,0 +1,238 @@
+package boardspec
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// fakeBoard serves a single board, columns are positioned
+// by index and moved as the Glo API does, by removing the
+// column and inserting it at the requested position
+type fakeBoard struct {
+	mu     sync.Mutex
+	board  *glo.Board
+	nextID int
+}
+
+func newFakeBoard(columns ...string) *fakeBoard {
+	f := &fakeBoard{board: &glo.Board{ID: "b1", Name: "Team Alpha"}}
+	for _, name := range columns {
+		f.board.Columns = append(f.board.Columns, &glo.Column{ID: f.id(), Name: name})
+	}
+	f.renumber()
+
+	return f
+}
+
+func (f *fakeBoard) id() string {
+	f.nextID++
+	return fmt.Sprintf("c%d", f.nextID)
+}
+
+func (f *fakeBoard) renumber() {
+	for i, col := range f.board.Columns {
+		col.Position = i
+	}
+}
+
+func (f *fakeBoard) insert(col *glo.Column, position int) {
+	if position < 0 || position > len(f.board.Columns) {
+		position = len(f.board.Columns)
+	}
+	columns := append([]*glo.Column{}, f.board.Columns[:position]...)
+	columns = append(columns, col)
+	f.board.Columns = append(columns, f.board.Columns[position:]...)
+	f.renumber()
+}
+
+func (f *fakeBoard) remove(id string) (col *glo.Column) {
+	for i, c := range f.board.Columns {
+		if c.ID == id {
+			col = c
+			f.board.Columns = append(f.board.Columns[:i], f.board.Columns[i+1:]...)
+			break
+		}
+	}
+	f.renumber()
+
+	return
+}
+
+func (f *fakeBoard) names() (names []string) {
+	for _, col := range f.board.Columns {
+		names = append(names, col.Name)
+	}
+
+	return
+}
+
+func (f *fakeBoard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var v interface{}
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 2:
+		v = f.board
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "columns":
+		input := &glo.ColumnInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		col := &glo.Column{ID: f.id(), Name: input.Name}
+		f.insert(col, input.Position)
+		v = col
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[2] == "columns":
+		input := &glo.ColumnInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		col := f.remove(parts[3])
+		if col == nil {
+			http.NotFound(w, r)
+			return
+		}
+		col.Name = input.Name
+		f.insert(col, input.Position)
+		v = col
+	case r.Method == http.MethodDelete && len(parts) == 4 && parts[2] == "columns":
+		if f.remove(parts[3]) == nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "labels":
+		input := &glo.LabelInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		label := &glo.Label{ID: f.id(), Name: input.Name, Color: input.Color}
+		f.board.Labels = append(f.board.Labels, label)
+		v = label
+	}
+	if v == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestPlanIsIdempotent(t *testing.T) {
+	tests := []struct {
+		name    string
+		live    []string
+		spec    string
+		prune   bool
+		actions int
+		want    []string
+	}{
+		{
+			name:    "unmanaged columns between managed columns",
+			live:    []string{"Backlog", "Triage", "In Progress", "Review", "Done"},
+			spec:    "columns: [Backlog, In Progress, Done]",
+			actions: 0,
+			want:    []string{"Backlog", "Triage", "In Progress", "Review", "Done"},
+		},
+		{
+			name:    "reordered around an unmanaged column",
+			live:    []string{"Done", "Triage", "Backlog", "In Progress"},
+			spec:    "columns: [Backlog, In Progress, Done]",
+			actions: 1,
+			want:    []string{"Triage", "Backlog", "In Progress", "Done"},
+		},
+		{
+			name:    "reversed",
+			live:    []string{"Done", "In Progress", "Backlog"},
+			spec:    "columns: [Backlog, In Progress, Done]",
+			actions: 2,
+			want:    []string{"Backlog", "In Progress", "Done"},
+		},
+		{
+			name:    "new columns first, between and last",
+			live:    []string{"Backlog", "Triage", "Done"},
+			spec:    "columns: [Ideas, Backlog, In Progress, Done, Shipped]",
+			actions: 3,
+			want:    []string{"Ideas", "Backlog", "In Progress", "Triage", "Done", "Shipped"},
+		},
+		{
+			name:    "new columns on an empty board",
+			spec:    "columns: [Backlog, In Progress, Done]",
+			actions: 3,
+			want:    []string{"Backlog", "In Progress", "Done"},
+		},
+		{
+			name:    "pruned",
+			live:    []string{"Triage", "Done", "Backlog", "Review"},
+			spec:    "columns: [Backlog, Done]",
+			prune:   true,
+			actions: 3,
+			want:    []string{"Backlog", "Done"},
+		},
+		{
+			name:    "renamed by id and moved",
+			live:    []string{"Done", "Todo"},
+			spec:    "columns: [{name: Backlog, id: c2}, Done]",
+			actions: 2,
+			want:    []string{"Backlog", "Done"},
+		},
+		{
+			name:    "labels",
+			live:    []string{"Backlog"},
+			spec:    "columns: [Backlog]\nlabels: [{name: bug, color: \"#e11d48\"}]",
+			actions: 1,
+			want:    []string{"Backlog"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeBoard(test.live...)
+			srv := httptest.NewServer(fake)
+			defer srv.Close()
+
+			client := glo.NewClient("token")
+			client.BaseURI = srv.URL
+
+			spec, err := Parse([]byte("id: b1\nboard: Team Alpha\n" + test.spec))
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts := &Options{Prune: test.prune}
+
+			board, err := Find(client, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err := Compute(spec, board, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Actions) != test.actions {
+				t.Errorf("got %d actions, want %d:\n%s", len(plan.Actions), test.actions, plan.Actions)
+			}
+			if err = Apply(client, plan); err != nil {
+				t.Fatal(err)
+			}
+			if got := fake.names(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got columns %v, want %v", got, test.want)
+			}
+
+			board, err = Find(client, spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plan, err = Compute(spec, board, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(plan.Actions) != 0 {
+				t.Errorf("plan is not empty after applying it:\n%s", plan.Actions)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+// Package boardspec manages the structure of Glo boards declaratively,
+// computing and applying plans from YAML specs.
+//
+//	board: Team Alpha
+//	columns:
+//	  - Backlog
+//	  - In Progress
+//	  - name: Done
+//	    id: <column ID, allows the column to be renamed>
+//	labels:
+//	  - name: bug
+//	    color: "#e11d48"
+//	members:
+//	  - username: alice
+//	    role: admin
+package boardspec
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec the desired structure of a board
+type Spec struct {
+	// ID of the managed board, when empty the
+	// board is found by its name
+	ID      string        `yaml:"id,omitempty"`
+	Name    string        `yaml:"board"`
+	Columns []*ColumnSpec `yaml:"columns"`
+	Labels  []*LabelSpec  `yaml:"labels"`
+	Members []*MemberSpec `yaml:"members"`
+}
+
+// ColumnSpec a column, columns are positioned in the order they are listed
+type ColumnSpec struct {
+	ID   string `yaml:"id,omitempty"`
+	Name string `yaml:"name"`
+}
+
+// UnmarshalYAML allows columns to be listed by name only
+func (c *ColumnSpec) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		c.Name = node.Value
+		return nil
+	}
+
+	type plain ColumnSpec
+	return node.Decode((*plain)(c))
+}
+
+// LabelSpec a label and its colour, written as #rrggbb or #rrggbbaa
+type LabelSpec struct {
+	ID    string `yaml:"id,omitempty"`
+	Name  string `yaml:"name"`
+	Color string `yaml:"color"`
+}
+
+// MemberSpec a board member identified by ID or username
+type MemberSpec struct {
+	ID       string `yaml:"id,omitempty"`
+	Username string `yaml:"username,omitempty"`
+	Role     string `yaml:"role"`
+}
+
+// Load reads and validates a spec file
+func Load(path string) (spec *Spec, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	spec, err = Parse(data)
+	if err != nil {
+		err = fmt.Errorf("%s: %s", path, err)
+	}
+
+	return
+}
+
+// Parse parses and validates a YAML spec
+func Parse(data []byte) (spec *Spec, err error) {
+	spec = &Spec{}
+	err = yaml.Unmarshal(data, spec)
+	if err != nil {
+		return
+	}
+
+	err = spec.Validate()
+
+	return
+}
+
+// Validate ensures the spec is complete and unambiguous
+func (s *Spec) Validate() (err error) {
+	if s.Name == "" {
+		return fmt.Errorf("board name is required")
+	}
+
+	columns := map[string]bool{}
+	for i, col := range s.Columns {
+		if col == nil || col.Name == "" {
+			return fmt.Errorf("column %d has no name", i+1)
+		}
+		if columns[col.Name] {
+			return fmt.Errorf("column %q is listed more than once", col.Name)
+		}
+		columns[col.Name] = true
+	}
+
+	labels := map[string]bool{}
+	for i, label := range s.Labels {
+		if label == nil || label.Name == "" {
+			return fmt.Errorf("label %d has no name", i+1)
+		}
+		if labels[label.Name] {
+			return fmt.Errorf("label %q is listed more than once", label.Name)
+		}
+		labels[label.Name] = true
+
+		if _, err = ParseColor(label.Color); err != nil {
+			return fmt.Errorf("label %q: %s", label.Name, err)
+		}
+	}
+
+	members := map[string]bool{}
+	for i, member := range s.Members {
+		if member == nil || (member.ID == "" && member.Username == "") {
+			return fmt.Errorf("member %d has no id or username", i+1)
+		}
+		key := member.key()
+		if members[key] {
+			return fmt.Errorf("member %q is listed more than once", key)
+		}
+		members[key] = true
+	}
+
+	return
+}
+
+func (m *MemberSpec) key() string {
+	if m.ID != "" {
+		return m.ID
+	}
+
+	return m.Username
+}
+
+// ParseColor parses a #rrggbb or #rrggbbaa colour
+func ParseColor(s string) (color glo.Color, err error) {
+	hex := strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		err = fmt.Errorf("invalid colour %q, expected #rrggbb or #rrggbbaa", s)
+		return
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		err = fmt.Errorf("invalid colour %q, expected #rrggbb or #rrggbbaa", s)
+		return
+	}
+
+	alpha := uint64(0xff)
+	if len(hex) == 8 {
+		alpha = v & 0xff
+		v >>= 8
+	}
+
+	color = glo.Color{
+		R: int(v >> 16 & 0xff),
+		G: int(v >> 8 & 0xff),
+		B: int(v & 0xff),
+		A: float64(alpha) / 0xff,
+	}
+
+	return
+}
+
+// FormatColor formats a colour as #rrggbb, or #rrggbbaa
+// when it is not fully opaque
+func FormatColor(color glo.Color) string {
+	return color.Hex()
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/jackmcguire1/go-glo/internal/utils"
+	"github.com/jackmcguire1/go-glo/tasklist"
 )
 
 // Card contains information related to a card
@@ -72,6 +73,7 @@ var cardFields = []string{
 	"description",
 	"labels",
 	"name",
+	"position",
 	"total_task_count",
 	"updated_date",
 }
@@ -84,13 +86,17 @@ func (a *Glo) GetCards(
 	limit int,
 	sortDesc bool,
 	archived bool,
+	opts ...CallOption,
 ) (
 	cardsResp *CardsResp,
 	err error,
 ) {
 	addr := fmt.Sprintf("%s/boards/%s/cards", a.BaseURI, boardID)
 
-	q := utils.AddFields(cardFields)
+	q, err := fieldsQuery(cardResource, cardFields, opts)
+	if err != nil {
+		return
+	}
 	q.Set("page", fmt.Sprint(page))
 	q.Set("per_page", fmt.Sprint(limit))
 
@@ -144,6 +150,44 @@ func (a *Glo) CreateCard(
 	return
 }
 
+// CardsBatchInput contains information used
+// to create several cards in a single request
+type CardsBatchInput struct {
+	Cards             []*CardsInput `json:"cards"`
+	SendNotifications bool          `json:"send_notifications"`
+}
+
+// MaxCardsBatch the most cards which can be created in a single batch
+const MaxCardsBatch = 100
+
+// CreateCardsBatch Creates up to MaxCardsBatch Cards
+// https://gloapi.gitkraken.com/v1/docs/#/Cards/post_boards__board_id__cards_batch
+func (a *Glo) CreateCardsBatch(
+	boardID string,
+	batchInput *CardsBatchInput,
+) (
+	cards []*Card,
+	err error,
+) {
+	if len(batchInput.Cards) > MaxCardsBatch {
+		err = fmt.Errorf("batch of %d cards exceeds the maximum of %d", len(batchInput.Cards), MaxCardsBatch)
+		return
+	}
+
+	addr := fmt.Sprintf("%s/boards/%s/cards/batch", a.BaseURI, boardID)
+
+	resp, _, err := a.jsonReq(http.MethodPost, addr, utils.ToRawMessage(batchInput), nil)
+	if err != nil {
+		return
+	}
+
+	batchResp := &CardsResp{}
+	err = json.Unmarshal(resp, &batchResp)
+	cards = batchResp.Cards
+
+	return
+}
+
 // EditCard Edits a Card
 // https://gloapi.gitkraken.com/v1/docs/#/Cards/post_boards__board_id__cards__card_id_
 func (a *Glo) EditCard(
@@ -172,13 +216,17 @@ func (a *Glo) EditCard(
 func (a *Glo) GetCard(
 	boardID string,
 	cardID string,
+	opts ...CallOption,
 ) (
 	card *Card,
 	err error,
 ) {
 	addr := fmt.Sprintf("%s/boards/%s/cards/%s", a.BaseURI, boardID, cardID)
 
-	q := utils.AddFields(cardFields)
+	q, err := fieldsQuery(cardResource, cardFields, opts)
+	if err != nil {
+		return
+	}
 
 	resp, _, err := a.jsonReq(http.MethodGet, addr, nil, q)
 	if err != nil {
@@ -215,13 +263,17 @@ func (a *Glo) CardsByColumn(
 	limit int,
 	sortDesc bool,
 	archived bool,
+	opts ...CallOption,
 ) (
 	cardsResp *CardsResp,
 	err error,
 ) {
 	addr := fmt.Sprintf("%s/boards/%s/columns/%s/cards/", a.BaseURI, boardID, columnID)
 
-	q := utils.AddFields(cardFields)
+	q, err := fieldsQuery(cardResource, cardFields, opts)
+	if err != nil {
+		return
+	}
 
 	q.Set("page", fmt.Sprint(page))
 	q.Set("per_page", fmt.Sprint(limit))
@@ -251,3 +303,64 @@ func (a *Glo) CardsByColumn(
 
 	return
 }
+
+// Input returns the information required to edit the card
+// without modifying any of its current values
+func (c *Card) Input() *CardsInput {
+	input := &CardsInput{
+		Name:      c.Name,
+		Position:  c.Position,
+		ColumnID:  c.ColumnID,
+		Assignees: c.Assignees,
+		Labels:    c.Labels,
+		DueDate:   c.DueDate,
+	}
+	if c.Description != nil {
+		input.Description = &MinimizedDescription{Text: c.Description.Text}
+	}
+
+	return input
+}
+
+// Tasks parses the task list of the card's description
+func (c *Card) Tasks() *tasklist.Document {
+	if c.Description == nil {
+		return tasklist.Parse("")
+	}
+
+	return tasklist.Parse(c.Description.Text)
+}
+
+// UpdateTasks fetches a card, applies edit to its task list and, when
+// the description changed, writes it back with EditCard leaving the
+// card's other values untouched
+func (a *Glo) UpdateTasks(
+	boardID string,
+	cardID string,
+	edit func(d *tasklist.Document) error,
+) (
+	card *Card,
+	err error,
+) {
+	card, err = a.GetCard(boardID, cardID)
+	if err != nil {
+		return
+	}
+
+	d := card.Tasks()
+	before := d.String()
+	err = edit(d)
+	if err != nil {
+		return
+	}
+	if d.String() == before {
+		return
+	}
+
+	input := card.Input()
+	input.Description = &MinimizedDescription{Text: d.String()}
+
+	card, err = a.EditCard(boardID, cardID, input)
+
+	return
+}
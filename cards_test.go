@@ -0,0 +1,115 @@
+package glo
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo/tasklist"
+)
+
+func TestUpdateTasks(t *testing.T) {
+	tests := []struct {
+		name        string
+		description *Description
+		edit        func(d *tasklist.Document) error
+		want        string
+		edited      bool
+		err         string
+	}{
+		{
+			name:        "toggle keeps crlf text",
+			description: &Description{Text: "Notes\r\n- [ ] one\r\n- [ ] two\r\n"},
+			edit:        func(d *tasklist.Document) error { return d.Toggle(1) },
+			want:        "Notes\r\n- [ ] one\r\n- [x] two\r\n",
+			edited:      true,
+		},
+		{
+			name:   "add without a description",
+			edit:   func(d *tasklist.Document) error { return d.Add("first", false) },
+			want:   "- [ ] first",
+			edited: true,
+		},
+		{
+			name:        "unchanged is not written",
+			description: &Description{Text: "- [x] done"},
+			edit:        func(d *tasklist.Document) error { return d.SetDone(0, true) },
+			want:        "- [x] done",
+		},
+		{
+			name:        "edit error",
+			description: &Description{Text: "- [ ] one"},
+			edit:        func(d *tasklist.Document) error { return errors.New("no such task") },
+			err:         "no such task",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			card := &Card{
+				ID:          "c1",
+				Name:        "Card",
+				Position:    3,
+				ColumnID:    "col1",
+				Labels:      []*PartialLabel{{ID: "l1"}},
+				DueDate:     "2026-10-19T00:00:00Z",
+				Description: test.description,
+			}
+
+			var edits []*CardsInput
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/boards/b1/cards/c1" {
+					http.NotFound(w, r)
+					return
+				}
+				if r.Method == http.MethodPost {
+					input := &CardsInput{}
+					json.NewDecoder(r.Body).Decode(input)
+					edits = append(edits, input)
+					card.Description = &Description{Text: input.Description.Text}
+				}
+				json.NewEncoder(w).Encode(card)
+			}))
+			defer srv.Close()
+
+			client := NewClient("token")
+			client.BaseURI = srv.URL
+
+			got, err := client.UpdateTasks("b1", "c1", test.edit)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("got err %v, want %q", err, test.err)
+				}
+				if len(edits) != 0 {
+					t.Error("card was edited")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Description == nil || got.Description.Text != test.want {
+				t.Errorf("got description %+v, want %q", got.Description, test.want)
+			}
+			if !test.edited {
+				if len(edits) != 0 {
+					t.Errorf("unchanged card was edited %d times", len(edits))
+				}
+				return
+			}
+			if len(edits) != 1 {
+				t.Fatalf("card was edited %d times, want 1", len(edits))
+			}
+
+			// the card's other values are left untouched
+			input := edits[0]
+			if input.Name != "Card" || input.Position != 3 || input.ColumnID != "col1" ||
+				len(input.Labels) != 1 || input.DueDate != "2026-10-19T00:00:00Z" {
+				t.Errorf("got input %+v", input)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package glo
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jackmcguire1/go-glo/tasklist"
+)
+
+// CloneOptions contains information used to clone a board
+type CloneOptions struct {
+	// Cards copies the active cards of the board
+	Cards bool
+
+	// Descriptions copies the descriptions of copied cards
+	Descriptions bool
+
+	// Checklists copies only the task lists of card descriptions,
+	// it has no effect when descriptions are copied
+	Checklists bool
+}
+
+// CloneBoard Creates a new board with the columns, in position
+// order, and labels of an existing board, optionally copying its cards
+func (a *Glo) CloneBoard(
+	srcID string,
+	newName string,
+	opts *CloneOptions,
+) (
+	board *Board,
+	err error,
+) {
+	if opts == nil {
+		opts = &CloneOptions{}
+	}
+
+	src, err := a.GetBoard(srcID)
+	if err != nil {
+		return
+	}
+
+	board, err = a.CreateBoard(&BoardInput{Name: newName})
+	if err != nil {
+		return
+	}
+
+	columns := append([]*Column{}, src.Columns...)
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Position < columns[j].Position
+	})
+
+	columnIDs := map[string]string{}
+	for position, col := range columns {
+		var created *Column
+		created, err = a.CreateColumn(board.ID, &ColumnInput{Name: col.Name, Position: position})
+		if err != nil {
+			err = fmt.Errorf("failed to clone column:%s err:%s", col.ID, err)
+			return
+		}
+		columnIDs[col.ID] = created.ID
+		board.Columns = append(board.Columns, created)
+	}
+
+	labelIDs := map[string]string{}
+	for _, label := range src.Labels {
+		var created *Label
+		created, err = a.CreateLabel(board.ID, &LabelInput{Name: label.Name, Color: label.Color})
+		if err != nil {
+			err = fmt.Errorf("failed to clone label:%s err:%s", label.ID, err)
+			return
+		}
+		labelIDs[label.ID] = created.ID
+		board.Labels = append(board.Labels, created)
+	}
+
+	if !opts.Cards {
+		return
+	}
+
+	cards, err := a.AllCards(srcID, false)
+	if err != nil {
+		return
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].Position < cards[j].Position
+	})
+
+	// cards of archived columns are not cloned, so positions are
+	// renumbered within each column rather than copied
+	positions := map[string]int{}
+	for _, card := range cards {
+		columnID, ok := columnIDs[card.ColumnID]
+		if !ok {
+			continue
+		}
+
+		input := &CardsInput{
+			Name:     card.Name,
+			Position: positions[columnID],
+			ColumnID: columnID,
+			DueDate:  card.DueDate,
+		}
+		for _, label := range card.Labels {
+			if labelID, ok := labelIDs[label.ID]; ok {
+				input.Labels = append(input.Labels, &PartialLabel{ID: labelID})
+			}
+		}
+
+		if card.Description != nil {
+			text := card.Description.Text
+			if !opts.Descriptions {
+				text = ""
+				if opts.Checklists {
+					text = tasklist.Parse(card.Description.Text).Checklist()
+				}
+			}
+			if text != "" {
+				input.Description = &MinimizedDescription{Text: text}
+			}
+		}
+
+		_, err = a.CreateCard(board.ID, input)
+		if err != nil {
+			err = fmt.Errorf("failed to clone card:%s err:%s", card.ID, err)
+			return
+		}
+		positions[columnID]++
+	}
+
+	return
+}
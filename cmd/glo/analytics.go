@@ -0,0 +1,230 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/analytics"
+)
+
+// analyticsCmd captures periodic snapshots of a board
+// and computes flow metrics from them
+func analyticsCmd(e *env, args []string) error {
+	return subcommands(e, "analytics", args, map[string]command{
+		"capture":    analyticsCapture,
+		"flow":       analyticsFlow,
+		"cycle":      analyticsCycle,
+		"throughput": analyticsThroughput,
+		"wip":        analyticsWIP,
+	})
+}
+
+// snapshotsDir the snapshot directory, or alongside the config file
+func (e *env) snapshotsDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	if e.config != "" {
+		return filepath.Join(filepath.Dir(e.config), "snapshots"), nil
+	}
+
+	return analytics.DefaultDir()
+}
+
+func analyticsCapture(e *env, args []string) (err error) {
+	fs := e.flagSet("analytics capture")
+	boards := fs.String("board", "", "comma separated board IDs")
+	dir := fs.String("dir", "", "snapshot directory")
+	archived := fs.Bool("archived", false, "include archived cards")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	boardIDs := append(splitList(*boards), positional...)
+	if len(boardIDs) == 0 {
+		return fmt.Errorf("%s: at least one board is required", fs.Name())
+	}
+	*dir, err = e.snapshotsDir(*dir)
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	for _, boardID := range boardIDs {
+		snap, snapErr := e.client.Snapshot(boardID, &glo.SnapshotOptions{Archived: *archived})
+		if snapErr != nil {
+			return snapErr
+		}
+		snap.Board.ID = boardID
+
+		path, saveErr := analytics.Save(snap, *dir)
+		if saveErr != nil {
+			return saveErr
+		}
+		fmt.Fprintln(e.stderr, "captured", path)
+	}
+
+	return
+}
+
+// metricFlags flags shared by the metric subcommands
+type metricFlags struct {
+	board  string
+	dir    string
+	format string
+	out    string
+}
+
+func (m *metricFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&m.board, "board", "", "board ID, required when snapshots of several boards are read")
+	fs.StringVar(&m.dir, "dir", "", "snapshot directory, used when no snapshot files are given")
+	fs.StringVar(&m.format, "format", "csv", "output format: csv or json")
+	fs.StringVar(&m.out, "o", "", "output file, defaults to stdout")
+}
+
+// history loads the snapshot files given, or those in the snapshot directory
+func (m *metricFlags) history(e *env, paths []string) (h *analytics.History, err error) {
+	if len(paths) == 0 {
+		dir, dirErr := e.snapshotsDir(m.dir)
+		if dirErr != nil {
+			return nil, dirErr
+		}
+		paths = []string{dir}
+	}
+
+	return analytics.LoadHistory(m.board, paths...)
+}
+
+// write writes a metric as CSV or JSON
+func (m *metricFlags) write(e *env, fs *flag.FlagSet, v interface{ WriteCSV(io.Writer) error }) (err error) {
+	if e.output == "json" {
+		m.format = "json"
+	}
+	if m.format != "csv" && m.format != "json" {
+		return fmt.Errorf("%s: unsupported format %q", fs.Name(), m.format)
+	}
+
+	w := e.stdout
+	if m.out != "" {
+		f, createErr := os.Create(m.out)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+
+	if m.format == "csv" {
+		return v.WriteCSV(w)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
+
+func analyticsFlow(e *env, args []string) (err error) {
+	fs := e.flagSet("analytics flow")
+	m := &metricFlags{}
+	m.register(fs)
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	h, err := m.history(e, positional)
+	if err != nil {
+		return
+	}
+
+	return m.write(e, fs, h.CumulativeFlow())
+}
+
+// cycleFlags the columns between which cycle time is measured
+func cycleFlags(fs *flag.FlagSet) *analytics.CycleOptions {
+	opts := &analytics.CycleOptions{}
+	fs.StringVar(&opts.Start, "start", "", "column, by ID or name, in which work starts, defaults to the second column")
+	fs.StringVar(&opts.End, "end", "", "column, by ID or name, in which work is finished, defaults to the last column")
+
+	return opts
+}
+
+// cycleTimes loads the history and computes the cycle times of its cards
+func cycleTimes(
+	e *env,
+	fs *flag.FlagSet,
+	m *metricFlags,
+	opts *analytics.CycleOptions,
+	args []string,
+) (
+	h *analytics.History,
+	times analytics.CycleTimes,
+	err error,
+) {
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	h, err = m.history(e, positional)
+	if err != nil {
+		return
+	}
+	times, err = h.CycleTimes(opts)
+
+	return
+}
+
+func analyticsCycle(e *env, args []string) (err error) {
+	fs := e.flagSet("analytics cycle")
+	m := &metricFlags{}
+	m.register(fs)
+	opts := cycleFlags(fs)
+
+	_, times, err := cycleTimes(e, fs, m, opts, args)
+	if err != nil {
+		return
+	}
+
+	return m.write(e, fs, times)
+}
+
+func analyticsThroughput(e *env, args []string) (err error) {
+	fs := e.flagSet("analytics throughput")
+	m := &metricFlags{}
+	m.register(fs)
+	opts := cycleFlags(fs)
+
+	h, times, err := cycleTimes(e, fs, m, opts, args)
+	if err != nil {
+		return
+	}
+
+	return m.write(e, fs, h.Throughput(times))
+}
+
+func analyticsWIP(e *env, args []string) (err error) {
+	fs := e.flagSet("analytics wip")
+	m := &metricFlags{}
+	m.register(fs)
+	opts := cycleFlags(fs)
+
+	h, times, err := cycleTimes(e, fs, m, opts, args)
+	if err != nil {
+		return
+	}
+
+	return m.write(e, fs, h.AgingWIP(times))
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func attachmentsCmd(e *env, args []string) error {
+	return subcommands(e, "attachments", args, map[string]command{
+		"list":   attachmentsList,
+		"upload": attachCmd,
+	})
+}
+
+func attachmentsList(e *env, args []string) (err error) {
+	fs := e.flagSet("attachments list")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	p := &paging{}
+	p.register(fs, false)
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+	if err = require(fs, "board", "card"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	resp, err := e.client.GetAttachments(*boardID, *cardID, p.page, p.limit, p.desc)
+	if err != nil {
+		return
+	}
+
+	var rows [][]string
+	for _, attachment := range resp.Attachments {
+		rows = append(rows, []string{
+			attachment.ID,
+			attachment.Filename,
+			attachment.MimeType,
+			attachment.CreatedDate,
+		})
+	}
+
+	return e.render(
+		resp.Attachments,
+		[]string{"ID", "FILENAME", "MIME TYPE", "CREATED"},
+		rows,
+	)
+}
+
+// attachCmd uploads a file and links it from a new comment on the card
+func attachCmd(e *env, args []string) (err error) {
+	fs := e.flagSet("attach")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	description := fs.String("description", "", "link text, defaults to the file name")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		err = fmt.Errorf("%s: exactly one file is required", fs.Name())
+		return
+	}
+	if err = require(fs, "board", "card"); err != nil {
+		return
+	}
+
+	path := positional[0]
+	if *description == "" {
+		*description = filepath.Base(path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	generated, err := e.client.CreateAttachment(*boardID, *cardID, *description, f)
+	if err != nil {
+		return
+	}
+
+	a := generated.Attachment
+	return e.render(
+		generated,
+		[]string{"ID", "FILENAME", "MIME TYPE", "URL"},
+		[][]string{{a.ID, a.Filename, a.MimeType, a.URL}},
+	)
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// paging flags shared by list commands
+type paging struct {
+	page     int
+	limit    int
+	desc     bool
+	archived bool
+}
+
+func (p *paging) register(fs *flag.FlagSet, archived bool) {
+	fs.IntVar(&p.page, "page", 1, "page number")
+	fs.IntVar(&p.limit, "limit", 50, "results per page")
+	fs.BoolVar(&p.desc, "desc", false, "sort descending")
+	if archived {
+		fs.BoolVar(&p.archived, "archived", false, "list archived items")
+	}
+}
+
+func boardsCmd(e *env, args []string) error {
+	return subcommands(e, "boards", args, map[string]command{
+		"list":   boardsList,
+		"get":    boardsGet,
+		"create": boardsCreate,
+		"edit":   boardsEdit,
+		"delete": boardsDelete,
+		"view":   boardsView,
+		"plan":   boardsPlan,
+		"apply":  boardsApply,
+		"clone":  boardsClone,
+	})
+}
+
+func boardsList(e *env, args []string) (err error) {
+	fs := e.flagSet("boards list")
+	p := &paging{}
+	p.register(fs, true)
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	resp, err := e.client.GetBoards(p.page, p.limit, p.desc, p.archived)
+	if err != nil {
+		return
+	}
+
+	return e.renderBoards(resp.Boards)
+}
+
+func boardsGet(e *env, args []string) (err error) {
+	fs := e.flagSet("boards get")
+	boardID := fs.String("board", "", "board ID")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	board, err := e.client.GetBoard(id)
+	if err != nil {
+		return
+	}
+
+	if e.output == "json" {
+		return e.render(board, nil, nil)
+	}
+
+	err = e.renderBoards([]*glo.Board{board})
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintln(e.stdout)
+	return e.renderColumns(board.Columns)
+}
+
+func boardsCreate(e *env, args []string) (err error) {
+	fs := e.flagSet("boards create")
+	name := fs.String("name", "", "board name")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if *name == "" && len(positional) > 0 {
+		*name = positional[0]
+	}
+	if err = require(fs, "name"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	board, err := e.client.CreateBoard(&glo.BoardInput{Name: *name})
+	if err != nil {
+		return
+	}
+
+	return e.renderBoards([]*glo.Board{board})
+}
+
+func boardsEdit(e *env, args []string) (err error) {
+	fs := e.flagSet("boards edit")
+	boardID := fs.String("board", "", "board ID")
+	name := fs.String("name", "", "new board name")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "name"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	board, err := e.client.EditBoard(id, &glo.BoardInput{Name: *name})
+	if err != nil {
+		return
+	}
+
+	return e.renderBoards([]*glo.Board{board})
+}
+
+func boardsDelete(e *env, args []string) (err error) {
+	fs := e.flagSet("boards delete")
+	boardID := fs.String("board", "", "board ID")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	err = e.client.DeleteBoard(id)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(e.stderr, "deleted board", id)
+
+	return
+}
+
+func (e *env) renderBoards(boards []*glo.Board) error {
+	var rows [][]string
+	for _, board := range boards {
+		rows = append(rows, []string{
+			board.ID,
+			board.Name,
+			fmt.Sprint(len(board.Columns)),
+			fmt.Sprint(len(board.Members)),
+			board.CreatedDate,
+			board.ArchivedDate,
+		})
+	}
+
+	return e.render(
+		boards,
+		[]string{"ID", "NAME", "COLUMNS", "MEMBERS", "CREATED", "ARCHIVED"},
+		rows,
+	)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/export"
+)
+
+// calendarCmd writes, or serves, an iCalendar feed of due cards
+func calendarCmd(e *env, args []string) (err error) {
+	fs := e.flagSet("calendar")
+	boards := fs.String("board", "", "comma separated board IDs")
+	assignees := fs.String("assignee", "", "comma separated assignee IDs or usernames, \"me\" for yourself")
+	labels := fs.String("label", "", "comma separated label names")
+	name := fs.String("name", "Glo", "calendar name")
+	out := fs.String("o", "", "output file, defaults to stdout")
+	serve := fs.String("serve", "", "serve the feed on this address instead, e.g. :8080")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	boardIDs := append(splitList(*boards), positional...)
+	if len(boardIDs) == 0 {
+		return fmt.Errorf("%s: at least one board is required", fs.Name())
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	opts := export.CalendarOptions{
+		Name:   *name,
+		Labels: splitList(*labels),
+	}
+	for _, assignee := range splitList(*assignees) {
+		if assignee == "me" {
+			user, userErr := e.client.GetUser()
+			if userErr != nil {
+				return userErr
+			}
+			assignee = user.ID
+		}
+		opts.Assignees = append(opts.Assignees, assignee)
+	}
+
+	if *serve != "" {
+		handler := &export.CalendarHandler{
+			Client:   e.client,
+			BoardIDs: boardIDs,
+			Options:  opts,
+		}
+		fmt.Fprintf(e.stderr, "serving calendar on %s\n", *serve)
+		return http.ListenAndServe(*serve, handler)
+	}
+
+	var snaps []*glo.BoardSnapshot
+	for _, boardID := range boardIDs {
+		snap, snapErr := e.client.Snapshot(boardID, nil)
+		if snapErr != nil {
+			return snapErr
+		}
+		snaps = append(snaps, snap)
+	}
+
+	w := e.stdout
+	if *out != "" {
+		f, createErr := os.Create(*out)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+
+	return export.ICalendar(w, snaps, &opts)
+}
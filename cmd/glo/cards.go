@@ -0,0 +1,334 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func cardsCmd(e *env, args []string) error {
+	return subcommands(e, "cards", args, map[string]command{
+		"list":   cardsList,
+		"get":    cardsGet,
+		"create": cardsCreate,
+		"edit":   cardsEdit,
+		"delete": cardsDelete,
+	})
+}
+
+func cardsList(e *env, args []string) (err error) {
+	fs := e.flagSet("cards list")
+	boardID := fs.String("board", "", "board ID")
+	columnID := fs.String("column", "", "only list cards in this column")
+	p := &paging{}
+	p.register(fs, true)
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	var resp *glo.CardsResp
+	if *columnID != "" {
+		resp, err = e.client.CardsByColumn(*boardID, *columnID, p.page, p.limit, p.desc, p.archived)
+	} else {
+		resp, err = e.client.GetCards(*boardID, p.page, p.limit, p.desc, p.archived)
+	}
+	if err != nil {
+		return
+	}
+
+	return e.renderCards(resp.Cards)
+}
+
+func cardsGet(e *env, args []string) (err error) {
+	fs := e.flagSet("cards get")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *cardID, positional, "card")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	card, err := e.client.GetCard(*boardID, id)
+	if err != nil {
+		return
+	}
+
+	if e.output == "json" {
+		return e.render(card, nil, nil)
+	}
+
+	err = e.renderCards([]*glo.Card{card})
+	if err != nil {
+		return
+	}
+	if card.Description != nil && card.Description.Text != "" {
+		fmt.Fprintf(e.stdout, "\n%s\n", card.Description.Text)
+	}
+
+	return
+}
+
+// cardFlags flags used to create or edit a card
+type cardFlags struct {
+	name        string
+	column      string
+	description string
+	position    int
+	due         string
+	labels      string
+	assignees   string
+}
+
+func (c *cardFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&c.name, "name", "", "card name")
+	fs.StringVar(&c.column, "column", "", "column ID")
+	fs.StringVar(&c.description, "description", "", "card description (markdown)")
+	fs.IntVar(&c.position, "position", 0, "card position")
+	fs.StringVar(&c.due, "due", "", "due date e.g. 2019-06-01T00:00:00Z")
+	fs.StringVar(&c.labels, "labels", "", "comma separated label IDs or names")
+	fs.StringVar(&c.assignees, "assignees", "", "comma separated user IDs")
+}
+
+// apply sets the explicitly provided flags on the card input
+func (c *cardFlags) apply(
+	e *env,
+	fs *flag.FlagSet,
+	boardID string,
+	input *glo.CardsInput,
+) (err error) {
+	set := setFlags(fs)
+
+	if set["name"] {
+		input.Name = c.name
+	}
+	if set["column"] {
+		input.ColumnID = c.column
+	}
+	if set["description"] {
+		input.Description = &glo.MinimizedDescription{Text: c.description}
+	}
+	if set["position"] {
+		input.Position = c.position
+	}
+	if set["due"] {
+		input.DueDate = c.due
+	}
+	if set["assignees"] {
+		input.Assignees = []*glo.PartialUser{}
+		for _, id := range splitList(c.assignees) {
+			input.Assignees = append(input.Assignees, &glo.PartialUser{ID: id})
+		}
+	}
+	if set["labels"] {
+		input.Labels, err = e.resolveLabels(boardID, splitList(c.labels))
+	}
+
+	return
+}
+
+// resolveLabels resolves label IDs or names against the board's labels
+func (e *env) resolveLabels(boardID string, refs []string) (labels []*glo.PartialLabel, err error) {
+	labels = []*glo.PartialLabel{}
+	if len(refs) == 0 {
+		return
+	}
+
+	board, err := e.client.GetBoard(boardID, glo.Fields(glo.BoardFieldLabels))
+	if err != nil {
+		return
+	}
+
+	for _, ref := range refs {
+		var found *glo.Label
+		for _, label := range board.Labels {
+			if label.ID == ref || strings.EqualFold(label.Name, ref) {
+				found = label
+				break
+			}
+		}
+		if found == nil {
+			err = fmt.Errorf("label %q not found on board %s", ref, boardID)
+			return
+		}
+		labels = append(labels, &glo.PartialLabel{ID: found.ID, Name: found.Name})
+	}
+
+	return
+}
+
+func cardsCreate(e *env, args []string) (err error) {
+	fs := e.flagSet("cards create")
+	boardID := fs.String("board", "", "board ID")
+	queue := fs.Bool("queue", false, "queue the card in the outbox when the API is unreachable")
+	c := &cardFlags{}
+	c.register(fs)
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if c.name == "" && len(positional) > 0 {
+		fs.Set("name", strings.Join(positional, " "))
+	}
+	if err = require(fs, "board", "column", "name"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	input := &glo.CardsInput{}
+	err = c.apply(e, fs, *boardID, input)
+	if err != nil {
+		return
+	}
+
+	var card *glo.Card
+	if *queue {
+		o, openErr := e.openOutbox("")
+		if openErr != nil {
+			return openErr
+		}
+
+		var queued bool
+		card, queued, err = o.CreateCard(*boardID, input)
+		if queued {
+			e.queued("card", o.Len())
+		}
+	} else {
+		card, err = e.client.CreateCard(*boardID, input)
+	}
+	if err != nil {
+		return
+	}
+
+	return e.renderCards([]*glo.Card{card})
+}
+
+func cardsEdit(e *env, args []string) (err error) {
+	fs := e.flagSet("cards edit")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	c := &cardFlags{}
+	c.register(fs)
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *cardID, positional, "card")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	// unspecified values keep the card's current values
+	current, err := e.client.GetCard(*boardID, id)
+	if err != nil {
+		return
+	}
+
+	input := current.Input()
+	err = c.apply(e, fs, *boardID, input)
+	if err != nil {
+		return
+	}
+
+	card, err := e.client.EditCard(*boardID, id, input)
+	if err != nil {
+		return
+	}
+
+	return e.renderCards([]*glo.Card{card})
+}
+
+func cardsDelete(e *env, args []string) (err error) {
+	fs := e.flagSet("cards delete")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	queue := fs.Bool("queue", false, "queue the delete in the outbox when the API is unreachable")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *cardID, positional, "card")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	if *queue {
+		o, openErr := e.openOutbox("")
+		if openErr != nil {
+			return openErr
+		}
+
+		lastSeen, seenErr := e.lastSeen(*boardID, id)
+		if seenErr != nil {
+			return seenErr
+		}
+
+		queued, deleteErr := o.DeleteCard(*boardID, id, lastSeen)
+		if queued {
+			e.queued("card delete", o.Len())
+			return
+		}
+		err = deleteErr
+	} else {
+		err = e.client.DeleteCard(*boardID, id)
+	}
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(e.stderr, "deleted card", id)
+
+	return
+}
+
+func (e *env) renderCards(cards []*glo.Card) error {
+	var rows [][]string
+	for _, card := range cards {
+		rows = append(rows, []string{
+			card.ID,
+			truncate(card.Name, 40),
+			card.ColumnID,
+			labelNames(card.Labels),
+			userIDs(card.Assignees),
+			card.DueDate,
+			fmt.Sprintf("%d/%d", card.CompletedTaskCount, card.TotalTaskCount),
+			fmt.Sprint(card.CommentCount),
+		})
+	}
+
+	return e.render(
+		cards,
+		[]string{"ID", "NAME", "COLUMN", "LABELS", "ASSIGNEES", "DUE", "TASKS", "COMMENTS"},
+		rows,
+	)
+}
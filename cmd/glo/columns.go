@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func columnsCmd(e *env, args []string) error {
+	return subcommands(e, "columns", args, map[string]command{
+		"list":   columnsList,
+		"create": columnsCreate,
+		"edit":   columnsEdit,
+		"delete": columnsDelete,
+	})
+}
+
+func columnsList(e *env, args []string) (err error) {
+	fs := e.flagSet("columns list")
+	boardID := fs.String("board", "", "board ID")
+	archived := fs.Bool("archived", false, "list archived columns")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	board, err := e.client.GetBoard(
+		*boardID,
+		glo.Fields(glo.BoardFieldColumns, glo.BoardFieldArchivedColumns),
+	)
+	if err != nil {
+		return
+	}
+
+	columns := board.Columns
+	if *archived {
+		columns = board.ArchivedColumns
+	}
+
+	return e.renderColumns(columns)
+}
+
+func columnsCreate(e *env, args []string) (err error) {
+	fs := e.flagSet("columns create")
+	boardID := fs.String("board", "", "board ID")
+	name := fs.String("name", "", "column name")
+	position := fs.Int("position", 0, "column position")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if *name == "" && len(positional) > 0 {
+		*name = positional[0]
+	}
+	if err = require(fs, "board", "name"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	col, err := e.client.CreateColumn(*boardID, &glo.ColumnInput{
+		Name:     *name,
+		Position: *position,
+	})
+	if err != nil {
+		return
+	}
+
+	return e.renderColumns([]*glo.Column{col})
+}
+
+func columnsEdit(e *env, args []string) (err error) {
+	fs := e.flagSet("columns edit")
+	boardID := fs.String("board", "", "board ID")
+	columnID := fs.String("column", "", "column ID")
+	name := fs.String("name", "", "column name")
+	position := fs.Int("position", 0, "column position")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *columnID, positional, "column")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	// unspecified values keep the column's current values
+	board, err := e.client.GetBoard(*boardID, glo.Fields(glo.BoardFieldColumns))
+	if err != nil {
+		return
+	}
+	var current *glo.Column
+	for _, col := range board.Columns {
+		if col.ID == id {
+			current = col
+		}
+	}
+	if current == nil {
+		err = fmt.Errorf("column %s not found on board %s", id, *boardID)
+		return
+	}
+
+	input := &glo.ColumnInput{Name: current.Name, Position: current.Position}
+	set := setFlags(fs)
+	if set["name"] {
+		input.Name = *name
+	}
+	if set["position"] {
+		input.Position = *position
+	}
+
+	col, err := e.client.EditColumn(*boardID, id, input)
+	if err != nil {
+		return
+	}
+
+	return e.renderColumns([]*glo.Column{col})
+}
+
+func columnsDelete(e *env, args []string) (err error) {
+	fs := e.flagSet("columns delete")
+	boardID := fs.String("board", "", "board ID")
+	columnID := fs.String("column", "", "column ID")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *columnID, positional, "column")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	err = e.client.DeteleColumn(*boardID, id)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(e.stderr, "deleted column", id)
+
+	return
+}
+
+func (e *env) renderColumns(columns []*glo.Column) error {
+	sorted := append([]*glo.Column{}, columns...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Position < sorted[j].Position
+	})
+
+	var rows [][]string
+	for _, col := range sorted {
+		rows = append(rows, []string{
+			col.ID,
+			col.Name,
+			fmt.Sprint(col.Position),
+			col.CreatedDate,
+			col.ArchivedDate,
+		})
+	}
+
+	return e.render(
+		sorted,
+		[]string{"ID", "NAME", "POSITION", "CREATED", "ARCHIVED"},
+		rows,
+	)
+}
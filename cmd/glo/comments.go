@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func commentsCmd(e *env, args []string) error {
+	return subcommands(e, "comments", args, map[string]command{
+		"list":   commentsList,
+		"add":    commentsAdd,
+		"edit":   commentsEdit,
+		"delete": commentsDelete,
+	})
+}
+
+func commentsList(e *env, args []string) (err error) {
+	fs := e.flagSet("comments list")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	p := &paging{}
+	p.register(fs, false)
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+	if err = require(fs, "board", "card"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	resp, err := e.client.GetComments(*boardID, *cardID, p.page, p.limit, p.desc)
+	if err != nil {
+		return
+	}
+
+	return e.renderComments(resp.Comments)
+}
+
+func commentsAdd(e *env, args []string) (err error) {
+	fs := e.flagSet("comments add")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	queue := fs.Bool("queue", false, "queue the comment in the outbox when the API is unreachable")
+	text := fs.String("text", "", "comment text (markdown)")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if *text == "" {
+		*text = strings.Join(positional, " ")
+	}
+	if *text == "" {
+		err = fmt.Errorf("%s: comment text is required", fs.Name())
+		return
+	}
+	if err = require(fs, "board", "card"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	input := &glo.CommentInput{Text: *text}
+
+	var comment *glo.Comment
+	if *queue {
+		o, openErr := e.openOutbox("")
+		if openErr != nil {
+			return openErr
+		}
+
+		var queued bool
+		comment, queued, err = o.CreateComment(*boardID, *cardID, input)
+		if queued {
+			e.queued("comment", o.Len())
+		}
+	} else {
+		comment, err = e.client.CreateComment(*boardID, *cardID, input)
+	}
+	if err != nil {
+		return
+	}
+
+	return e.renderComments([]*glo.Comment{comment})
+}
+
+func commentsEdit(e *env, args []string) (err error) {
+	fs := e.flagSet("comments edit")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	commentID := fs.String("comment", "", "comment ID")
+	text := fs.String("text", "", "comment text (markdown)")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *commentID, positional, "comment")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board", "card", "text"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	comment, err := e.client.EditComment(*boardID, *cardID, id, &glo.CommentInput{Text: *text})
+	if err != nil {
+		return
+	}
+
+	return e.renderComments([]*glo.Comment{comment})
+}
+
+func commentsDelete(e *env, args []string) (err error) {
+	fs := e.flagSet("comments delete")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	commentID := fs.String("comment", "", "comment ID")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *commentID, positional, "comment")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board", "card"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	err = e.client.DeleteComment(*boardID, *cardID, id)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(e.stderr, "deleted comment", id)
+
+	return
+}
+
+func (e *env) renderComments(comments []*glo.Comment) error {
+	var rows [][]string
+	for _, comment := range comments {
+		var author string
+		if comment.CreatedBy != nil {
+			author = comment.CreatedBy.ID
+		}
+		rows = append(rows, []string{
+			comment.ID,
+			author,
+			comment.CreatedDate,
+			truncate(comment.Text, 60),
+		})
+	}
+
+	return e.render(
+		comments,
+		[]string{"ID", "AUTHOR", "CREATED", "TEXT"},
+		rows,
+	)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/boarddiff"
+)
+
+var diffRenderers = map[string]func(io.Writer, *boarddiff.Report) error{
+	"text":     boarddiff.Text,
+	"markdown": boarddiff.Markdown,
+	"md":       boarddiff.Markdown,
+	"json":     boarddiff.JSON,
+}
+
+// diffCmd reports the changes between a saved snapshot of a
+// board and either a later snapshot or the board as it is now
+func diffCmd(e *env, args []string) (err error) {
+	fs := e.flagSet("diff")
+	boardID := fs.String("board", "", "board ID, defaults to the board of the snapshot")
+	format := fs.String("format", "text", "report format: text, markdown or json")
+	out := fs.String("o", "", "output file, defaults to stdout")
+	archived := fs.Bool("archived", false, "include archived cards when capturing the board")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) == 0 || len(positional) > 2 {
+		err = fmt.Errorf("%s: expected a snapshot file and, optionally, a later snapshot file", fs.Name())
+		return
+	}
+	if e.output == "json" {
+		*format = "json"
+	}
+
+	render, ok := diffRenderers[*format]
+	if !ok {
+		err = fmt.Errorf("%s: unsupported format %q", fs.Name(), *format)
+		return
+	}
+
+	old, err := boarddiff.Load(positional[0])
+	if err != nil {
+		return
+	}
+
+	var current *glo.BoardSnapshot
+	if len(positional) == 2 {
+		current, err = boarddiff.Load(positional[1])
+	} else {
+		if *boardID == "" {
+			*boardID = old.Board.ID
+		}
+		if err = e.connect(); err != nil {
+			return
+		}
+		current, err = e.client.Snapshot(*boardID, &glo.SnapshotOptions{Archived: *archived})
+	}
+	if err != nil {
+		return
+	}
+
+	report, err := boarddiff.Diff(old, current)
+	if err != nil {
+		return
+	}
+
+	w := e.stdout
+	if *out != "" {
+		f, createErr := os.Create(*out)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+
+	return render(w, report)
+}
+
+// writeSnapshot writes a snapshot as it is read by glo diff
+func writeSnapshot(w io.Writer, snap *glo.BoardSnapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(snap)
+}
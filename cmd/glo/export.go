@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/export"
+)
+
+var exporters = map[string]func(io.Writer, *glo.BoardSnapshot) error{
+	"csv":      export.CSV,
+	"markdown": export.Markdown,
+	"md":       export.Markdown,
+	"json":     export.JSON,
+	"snapshot": writeSnapshot,
+}
+
+// exportCmd writes a snapshot of a board as CSV, Markdown or JSON
+func exportCmd(e *env, args []string) (err error) {
+	fs := e.flagSet("export")
+	boardID := fs.String("board", "", "board ID")
+	format := fs.String("format", "markdown", "export format: csv, markdown, json or snapshot")
+	out := fs.String("o", "", "output file, defaults to stdout")
+	comments := fs.Bool("comments", false, "include card comments")
+	archived := fs.Bool("archived", false, "include archived cards")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+
+	exporter, ok := exporters[*format]
+	if !ok {
+		err = fmt.Errorf("%s: unsupported format %q", fs.Name(), *format)
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	snap, err := e.client.Snapshot(id, &glo.SnapshotOptions{
+		Archived: *archived,
+		Comments: *comments,
+	})
+	if err != nil {
+		return
+	}
+
+	w := e.stdout
+	if *out != "" {
+		f, createErr := os.Create(*out)
+		if createErr != nil {
+			return createErr
+		}
+		defer func() {
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+		}()
+		w = f
+	}
+
+	return exporter(w, snap)
+}
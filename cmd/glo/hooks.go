@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/jackmcguire1/go-glo/gitlink"
+)
+
+// hooksCmd installs and runs git hooks referencing the
+// card of the current branch
+func hooksCmd(e *env, args []string) error {
+	return subcommands(e, "hooks", args, map[string]command{
+		"install":   hooksInstall,
+		"uninstall": hooksUninstall,
+		"run":       hooksRun,
+	})
+}
+
+func hooksInstall(e *env, args []string) (err error) {
+	fs := e.flagSet("hooks install")
+	repoDir := fs.String("repo", ".", "git repository")
+	force := fs.Bool("force", false, "replace existing hooks")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	command, err := os.Executable()
+	if err != nil {
+		command = "glo"
+	}
+
+	repo := &gitlink.Repository{Dir: *repoDir}
+	paths, err := repo.InstallHooks(command, *force)
+	if err != nil {
+		return
+	}
+	for _, path := range paths {
+		fmt.Fprintf(e.stdout, "installed %s\n", path)
+	}
+
+	board, err := repo.Config("glo.board")
+	if err == nil && board == "" {
+		fmt.Fprintln(e.stdout, `set the board commits are linked to with "git config glo.board <board>"`)
+	}
+
+	return
+}
+
+func hooksUninstall(e *env, args []string) (err error) {
+	fs := e.flagSet("hooks uninstall")
+	repoDir := fs.String("repo", ".", "git repository")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	paths, err := (&gitlink.Repository{Dir: *repoDir}).UninstallHooks()
+	for _, path := range paths {
+		fmt.Fprintf(e.stdout, "removed %s\n", path)
+	}
+
+	return
+}
+
+// hooksRun runs a hook, git runs hooks from the top of the working tree
+func hooksRun(e *env, args []string) error {
+	return subcommands(e, "hooks run", args, map[string]command{
+		"prepare-commit-msg": hooksPrepareCommitMsg,
+		"post-commit":        hooksPostCommit,
+	})
+}
+
+// hooksPrepareCommitMsg prefixes the message with the branch's card
+//
+//	prepare-commit-msg <file> [message|template|merge|squash|commit] [sha]
+func hooksPrepareCommitMsg(e *env, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("hooks run prepare-commit-msg: expected the message file")
+	}
+	if len(args) > 1 {
+		switch args[1] {
+		case "merge", "squash", "commit":
+			// leave merges and amended commits alone
+			return
+		}
+	}
+
+	repo := &gitlink.Repository{Dir: "."}
+	branch, err := repo.Branch()
+	if err != nil {
+		return
+	}
+	cardID := gitlink.CardFromBranch(branch)
+	if cardID == "" {
+		return
+	}
+
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return
+	}
+
+	message := gitlink.PrepareMessage(string(data), cardID)
+	if message == string(data) {
+		return
+	}
+
+	return ioutil.WriteFile(args[0], []byte(message), 0644)
+}
+
+// hooksPostCommit links the new commit to the cards it references
+func hooksPostCommit(e *env, args []string) (err error) {
+	repo := &gitlink.Repository{Dir: "."}
+	boardID, err := repo.Config("glo.board")
+	if err != nil || boardID == "" {
+		return
+	}
+	doneColumn, err := repo.Config("glo.doneColumn")
+	if err != nil {
+		return
+	}
+	commitURL, err := repo.Config("glo.commitUrl")
+	if err != nil {
+		return
+	}
+
+	commits, err := repo.Log("-1", "HEAD")
+	if err != nil || len(commits) == 0 {
+		return
+	}
+	if len(gitlink.ParseReferences(commits[0].Message, nil)) == 0 {
+		return
+	}
+
+	path, err := gitlink.DefaultStatePath(repo)
+	if err != nil {
+		return
+	}
+	state, err := gitlink.LoadState(path)
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	links, err := (&gitlink.Linker{
+		Client:     e.client,
+		BoardID:    boardID,
+		DoneColumn: doneColumn,
+		CommitURL:  commitURL,
+		State:      state,
+	}).Link(commits)
+	for _, link := range links {
+		if link.Error != "" {
+			fmt.Fprintf(e.stderr, "glo: failed to link %s: %s\n", gitlink.Format(link.CardID), link.Error)
+		}
+	}
+
+	return
+}
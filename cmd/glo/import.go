@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jackmcguire1/go-glo"
+
+	"github.com/jackmcguire1/go-glo/importer"
+	"github.com/jackmcguire1/go-glo/importer/csvimport"
+	"github.com/jackmcguire1/go-glo/importer/trello"
+)
+
+// importCmd creates boards from other tools' exports
+func importCmd(e *env, args []string) error {
+	return subcommands(e, "import", args, map[string]command{
+		"trello": importTrello,
+		"csv":    importCSV,
+	})
+}
+
+func importTrello(e *env, args []string) (err error) {
+	fs := e.flagSet("import trello")
+	name := fs.String("name", "", "name of the created board, defaults to the trello board's name")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported without making changes")
+	members := fs.String("members", "", "JSON file mapping trello member IDs or usernames to glo user IDs")
+	closed := fs.Bool("closed", false, "include archived lists and cards")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("%s: expected the path of a trello JSON export", fs.Name())
+	}
+
+	f, err := os.Open(positional[0])
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	export, err := trello.Parse(f)
+	if err != nil {
+		return
+	}
+
+	opts := &trello.Options{
+		BoardName:     *name,
+		DryRun:        *dryRun,
+		IncludeClosed: *closed,
+	}
+	if *members != "" {
+		opts.Members, err = importer.LoadMemberMap(*members)
+		if err != nil {
+			return
+		}
+	}
+	if !*dryRun {
+		if err = e.connect(); err != nil {
+			return
+		}
+	}
+
+	report, err := trello.Import(e.client, export, opts)
+	if report != nil {
+		if reportErr := e.report(report); err == nil {
+			err = reportErr
+		}
+	}
+
+	return
+}
+
+func importCSV(e *env, args []string) (err error) {
+	fs := e.flagSet("import csv")
+	boardID := fs.String("board", "", "board ID")
+	jira := fs.Bool("jira", false, "map the columns of a jira issue export")
+	mapSpec := fs.String("map", "", "comma separated field=header overrides, e.g. name=Title,column=State")
+	defaultColumn := fs.String("default-column", "", "column of rows without one")
+	members := fs.String("members", "", "JSON file mapping assignees to glo user IDs")
+	batch := fs.Int("batch", glo.MaxCardsBatch, "cards created per request")
+	dryRun := fs.Bool("dry-run", false, "validate the file and report changes without making them")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		return fmt.Errorf("%s: expected the path of a CSV file", fs.Name())
+	}
+
+	base := csvimport.DefaultMapping
+	if *jira {
+		base = csvimport.JiraMapping
+	}
+	mapping, err := csvimport.ParseMapping(base, *mapSpec)
+	if err != nil {
+		return
+	}
+
+	opts := &csvimport.Options{
+		Mapping:       &mapping,
+		DefaultColumn: *defaultColumn,
+		BatchSize:     *batch,
+		DryRun:        *dryRun,
+	}
+	if *members != "" {
+		opts.Members, err = importer.LoadMemberMap(*members)
+		if err != nil {
+			return
+		}
+	}
+
+	f, err := os.Open(positional[0])
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	report, err := csvimport.Import(e.client, *boardID, f, opts)
+	if report != nil {
+		if reportErr := e.report(report); err == nil {
+			err = reportErr
+		}
+	}
+
+	return
+}
+
+// report writes an import report as JSON or as its summary
+func (e *env) report(report io.WriterTo) (err error) {
+	if e.output == "json" {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	_, err = report.WriteTo(e.stdout)
+
+	return
+}
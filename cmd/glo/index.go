@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo/fulltext"
+)
+
+// indexCmd maintains and searches the local full-text index
+func indexCmd(e *env, args []string) error {
+	return subcommands(e, "index", args, map[string]command{
+		"sync":   indexSync,
+		"search": indexSearch,
+	})
+}
+
+// indexPath the index file, stored alongside the config file
+func (e *env) indexPath(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if e.config != "" {
+		return filepath.Join(filepath.Dir(e.config), "index.gob"), nil
+	}
+
+	return fulltext.DefaultPath()
+}
+
+func indexSync(e *env, args []string) (err error) {
+	fs := e.flagSet("index sync")
+	boards := fs.String("board", "", "comma separated board IDs, defaults to every board")
+	archived := fs.Bool("archived", false, "index archived cards")
+	indexFile := fs.String("index", "", "index file")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	path, err := e.indexPath(*indexFile)
+	if err != nil {
+		return
+	}
+	ix, err := fulltext.Load(path)
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	stats, err := ix.Sync(e.client, splitList(*boards), &fulltext.SyncOptions{Archived: *archived})
+	if err != nil {
+		return
+	}
+	if err = ix.Save(path); err != nil {
+		return
+	}
+
+	if e.output == "json" {
+		return e.render(stats, nil, nil)
+	}
+	fmt.Fprintf(
+		e.stdout,
+		"synced %d boards: %d cards indexed, %d unchanged, %d removed, %d in the index\n",
+		stats.Boards,
+		stats.Indexed,
+		stats.Unchanged,
+		stats.Removed,
+		ix.Len(),
+	)
+
+	return
+}
+
+func indexSearch(e *env, args []string) (err error) {
+	fs := e.flagSet("index search")
+	boards := fs.String("board", "", "comma separated board names or IDs")
+	columns := fs.String("column", "", "comma separated column names or IDs")
+	labels := fs.String("label", "", "comma separated label names or IDs")
+	archived := fs.Bool("archived", false, "include archived cards")
+	limit := fs.Int("limit", 20, "maximum number of results, 0 for all")
+	indexFile := fs.String("index", "", "index file")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	path, err := e.indexPath(*indexFile)
+	if err != nil {
+		return
+	}
+	ix, err := fulltext.Load(path)
+	if err != nil {
+		return
+	}
+	if ix.Len() == 0 {
+		return fmt.Errorf(`%s: the index is empty, run "glo index sync" first`, fs.Name())
+	}
+
+	results, err := ix.Search(strings.Join(positional, " "), &fulltext.SearchOptions{
+		Boards:   splitList(*boards),
+		Columns:  splitList(*columns),
+		Labels:   splitList(*labels),
+		Archived: *archived,
+		Limit:    *limit,
+	})
+	if err != nil {
+		return
+	}
+
+	var rows [][]string
+	for _, result := range results {
+		board, column := result.BoardID, result.ColumnID
+		if info := ix.Board(result.BoardID); info != nil {
+			board = info.Name
+			if name, ok := info.Columns[result.ColumnID]; ok {
+				column = name
+			}
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%.2f", result.Score),
+			truncate(board, 20),
+			truncate(column, 20),
+			result.CardID,
+			truncate(result.Name, 50),
+		})
+	}
+
+	return e.render(results, []string{"SCORE", "BOARD", "COLUMN", "ID", "NAME"}, rows)
+}
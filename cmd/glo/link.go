@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo/gitlink"
+)
+
+// linkCmd comments on the cards referenced by commits of a git repository,
+// unset flags fall back to the repository's glo.* git config
+func linkCmd(e *env, args []string) (err error) {
+	fs := e.flagSet("link")
+	repoDir := fs.String("repo", ".", "git repository")
+	boardID := fs.String("board", "", "board ID, defaults to git config glo.board")
+	doneColumn := fs.String("done-column", "", "column closed cards are moved to, defaults to git config glo.doneColumn")
+	commitURL := fs.String("commit-url", "", "format string linking to a commit, defaults to git config glo.commitUrl")
+	statePath := fs.String("state", "", "file recording linked commits, defaults to .git/glo/links.json")
+	dryRun := fs.Bool("dry-run", false, "report the links which would be made")
+	revisions, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	repo := &gitlink.Repository{Dir: *repoDir}
+	for _, setting := range []struct {
+		value *string
+		key   string
+	}{
+		{boardID, "glo.board"},
+		{doneColumn, "glo.doneColumn"},
+		{commitURL, "glo.commitUrl"},
+	} {
+		if *setting.value != "" {
+			continue
+		}
+		*setting.value, err = repo.Config(setting.key)
+		if err != nil {
+			return
+		}
+	}
+	if *boardID == "" {
+		return fmt.Errorf("%s: a board is required, pass --board or set git config glo.board", fs.Name())
+	}
+
+	if *statePath == "" {
+		*statePath, err = gitlink.DefaultStatePath(repo)
+		if err != nil {
+			return
+		}
+	}
+	state, err := gitlink.LoadState(*statePath)
+	if err != nil {
+		return
+	}
+
+	commits, err := repo.Log(revisions...)
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	linker := &gitlink.Linker{
+		Client:     e.client,
+		BoardID:    *boardID,
+		DoneColumn: *doneColumn,
+		CommitURL:  *commitURL,
+		State:      state,
+		DryRun:     *dryRun,
+	}
+	links, err := linker.Link(commits)
+	if err != nil {
+		return
+	}
+
+	var rows [][]string
+	failed := 0
+	for _, link := range links {
+		var actions []string
+		switch {
+		case link.Error != "":
+			failed++
+			actions = append(actions, "error: "+link.Error)
+		case link.Skipped:
+			actions = append(actions, "already linked")
+		}
+		if link.Commented {
+			actions = append(actions, "commented")
+		}
+		if link.Moved {
+			actions = append(actions, "moved")
+		}
+		rows = append(rows, []string{
+			link.Commit.ShortHash(),
+			link.CardID,
+			strings.Join(actions, ", "),
+			truncate(link.Commit.Subject(), 50),
+		})
+	}
+
+	err = e.render(links, []string{"COMMIT", "CARD", "ACTION", "SUBJECT"}, rows)
+	if err == nil && failed > 0 {
+		err = fmt.Errorf("%d of %d references could not be linked", failed, len(links))
+	}
+
+	return
+}
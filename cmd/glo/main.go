@@ -0,0 +1,280 @@
+// Command glo is a command-line client for the GitKraken Glo Boards API.
+//
+//	glo [--profile name] [--output table|json] <command> <subcommand> [flags]
+//
+// Credentials are resolved with glo.LoadCredentials.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+const usage = `usage: glo [--profile name] [--output table|json] <command> [args]
+
+commands:
+  boards       list, get, create, edit, delete, view, clone, plan or apply boards
+  columns      list, create, edit or delete columns
+  cards        list, get, create, edit or delete cards
+  search       find cards matching a query across boards
+  mirror       sync or read the offline mirror of boards
+  index        sync or search the local full-text index of cards and comments
+  tasks        list, check, uncheck, toggle, add or remove a card's tasks
+  comments     list, add, edit or delete comments
+  attachments  list attachments
+  attach       upload a file as an attachment
+  user         show the authenticated user
+  templates    list, save, apply or delete board templates
+  export       export a board as csv, markdown, json or a snapshot
+  diff         report the changes to a board since a snapshot
+  analytics    report cumulative flow, cycle time, throughput and wip from snapshots
+  calendar     write or serve an icalendar feed of due cards
+  link         comment on the cards referenced by git commits
+  hooks        install git hooks referencing the card of the current branch
+  import       import a board from a trello export or cards from a CSV file
+  outbox       list, replay or discard changes queued while offline
+
+run "glo <command> --help" for the flags of a command.
+`
+
+// globals flags accepted by every command
+type globals struct {
+	profile string
+	config  string
+	output  string
+}
+
+// env shared state passed to every command
+type env struct {
+	globals
+	stdout io.Writer
+	stderr io.Writer
+	client *glo.Glo
+}
+
+type command func(e *env, args []string) error
+
+var commands = map[string]command{
+	"boards":      boardsCmd,
+	"board":       boardsCmd,
+	"columns":     columnsCmd,
+	"column":      columnsCmd,
+	"cards":       cardsCmd,
+	"card":        cardsCmd,
+	"search":      searchCmd,
+	"index":       indexCmd,
+	"mirror":      mirrorCmd,
+	"tasks":       tasksCmd,
+	"task":        tasksCmd,
+	"comments":    commentsCmd,
+	"comment":     commentsCmd,
+	"attachments": attachmentsCmd,
+	"attachment":  attachmentsCmd,
+	"attach":      attachCmd,
+	"user":        userCmd,
+	"templates":   templatesCmd,
+	"template":    templatesCmd,
+	"export":      exportCmd,
+	"diff":        diffCmd,
+	"analytics":   analyticsCmd,
+	"import":      importCmd,
+	"calendar":    calendarCmd,
+	"link":        linkCmd,
+	"hooks":       hooksCmd,
+	"outbox":      outboxCmd,
+}
+
+// errUsage returned when the command line is invalid
+// and usage has already been printed
+var errUsage = errors.New("usage")
+
+func main() {
+	e := &env{
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+
+	err := run(e, os.Args[1:])
+	if err == errUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(e.stderr, "glo:", err)
+		os.Exit(1)
+	}
+}
+
+func run(e *env, args []string) (err error) {
+	fs := e.flagSet("glo")
+	fs.Usage = func() {
+		fmt.Fprint(e.stderr, usage)
+	}
+	err = fs.Parse(args)
+	if err == flag.ErrHelp {
+		return nil
+	}
+	if err != nil {
+		return errUsage
+	}
+
+	args = fs.Args()
+	if len(args) == 0 {
+		fmt.Fprint(e.stderr, usage)
+		return errUsage
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "glo: unknown command %q\n\n%s", args[0], usage)
+		return errUsage
+	}
+
+	err = cmd(e, args[1:])
+	if err == flag.ErrHelp {
+		return nil
+	}
+
+	return err
+}
+
+// flagSet creates a flag set which also accepts the global flags
+func (e *env) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.StringVar(&e.profile, "profile", e.profile, "config file profile")
+	fs.StringVar(&e.config, "config", e.config, "config file path")
+	fs.StringVar(&e.output, "output", e.output, "output format: table or json")
+
+	return fs
+}
+
+// parse parses flags which may be interspersed with positional
+// arguments, returning the positional arguments
+func (e *env) parse(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		err = fs.Parse(args)
+		if err != nil {
+			if err != flag.ErrHelp {
+				err = errUsage
+			}
+			return
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			break
+		}
+		if args[0] == "--" {
+			positional = append(positional, args[1:]...)
+			break
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if e.output != "" && e.output != "table" && e.output != "json" {
+		err = fmt.Errorf("unsupported output format %q", e.output)
+	}
+
+	return
+}
+
+// connect resolves credentials and constructs the API client
+func (e *env) connect() (err error) {
+	if e.client != nil {
+		return
+	}
+
+	creds, err := glo.LoadCredentials(&glo.CredentialsInput{
+		Profile:    e.profile,
+		ConfigPath: e.config,
+	})
+	if err != nil {
+		return
+	}
+	e.client = creds.NewClient()
+
+	return
+}
+
+// subcommands dispatches to a named subcommand
+func subcommands(
+	e *env,
+	name string,
+	args []string,
+	subs map[string]command,
+) error {
+	var names []string
+	for sub := range subs {
+		names = append(names, sub)
+	}
+	sort.Strings(names)
+
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprintf(e.stderr, "usage: glo %s <%s> [flags]\n", name, strings.Join(names, "|"))
+		if len(args) == 0 {
+			return errUsage
+		}
+		return nil
+	}
+
+	sub, ok := subs[args[0]]
+	if !ok {
+		fmt.Fprintf(
+			e.stderr,
+			"glo %s: unknown subcommand %q, expected one of %s\n",
+			name,
+			args[0],
+			strings.Join(names, ", "),
+		)
+		return errUsage
+	}
+
+	err := sub(e, args[1:])
+	if err == flag.ErrHelp {
+		return nil
+	}
+
+	return err
+}
+
+// require ensures flags have been provided
+func require(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		f := fs.Lookup(name)
+		if f == nil || f.Value.String() == "" {
+			return fmt.Errorf("%s: --%s is required", fs.Name(), name)
+		}
+	}
+
+	return nil
+}
+
+// idArg resolves an ID from a flag or the first positional argument
+func idArg(fs *flag.FlagSet, flagValue string, args []string, name string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+	if len(args) > 0 {
+		return args[0], nil
+	}
+
+	return "", fmt.Errorf("%s: %s ID is required", fs.Name(), name)
+}
+
+// setFlags the names of flags explicitly provided on the command line
+func setFlags(fs *flag.FlagSet) map[string]bool {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	return set
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/jackmcguire1/go-glo/mirror"
+)
+
+// mirrorCmd syncs and reads the offline mirror of boards
+func mirrorCmd(e *env, args []string) error {
+	return subcommands(e, "mirror", args, map[string]command{
+		"sync":    mirrorSync,
+		"boards":  mirrorBoards,
+		"cards":   mirrorCards,
+		"deleted": mirrorDeleted,
+	})
+}
+
+// openMirror the mirror in dir, or alongside the config file
+func (e *env) openMirror(dir string) (m *mirror.Mirror, err error) {
+	if dir == "" && e.config != "" {
+		dir = filepath.Join(filepath.Dir(e.config), "mirror")
+	}
+	if dir == "" {
+		dir, err = mirror.DefaultDir()
+		if err != nil {
+			return
+		}
+	}
+
+	return mirror.Open(e.client, dir), nil
+}
+
+func mirrorSync(e *env, args []string) (err error) {
+	fs := e.flagSet("mirror sync")
+	boards := fs.String("board", "", "comma separated board IDs, defaults to every board")
+	dir := fs.String("dir", "", "mirror directory")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	m, err := e.openMirror(*dir)
+	if err != nil {
+		return
+	}
+
+	stats, err := m.Sync(splitList(*boards)...)
+	if err != nil {
+		return
+	}
+
+	if e.output == "json" {
+		return e.render(stats, nil, nil)
+	}
+	fmt.Fprintf(
+		e.stdout,
+		"synced %d boards: %d cards, %d updated, %d unchanged, %d deleted\n",
+		stats.Boards,
+		stats.Cards,
+		stats.Updated,
+		stats.Unchanged,
+		stats.Deleted,
+	)
+
+	return
+}
+
+func mirrorBoards(e *env, args []string) (err error) {
+	fs := e.flagSet("mirror boards")
+	archived := fs.Bool("archived", false, "list archived boards")
+	dir := fs.String("dir", "", "mirror directory")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	m, err := e.openMirror(*dir)
+	if err != nil {
+		return
+	}
+	boards, err := m.GetBoards(*archived)
+	if err != nil {
+		return
+	}
+
+	return e.renderBoards(boards)
+}
+
+func mirrorCards(e *env, args []string) (err error) {
+	fs := e.flagSet("mirror cards")
+	boardID := fs.String("board", "", "board ID")
+	column := fs.String("column", "", "only list the cards of a column")
+	archived := fs.Bool("archived", false, "list archived cards")
+	dir := fs.String("dir", "", "mirror directory")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+
+	m, err := e.openMirror(*dir)
+	if err != nil {
+		return
+	}
+
+	cards, err := m.GetCards(id, *archived)
+	if *column != "" {
+		cards, err = m.CardsByColumn(id, *column, *archived)
+	}
+	if err != nil {
+		return
+	}
+
+	return e.renderCards(cards)
+}
+
+func mirrorDeleted(e *env, args []string) (err error) {
+	fs := e.flagSet("mirror deleted")
+	boardID := fs.String("board", "", "only list a board's deletions")
+	dir := fs.String("dir", "", "mirror directory")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	m, err := e.openMirror(*dir)
+	if err != nil {
+		return
+	}
+	tombstones, err := m.Deleted(*boardID)
+	if err != nil {
+		return
+	}
+
+	var rows [][]string
+	for _, tombstone := range tombstones {
+		rows = append(rows, []string{
+			tombstone.Kind,
+			tombstone.ID,
+			tombstone.BoardID,
+			truncate(tombstone.Name, 40),
+			tombstone.DeletedAt.Format(time.RFC3339),
+		})
+	}
+
+	return e.render(tombstones, []string{"KIND", "ID", "BOARD", "NAME", "DELETED"}, rows)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/outbox"
+)
+
+// outboxCmd lists, replays and discards mutations queued while offline
+func outboxCmd(e *env, args []string) error {
+	return subcommands(e, "outbox", args, map[string]command{
+		"list":    outboxList,
+		"replay":  outboxReplay,
+		"discard": outboxDiscard,
+	})
+}
+
+// openOutbox the journal at path, or alongside the config file
+func (e *env) openOutbox(path string) (o *outbox.Outbox, err error) {
+	if path == "" && e.config != "" {
+		path = filepath.Join(filepath.Dir(e.config), "outbox.jsonl")
+	}
+	if path == "" {
+		path, err = outbox.DefaultPath()
+		if err != nil {
+			return
+		}
+	}
+
+	return outbox.Open(e.client, path)
+}
+
+func outboxList(e *env, args []string) (err error) {
+	fs := e.flagSet("outbox list")
+	path := fs.String("journal", "", "outbox journal path")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	o, err := e.openOutbox(*path)
+	if err != nil {
+		return
+	}
+
+	pending := o.Pending()
+	failed := o.Failed()
+	entries := append(append([]*outbox.Entry{}, pending...), failed...)
+
+	var rows [][]string
+	for i, entry := range entries {
+		target := entry.CommentID
+		if target == "" {
+			target = entry.CardID
+		}
+		if target == "" {
+			target = entry.ColumnID
+		}
+		status := "queued"
+		if i >= len(pending) {
+			status = "failed"
+		}
+		rows = append(rows, []string{
+			strconv.FormatInt(entry.Seq, 10),
+			string(entry.Op),
+			entry.BoardID,
+			target,
+			entry.TempID,
+			entry.QueuedAt.Local().Format("2006-01-02 15:04"),
+			status,
+			entry.Error,
+		})
+	}
+
+	return e.render(
+		entries,
+		[]string{"SEQ", "OP", "BOARD", "TARGET", "TEMP ID", "QUEUED", "STATUS", "ERROR"},
+		rows,
+	)
+}
+
+func outboxReplay(e *env, args []string) (err error) {
+	fs := e.flagSet("outbox replay")
+	path := fs.String("journal", "", "outbox journal path")
+	onConflict := fs.String("on-conflict", "stop", "resolve conflicting changes: stop, skip or overwrite")
+	maxAttempts := fs.Int("max-attempts", outbox.DefaultMaxAttempts, "mark an entry failed once the API fails to serve it this many times")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	resolutions := map[string]outbox.Resolution{
+		"stop":      outbox.Stop,
+		"skip":      outbox.Skip,
+		"overwrite": outbox.Overwrite,
+	}
+	resolution, ok := resolutions[*onConflict]
+	if !ok {
+		err = fmt.Errorf("%s: unsupported conflict resolution %q", fs.Name(), *onConflict)
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	o, err := e.openOutbox(*path)
+	if err != nil {
+		return
+	}
+
+	result, err := o.Replay(&outbox.ReplayOptions{
+		OnConflict: func(conflict *outbox.ConflictError) outbox.Resolution {
+			if resolution != outbox.Stop {
+				fmt.Fprintf(e.stderr, "%s: %s %s\n", conflict, *onConflict, conflict.Entry.Op)
+			}
+			return resolution
+		},
+		MaxAttempts: *maxAttempts,
+	})
+	if e.output == "json" && result != nil {
+		if renderErr := e.render(result, nil, nil); renderErr != nil {
+			return renderErr
+		}
+	} else if result != nil {
+		fmt.Fprintf(
+			e.stdout,
+			"replayed %d, skipped %d, failed %d, %d remaining\n",
+			result.Replayed,
+			result.Skipped,
+			result.Failed,
+			result.Remaining,
+		)
+	}
+
+	var conflict *outbox.ConflictError
+	if errors.As(err, &conflict) {
+		err = fmt.Errorf("%s, rerun with --on-conflict skip or overwrite, or discard it", err)
+	}
+
+	return
+}
+
+func outboxDiscard(e *env, args []string) (err error) {
+	fs := e.flagSet("outbox discard")
+	path := fs.String("journal", "", "outbox journal path")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) == 0 {
+		err = fmt.Errorf("%s: entry sequence number is required", fs.Name())
+		return
+	}
+
+	o, err := e.openOutbox(*path)
+	if err != nil {
+		return
+	}
+
+	for _, arg := range positional {
+		seq, parseErr := strconv.ParseInt(arg, 10, 64)
+		if parseErr != nil {
+			return fmt.Errorf("%s: invalid sequence number %q", fs.Name(), arg)
+		}
+
+		err = o.Discard(seq)
+		if err != nil {
+			return
+		}
+		fmt.Fprintln(e.stderr, "discarded entry", seq)
+	}
+
+	return
+}
+
+// lastSeen the UpdatedDate of a card a queued change is based on, read
+// from the API or, when it is unreachable, from the mirror
+func (e *env) lastSeen(boardID, cardID string) (updated string, err error) {
+	// a card created while offline can not have changed since
+	if outbox.IsTempID(cardID) {
+		return
+	}
+
+	card, err := e.client.GetCard(boardID, cardID, glo.Fields(glo.CardFieldUpdatedDate))
+	if err == nil {
+		updated = card.UpdatedDate
+		return
+	}
+	if !outbox.Offline(err) {
+		return
+	}
+
+	m, err := e.openMirror("")
+	if err != nil {
+		return
+	}
+	card, err = m.GetCard(boardID, cardID)
+	if err != nil {
+		err = fmt.Errorf("the API is unreachable and %s, run \"glo mirror sync\" to queue changes to it", err)
+		return
+	}
+	updated = card.UpdatedDate
+
+	return
+}
+
+// queued reports a mutation was queued in the outbox
+func (e *env) queued(what string, pending int) {
+	fmt.Fprintf(e.stderr, "queued %s in the outbox, %d pending, run \"glo outbox replay\" once online\n", what, pending)
+}
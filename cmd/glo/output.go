@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// render writes v as JSON or as a table of rows
+// with the provided headers
+func (e *env) render(v interface{}, headers []string, rows [][]string) error {
+	if e.output == "json" {
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+
+	tw := tabwriter.NewWriter(e.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// userIDs joins the IDs of partial users
+func userIDs(users []*glo.PartialUser) string {
+	var ids []string
+	for _, user := range users {
+		if user != nil {
+			ids = append(ids, user.ID)
+		}
+	}
+
+	return strings.Join(ids, ",")
+}
+
+// labelNames joins the names of partial labels,
+// falling back to their IDs
+func labelNames(labels []*glo.PartialLabel) string {
+	var names []string
+	for _, label := range labels {
+		if label == nil {
+			continue
+		}
+		if label.Name != "" {
+			names = append(names, label.Name)
+			continue
+		}
+		names = append(names, label.ID)
+	}
+
+	return strings.Join(names, ",")
+}
+
+// splitList splits a comma separated flag value
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// truncate shortens s to n runes for table output
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	return string(r[:n-1]) + "…"
+}
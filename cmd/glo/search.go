@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/query"
+)
+
+// searchResult a matched card with its board
+type searchResult struct {
+	BoardID string    `json:"board_id"`
+	Board   string    `json:"board"`
+	Column  string    `json:"column"`
+	Card    *glo.Card `json:"card"`
+}
+
+// searchCmd lists the cards matching a query across boards
+func searchCmd(e *env, args []string) (err error) {
+	fs := e.flagSet("search")
+	boards := fs.String("board", "", "comma separated board IDs, defaults to every board")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	q, err := query.Parse(strings.Join(positional, " "))
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	boardIDs := splitList(*boards)
+	if len(boardIDs) == 0 {
+		boardIDs, err = e.searchBoards(q)
+		if err != nil {
+			return
+		}
+	}
+
+	env := &query.Env{}
+	if q.UsesMe() {
+		user, userErr := e.client.GetUser()
+		if userErr != nil {
+			return userErr
+		}
+		env.Me = user.ID
+	}
+
+	var results []*searchResult
+	for _, boardID := range boardIDs {
+		env.Board, err = e.client.GetBoard(boardID)
+		if err != nil {
+			return
+		}
+		if env.Board.ID == "" {
+			env.Board.ID = boardID
+		}
+
+		cards, cardsErr := e.client.AllCards(boardID, false)
+		if cardsErr != nil {
+			return cardsErr
+		}
+		if q.IncludesArchived() {
+			archived, cardsErr := e.client.AllCards(boardID, true)
+			if cardsErr != nil {
+				return cardsErr
+			}
+			cards = append(cards, archived...)
+		}
+
+		for _, card := range q.Filter(env, cards) {
+			results = append(results, &searchResult{
+				BoardID: boardID,
+				Board:   env.Board.Name,
+				Column:  columnName(env.Board, card.ColumnID),
+				Card:    card,
+			})
+		}
+	}
+
+	var rows [][]string
+	for _, result := range results {
+		rows = append(rows, []string{
+			truncate(result.Board, 20),
+			truncate(result.Column, 20),
+			result.Card.ID,
+			truncate(result.Card.Name, 40),
+			labelNames(result.Card.Labels),
+			result.Card.DueDate,
+		})
+	}
+
+	return e.render(results, []string{"BOARD", "COLUMN", "ID", "NAME", "LABELS", "DUE"}, rows)
+}
+
+// searchBoards the boards a query may match, those named by its board
+// terms or otherwise every board
+func (e *env) searchBoards(q *query.Query) (ids []string, err error) {
+	boards, err := e.client.AllBoards(false, glo.Fields(glo.BoardFieldName))
+	if err != nil {
+		return
+	}
+
+	wanted := q.Boards()
+	for _, board := range boards {
+		if len(wanted) == 0 {
+			ids = append(ids, board.ID)
+			continue
+		}
+		for _, want := range wanted {
+			if board.ID == want || strings.EqualFold(board.Name, want) {
+				ids = append(ids, board.ID)
+				break
+			}
+		}
+	}
+	if len(wanted) > 0 && len(ids) == 0 {
+		err = fmt.Errorf("no board matches %s", strings.Join(wanted, ", "))
+	}
+
+	return
+}
+
+// columnName the name of a column, or its ID when it is unknown
+func columnName(board *glo.Board, id string) string {
+	for _, group := range [][]*glo.Column{board.Columns, board.ArchivedColumns} {
+		for _, col := range group {
+			if col.ID == id {
+				return col.Name
+			}
+		}
+	}
+
+	return id
+}
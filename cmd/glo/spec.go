@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo/boardspec"
+)
+
+// boardsPlan shows the changes required for a board to match a spec
+func boardsPlan(e *env, args []string) (err error) {
+	fs := e.flagSet("boards plan")
+	prune := fs.Bool("prune", false, "delete columns and labels missing from the spec")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	plan, err := e.plan(fs.Name(), positional, *prune)
+	if err != nil {
+		return
+	}
+
+	return e.renderPlan(plan)
+}
+
+// boardsApply applies the changes required for a board to match a spec
+func boardsApply(e *env, args []string) (err error) {
+	fs := e.flagSet("boards apply")
+	prune := fs.Bool("prune", false, "delete columns and labels missing from the spec")
+	yes := fs.Bool("yes", false, "apply without asking for confirmation")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+
+	plan, err := e.plan(fs.Name(), positional, *prune)
+	if err != nil {
+		return
+	}
+
+	err = e.renderPlan(plan)
+	if err != nil || plan.Changes() == 0 {
+		return
+	}
+
+	if !*yes {
+		fmt.Fprint(e.stderr, "\napply these changes? [y/N] ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if !strings.EqualFold(strings.TrimSpace(answer), "y") {
+			fmt.Fprintln(e.stderr, "apply cancelled")
+			return
+		}
+	}
+
+	err = boardspec.Apply(e.client, plan)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(e.stderr, "applied %d changes to board %s\n", plan.Changes(), plan.BoardID)
+
+	return
+}
+
+func (e *env) plan(name string, positional []string, prune bool) (plan *boardspec.Plan, err error) {
+	if len(positional) != 1 {
+		err = fmt.Errorf("%s: exactly one spec file is required", name)
+		return
+	}
+
+	spec, err := boardspec.Load(positional[0])
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	board, err := boardspec.Find(e.client, spec)
+	if err != nil {
+		return
+	}
+
+	return boardspec.Compute(spec, board, &boardspec.Options{Prune: prune})
+}
+
+func (e *env) renderPlan(plan *boardspec.Plan) (err error) {
+	if e.output == "json" {
+		return e.render(plan, nil, nil)
+	}
+
+	_, err = plan.WriteTo(e.stdout)
+
+	return
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo/tasklist"
+)
+
+// tasksCmd lists and edits the task list of a card's description
+func tasksCmd(e *env, args []string) error {
+	return subcommands(e, "tasks", args, map[string]command{
+		"list":    tasksList,
+		"check":   tasksEdit("check"),
+		"uncheck": tasksEdit("uncheck"),
+		"toggle":  tasksEdit("toggle"),
+		"add":     tasksEdit("add"),
+		"remove":  tasksEdit("remove"),
+	})
+}
+
+func tasksList(e *env, args []string) (err error) {
+	fs := e.flagSet("tasks list")
+	boardID := fs.String("board", "", "board ID")
+	cardID := fs.String("card", "", "card ID")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *cardID, positional, "card")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "board"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	card, err := e.client.GetCard(*boardID, id)
+	if err != nil {
+		return
+	}
+
+	return e.renderTasks(card.Tasks())
+}
+
+// tasksEdit a subcommand applying an edit to a single task, selected
+// by its number or text, or for add, the text of the new task
+func tasksEdit(action string) command {
+	return func(e *env, args []string) (err error) {
+		fs := e.flagSet("tasks " + action)
+		boardID := fs.String("board", "", "board ID")
+		cardID := fs.String("card", "", "card ID")
+		done := false
+		if action == "add" {
+			fs.BoolVar(&done, "done", false, "add the task checked")
+		}
+		positional, err := e.parse(fs, args)
+		if err != nil {
+			return
+		}
+		if err = require(fs, "board", "card"); err != nil {
+			return
+		}
+		task := strings.Join(positional, " ")
+		if task == "" {
+			return fmt.Errorf("%s: a task number or text is required", fs.Name())
+		}
+		if err = e.connect(); err != nil {
+			return
+		}
+
+		var d *tasklist.Document
+		_, err = e.client.UpdateTasks(*boardID, *cardID, func(doc *tasklist.Document) (err error) {
+			d = doc
+			if action == "add" {
+				return doc.Add(task, done)
+			}
+
+			i, err := findTask(doc, task)
+			if err != nil {
+				return
+			}
+
+			switch action {
+			case "check":
+				return doc.SetDone(i, true)
+			case "uncheck":
+				return doc.SetDone(i, false)
+			case "toggle":
+				return doc.Toggle(i)
+			case "remove":
+				return doc.Remove(i)
+			}
+
+			return
+		})
+		if err != nil {
+			return
+		}
+
+		return e.renderTasks(d)
+	}
+}
+
+// findTask resolves a task by its number, starting at 1, or its text
+func findTask(d *tasklist.Document, task string) (i int, err error) {
+	if n, convErr := strconv.Atoi(task); convErr == nil {
+		if n < 1 || n > d.Len() {
+			return 0, fmt.Errorf("no task %d, the card has %d tasks", n, d.Len())
+		}
+		return n - 1, nil
+	}
+
+	i = d.Find(task)
+	if i < 0 {
+		err = fmt.Errorf("no task %q", task)
+	}
+
+	return
+}
+
+func (e *env) renderTasks(d *tasklist.Document) error {
+	tasks := d.Tasks()
+
+	var rows [][]string
+	for i, task := range tasks {
+		mark := "[ ]"
+		if task.Done {
+			mark = "[x]"
+		}
+		rows = append(rows, []string{strconv.Itoa(i + 1), mark, truncate(task.Text, 60)})
+	}
+
+	return e.render(tasks, []string{"#", "DONE", "TASK"}, rows)
+}
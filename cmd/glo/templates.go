@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/templates"
+)
+
+// boardsClone copies a board's layout, and optionally its cards, to a new board
+func boardsClone(e *env, args []string) (err error) {
+	fs := e.flagSet("boards clone")
+	boardID := fs.String("board", "", "board ID")
+	name := fs.String("name", "", "name of the new board")
+	cards := fs.Bool("cards", false, "copy cards")
+	descriptions := fs.Bool("descriptions", false, "copy card descriptions")
+	checklists := fs.Bool("checklists", false, "copy only the checklists of card descriptions")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "name"); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	board, err := e.client.CloneBoard(id, *name, &glo.CloneOptions{
+		Cards:        *cards,
+		Descriptions: *descriptions,
+		Checklists:   *checklists,
+	})
+	if err != nil {
+		return
+	}
+
+	return e.renderBoards([]*glo.Board{board})
+}
+
+func templatesCmd(e *env, args []string) error {
+	return subcommands(e, "templates", args, map[string]command{
+		"list":   templatesList,
+		"save":   templatesSave,
+		"apply":  templatesApply,
+		"delete": templatesDelete,
+	})
+}
+
+// registry the template registry, stored alongside the config file
+func (e *env) registry() (*templates.Registry, error) {
+	if e.config != "" {
+		return templates.NewRegistry(filepath.Join(filepath.Dir(e.config), "templates")), nil
+	}
+
+	return templates.DefaultRegistry()
+}
+
+func templatesList(e *env, args []string) (err error) {
+	fs := e.flagSet("templates list")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+
+	registry, err := e.registry()
+	if err != nil {
+		return
+	}
+	names, err := registry.List()
+	if err != nil {
+		return
+	}
+
+	var list []*templates.Template
+	var rows [][]string
+	for _, name := range names {
+		t, loadErr := registry.Load(name)
+		if loadErr != nil {
+			err = loadErr
+			return
+		}
+		list = append(list, t)
+		rows = append(rows, []string{
+			t.Name,
+			fmt.Sprint(len(t.Columns)),
+			fmt.Sprint(len(t.Labels)),
+			fmt.Sprint(len(t.Cards)),
+			strings.Join(t.Variables(), ","),
+		})
+	}
+
+	return e.render(
+		list,
+		[]string{"NAME", "COLUMNS", "LABELS", "CARDS", "VARIABLES"},
+		rows,
+	)
+}
+
+func templatesSave(e *env, args []string) (err error) {
+	fs := e.flagSet("templates save")
+	boardID := fs.String("board", "", "board ID")
+	name := fs.String("name", "", "template name")
+	cards := fs.Bool("cards", false, "include cards")
+	descriptions := fs.Bool("descriptions", false, "include card descriptions")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+	if err = require(fs, "name"); err != nil {
+		return
+	}
+	registry, err := e.registry()
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	t, err := templates.FromBoard(e.client, id, *name, &templates.FromBoardOptions{
+		Cards:        *cards,
+		Descriptions: *descriptions,
+	})
+	if err != nil {
+		return
+	}
+
+	err = registry.Save(t)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(e.stderr, "saved template %s to %s\n", t.Name, registry.Dir)
+
+	return
+}
+
+// varsFlag collects repeated --var name=value flags
+type varsFlag map[string]string
+
+func (v varsFlag) String() string {
+	var pairs []string
+	for name, value := range v {
+		pairs = append(pairs, name+"="+value)
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+func (v varsFlag) Set(s string) error {
+	kv := strings.SplitN(s, "=", 2)
+	if len(kv) != 2 || kv[0] == "" {
+		return fmt.Errorf("expected name=value")
+	}
+	v[kv[0]] = kv[1]
+
+	return nil
+}
+
+func templatesApply(e *env, args []string) (err error) {
+	fs := e.flagSet("templates apply")
+	name := fs.String("name", "", "name of the new board")
+	vars := varsFlag{}
+	fs.Var(vars, "var", "template variable as name=value, may be repeated")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		err = fmt.Errorf("%s: exactly one template name is required", fs.Name())
+		return
+	}
+	if err = require(fs, "name"); err != nil {
+		return
+	}
+
+	registry, err := e.registry()
+	if err != nil {
+		return
+	}
+	t, err := registry.Load(positional[0])
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	board, err := t.Instantiate(e.client, *name, vars)
+	if err != nil {
+		return
+	}
+
+	return e.renderBoards([]*glo.Board{board})
+}
+
+func templatesDelete(e *env, args []string) (err error) {
+	fs := e.flagSet("templates delete")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	if len(positional) != 1 {
+		err = fmt.Errorf("%s: exactly one template name is required", fs.Name())
+		return
+	}
+
+	registry, err := e.registry()
+	if err != nil {
+		return
+	}
+
+	return registry.Delete(positional[0])
+}
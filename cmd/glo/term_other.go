@@ -0,0 +1,21 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !openbsd
+// +build !linux,!darwin,!freebsd,!netbsd,!openbsd
+
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+var errNoRawMode = errors.New("raw terminal mode is not supported on this platform")
+
+// makeRaw is not supported, keys are read once Enter is pressed
+func makeRaw(f *os.File) (restore func(), err error) {
+	return nil, errNoRawMode
+}
+
+// terminalSize is not supported, a default size is used
+func terminalSize(f *os.File) (width, height int, err error) {
+	return 0, 0, errNoRawMode
+}
@@ -0,0 +1,58 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd
+// +build linux darwin freebsd netbsd openbsd
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// makeRaw disables line buffering and echo on the terminal,
+// returning a function which restores its previous state
+func makeRaw(f *os.File) (restore func(), err error) {
+	var old syscall.Termios
+	err = ioctl(f.Fd(), ioctlGetTermios, unsafe.Pointer(&old))
+	if err != nil {
+		return
+	}
+
+	raw := old
+	raw.Lflag &^= syscall.ECHO | syscall.ICANON
+	raw.Cc[syscall.VMIN] = 1
+	raw.Cc[syscall.VTIME] = 0
+
+	err = ioctl(f.Fd(), ioctlSetTermios, unsafe.Pointer(&raw))
+	if err != nil {
+		return
+	}
+
+	restore = func() {
+		ioctl(f.Fd(), ioctlSetTermios, unsafe.Pointer(&old))
+	}
+
+	return
+}
+
+// terminalSize the width and height of the terminal
+func terminalSize(f *os.File) (width, height int, err error) {
+	var ws struct {
+		Row, Col, Xpixel, Ypixel uint16
+	}
+	err = ioctl(f.Fd(), syscall.TIOCGWINSZ, unsafe.Pointer(&ws))
+	if err != nil {
+		return
+	}
+
+	return int(ws.Col), int(ws.Row), nil
+}
+
+func ioctl(fd uintptr, req uintptr, arg unsafe.Pointer) error {
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	if errno != 0 {
+		return errno
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package main
+
+// userCmd shows the authenticated user
+func userCmd(e *env, args []string) (err error) {
+	fs := e.flagSet("user")
+	if _, err = e.parse(fs, args); err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	user, err := e.client.GetUser()
+	if err != nil {
+		return
+	}
+
+	return e.render(
+		user,
+		[]string{"ID", "NAME", "USERNAME", "EMAIL"},
+		[][]string{{user.ID, user.Name, user.Username, user.Email}},
+	)
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// ANSI escape sequences used by the board viewer
+const (
+	ansiClear   = "\x1b[H\x1b[2J"
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiReverse = "\x1b[7m"
+	ansiHide    = "\x1b[?25l"
+	ansiShow    = "\x1b[?25h"
+)
+
+// keys produced by readKey
+const (
+	keyUp    = "up"
+	keyDown  = "down"
+	keyLeft  = "left"
+	keyRight = "right"
+	keyEnter = "enter"
+	keyEsc   = "esc"
+)
+
+const viewHelp = "←/→ h/l column  ↑/↓ j/k card  </> H/L move card  enter comments  r refresh  q quit"
+
+// boardView an interactive kanban view of a board
+type boardView struct {
+	e     *env
+	board *glo.Board
+
+	columns []*glo.Column
+	cards   [][]*glo.Card
+
+	col int
+	row int
+
+	// comments of the selected card, when showing comments
+	comments     []*glo.Comment
+	showComments bool
+
+	status string
+	width  int
+	height int
+}
+
+// boardsView renders a board's columns side by side with their cards
+func boardsView(e *env, args []string) (err error) {
+	fs := e.flagSet("boards view")
+	boardID := fs.String("board", "", "board ID")
+	positional, err := e.parse(fs, args)
+	if err != nil {
+		return
+	}
+	id, err := idArg(fs, *boardID, positional, "board")
+	if err != nil {
+		return
+	}
+	if err = e.connect(); err != nil {
+		return
+	}
+
+	v := &boardView{e: e}
+	err = v.load(id)
+	if err != nil {
+		return
+	}
+
+	keys := &keyReader{r: bufio.NewReader(os.Stdin)}
+	restore, rawErr := makeRaw(os.Stdin)
+	if rawErr == nil {
+		defer restore()
+	} else {
+		keys.lineMode = true
+		v.status = "line mode: press enter after each key"
+	}
+
+	fmt.Fprint(e.stdout, ansiHide)
+	defer fmt.Fprint(e.stdout, ansiShow+ansiClear)
+
+	return v.loop(keys)
+}
+
+// load fetches the board and the cards of each of its columns
+func (v *boardView) load(boardID string) (err error) {
+	board, err := v.e.client.GetBoard(boardID)
+	if err != nil {
+		return
+	}
+
+	columns := append([]*glo.Column{}, board.Columns...)
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Position < columns[j].Position
+	})
+
+	cards := make([][]*glo.Card, len(columns))
+	for i, col := range columns {
+		for page := 1; ; page++ {
+			resp, listErr := v.e.client.CardsByColumn(boardID, col.ID, page, 100, false, false)
+			if listErr != nil {
+				err = listErr
+				return
+			}
+			cards[i] = append(cards[i], resp.Cards...)
+			if !resp.HasMore {
+				break
+			}
+		}
+		sort.SliceStable(cards[i], func(a, b int) bool {
+			return cards[i][a].Position < cards[i][b].Position
+		})
+	}
+
+	v.board = board
+	v.columns = columns
+	v.cards = cards
+	v.clamp()
+
+	return
+}
+
+func (v *boardView) loop(keys *keyReader) (err error) {
+	for {
+		v.render()
+
+		key, readErr := keys.read()
+		if readErr == io.EOF {
+			return nil
+		}
+		if readErr != nil {
+			return readErr
+		}
+
+		if v.showComments {
+			switch key {
+			case "q", keyEsc, keyEnter, keyLeft, "h":
+				v.showComments = false
+			}
+			continue
+		}
+
+		v.status = ""
+		switch key {
+		case "q":
+			return nil
+		case keyLeft, "h":
+			v.col--
+		case keyRight, "l":
+			v.col++
+		case keyUp, "k":
+			v.row--
+		case keyDown, "j":
+			v.row++
+		case "<", "H":
+			v.move(-1)
+		case ">", "L":
+			v.move(1)
+		case keyEnter, "c":
+			v.openComments()
+		case "r":
+			if loadErr := v.load(v.board.ID); loadErr != nil {
+				v.status = loadErr.Error()
+			}
+		}
+		v.clamp()
+	}
+}
+
+// clamp keeps the selection within the board
+func (v *boardView) clamp() {
+	if v.col >= len(v.columns) {
+		v.col = len(v.columns) - 1
+	}
+	if v.col < 0 {
+		v.col = 0
+	}
+
+	count := 0
+	if v.col < len(v.cards) {
+		count = len(v.cards[v.col])
+	}
+	if v.row >= count {
+		v.row = count - 1
+	}
+	if v.row < 0 {
+		v.row = 0
+	}
+}
+
+func (v *boardView) selected() *glo.Card {
+	if v.col >= len(v.cards) || v.row >= len(v.cards[v.col]) {
+		return nil
+	}
+
+	return v.cards[v.col][v.row]
+}
+
+// move moves the selected card to a neighbouring column
+func (v *boardView) move(delta int) {
+	card := v.selected()
+	target := v.col + delta
+	if card == nil || target < 0 || target >= len(v.columns) {
+		return
+	}
+
+	// moved cards are placed at the bottom of the target column
+	input := card.Input()
+	input.ColumnID = v.columns[target].ID
+	input.Position = len(v.cards[target])
+
+	edited, err := v.e.client.EditCard(v.board.ID, card.ID, input)
+	if err != nil {
+		v.status = err.Error()
+		return
+	}
+	if edited.ID == "" {
+		edited = card
+	}
+	edited.ColumnID = v.columns[target].ID
+	edited.Position = input.Position
+
+	v.cards[v.col] = append(v.cards[v.col][:v.row], v.cards[v.col][v.row+1:]...)
+	v.cards[target] = append(v.cards[target], edited)
+	v.col = target
+	v.row = len(v.cards[target]) - 1
+	v.status = fmt.Sprintf("moved %q to %s", card.Name, v.columns[target].Name)
+}
+
+func (v *boardView) openComments() {
+	card := v.selected()
+	if card == nil {
+		return
+	}
+
+	comments, err := v.e.client.AllComments(v.board.ID, card.ID)
+	if err != nil {
+		v.status = err.Error()
+		return
+	}
+
+	v.comments = comments
+	v.showComments = true
+}
+
+func (v *boardView) render() {
+	v.width, v.height = 120, 40
+	if w, h, err := terminalSize(os.Stdout); err == nil && w > 0 && h > 0 {
+		v.width, v.height = w, h
+	}
+
+	var b strings.Builder
+	b.WriteString(ansiClear)
+	fmt.Fprintf(&b, "%s%s%s\n", ansiBold, v.board.Name, ansiReset)
+
+	if v.showComments {
+		v.renderComments(&b)
+	} else {
+		v.renderColumns(&b)
+	}
+
+	status := v.status
+	if status == "" {
+		status = viewHelp
+		if v.showComments {
+			status = "esc/q back"
+		}
+	}
+	fmt.Fprintf(&b, "%s%s%s", ansiDim, fit(status, v.width), ansiReset)
+
+	io.WriteString(v.e.stdout, b.String())
+}
+
+const columnGap = 2
+
+func (v *boardView) renderColumns(b *strings.Builder) {
+	if len(v.columns) == 0 {
+		b.WriteString("this board has no columns\n")
+		return
+	}
+
+	colWidth := 24
+	visible := (v.width + columnGap) / (colWidth + columnGap)
+	if visible < 1 {
+		visible = 1
+	}
+	if visible > len(v.columns) {
+		visible = len(v.columns)
+	}
+	colWidth = (v.width-columnGap*(visible-1))/visible - 1
+
+	// scroll horizontally so the selected column is visible
+	first := 0
+	if v.col >= visible {
+		first = v.col - visible + 1
+	}
+	last := first + visible
+
+	// each card takes three lines, leaving room for the headers and status
+	perColumn := (v.height - 4) / 3
+	if perColumn < 1 {
+		perColumn = 1
+	}
+
+	blocks := make([][]string, 0, visible)
+	for i := first; i < last; i++ {
+		col := v.columns[i]
+		header := fmt.Sprintf("%s (%d)", col.Name, len(v.cards[i]))
+		lines := []string{
+			ansiBold + fit(header, colWidth) + ansiReset,
+			strings.Repeat("─", colWidth),
+		}
+
+		offset := 0
+		if i == v.col && v.row >= perColumn {
+			offset = v.row - perColumn + 1
+		}
+		for j := offset; j < len(v.cards[i]) && j < offset+perColumn; j++ {
+			selected := i == v.col && j == v.row
+			lines = append(lines, v.cardLines(v.cards[i][j], colWidth, selected)...)
+		}
+		blocks = append(blocks, lines)
+	}
+
+	height := 0
+	for _, lines := range blocks {
+		if len(lines) > height {
+			height = len(lines)
+		}
+	}
+	blank := strings.Repeat(" ", colWidth)
+	gap := strings.Repeat(" ", columnGap)
+	for line := 0; line < height; line++ {
+		for i, lines := range blocks {
+			if i > 0 {
+				b.WriteString(gap)
+			}
+			if line < len(lines) {
+				b.WriteString(lines[line])
+			} else {
+				b.WriteString(blank)
+			}
+		}
+		b.WriteString("\n")
+	}
+}
+
+// cardLines renders a card as a name line, a details line and a spacer
+func (v *boardView) cardLines(card *glo.Card, width int, selected bool) []string {
+	name := fit(card.Name, width)
+	if selected {
+		name = ansiReverse + name + ansiReset
+	}
+
+	var details []string
+	if len(card.Assignees) > 0 {
+		details = append(details, "@"+v.assigneeNames(card.Assignees))
+	}
+	if card.DueDate != "" {
+		due := card.DueDate
+		if len(due) >= 10 {
+			due = due[:10]
+		}
+		details = append(details, "due "+due)
+	}
+	if card.TotalTaskCount > 0 {
+		details = append(details, fmt.Sprintf("☑ %d/%d", card.CompletedTaskCount, card.TotalTaskCount))
+	}
+	plain := strings.Join(details, " ")
+
+	// labels are coloured, so only the remaining width is used for details
+	labelWidth := 0
+	var coloured []string
+	for _, partial := range card.Labels {
+		text := v.labelText(partial)
+		if labelWidth+len([]rune(text))+1 > width {
+			break
+		}
+		labelWidth += len([]rune(text)) + 1
+		coloured = append(coloured, v.labelColour(partial)+text+ansiReset)
+	}
+	detailLine := strings.Join(coloured, " ")
+	if labelWidth > 0 {
+		detailLine += " "
+	}
+	detailLine += ansiDim + fit(plain, width-labelWidth) + ansiReset
+
+	return []string{name, detailLine, strings.Repeat(" ", width)}
+}
+
+func (v *boardView) labelText(partial *glo.PartialLabel) string {
+	for _, label := range v.board.Labels {
+		if label.ID == partial.ID {
+			return "[" + label.Name + "]"
+		}
+	}
+	if partial.Name != "" {
+		return "[" + partial.Name + "]"
+	}
+
+	return "[" + partial.ID + "]"
+}
+
+func (v *boardView) labelColour(partial *glo.PartialLabel) string {
+	for _, label := range v.board.Labels {
+		if label.ID == partial.ID {
+			return fmt.Sprintf("\x1b[38;2;%d;%d;%dm", label.Color.R, label.Color.G, label.Color.B)
+		}
+	}
+
+	return ""
+}
+
+func (v *boardView) assigneeNames(users []*glo.PartialUser) string {
+	var names []string
+	for _, user := range users {
+		name := user.ID
+		for _, member := range v.board.Members {
+			if member.ID == user.ID && member.Username != "" {
+				name = member.Username
+			}
+		}
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (v *boardView) renderComments(b *strings.Builder) {
+	card := v.selected()
+	if card == nil {
+		return
+	}
+
+	fmt.Fprintf(b, "%s%s%s\n\n", ansiReverse, fit(card.Name, v.width), ansiReset)
+	if len(v.comments) == 0 {
+		b.WriteString("no comments\n")
+	}
+
+	for _, comment := range v.comments {
+		author := ""
+		if comment.CreatedBy != nil {
+			author = v.assigneeNames([]*glo.PartialUser{comment.CreatedBy})
+		}
+		fmt.Fprintf(b, "%s%s %s%s\n", ansiBold, author, comment.CreatedDate, ansiReset)
+		for _, line := range strings.Split(comment.Text, "\n") {
+			fmt.Fprintf(b, "  %s\n", fit(line, v.width-2))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+}
+
+// fit truncates or pads s to exactly width runes
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	r := []rune(strings.Join(strings.Fields(s), " "))
+	if len(r) > width {
+		return string(r[:width-1]) + "…"
+	}
+
+	return string(r) + strings.Repeat(" ", width-len(r))
+}
+
+// keyReader reads key presses from the terminal
+type keyReader struct {
+	r *bufio.Reader
+
+	// lineMode when the terminal could not be made raw,
+	// keys are only received once Enter is pressed
+	lineMode bool
+	pending  bool
+}
+
+// read reads a single key press, translating escape sequences
+func (k *keyReader) read() (key string, err error) {
+	for {
+		c, _, readErr := k.r.ReadRune()
+		if readErr != nil {
+			err = readErr
+			return
+		}
+
+		switch c {
+		case '\r', '\n':
+			// in line mode the Enter following other keys only submits them
+			if k.pending {
+				k.pending = false
+				continue
+			}
+			return keyEnter, nil
+		case 0x1b:
+			k.pending = k.lineMode
+			if !k.lineMode && k.r.Buffered() == 0 {
+				return keyEsc, nil
+			}
+			next, _, _ := k.r.ReadRune()
+			if next != '[' && next != 'O' {
+				k.r.UnreadRune()
+				return keyEsc, nil
+			}
+			final, _, _ := k.r.ReadRune()
+			switch final {
+			case 'A':
+				return keyUp, nil
+			case 'B':
+				return keyDown, nil
+			case 'C':
+				return keyRight, nil
+			case 'D':
+				return keyLeft, nil
+			}
+			continue
+		}
+
+		k.pending = k.lineMode
+		return string(c), nil
+	}
+}
@@ -0,0 +1,117 @@
+package glo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// flightCall is an in-flight or completed request
+// shared between identical concurrent callers
+type flightCall struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	data    []byte
+	header  http.Header
+	err     error
+}
+
+// flightGroup coalesces identical concurrent requests
+// so that only one of them reaches the API
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// errFlightPanicked returned to the callers waiting on
+// a request whose function panicked
+var errFlightPanicked = errors.New("coalesced request panicked")
+
+// do executes fn once for all concurrent callers sharing key, every
+// caller receives the same response and error, waiting callers receive
+// their own copies of the response data and headers
+//
+// fn runs with a context detached from the callers' cancellation, it
+// is cancelled once every caller waiting on it has given up, a caller
+// whose ctx is done returns ctx.Err() without waiting for fn
+func (g *flightGroup) do(
+	ctx context.Context,
+	key string,
+	fn func(ctx context.Context) ([]byte, http.Header, error),
+) (
+	data []byte,
+	header http.Header,
+	err error,
+) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = map[string]*flightCall{}
+	}
+	c, ok := g.calls[key]
+	if !ok {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		c = &flightCall{
+			done:   make(chan struct{}),
+			cancel: cancel,
+			err:    errFlightPanicked,
+		}
+		g.calls[key] = c
+		go g.run(callCtx, key, c, fn)
+	}
+	c.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return append([]byte(nil), c.data...), c.header.Clone(), c.err
+	case <-ctx.Done():
+		g.leave(key, c)
+		err = ctx.Err()
+		return
+	}
+}
+
+// run executes fn for the call, releasing its waiters
+// and the key even when fn panics
+func (g *flightGroup) run(
+	ctx context.Context,
+	key string,
+	c *flightCall,
+	fn func(ctx context.Context) ([]byte, http.Header, error),
+) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.err = fmt.Errorf("%w: %v", errFlightPanicked, r)
+		}
+		g.mu.Lock()
+		if g.calls[key] == c {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		c.cancel()
+		close(c.done)
+	}()
+
+	c.data, c.header, c.err = fn(ctx)
+}
+
+// leave removes a waiter from the call, cancelling the
+// call once no callers are waiting on it
+func (g *flightGroup) leave(key string, c *flightCall) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	c.waiters--
+	if c.waiters > 0 {
+		return
+	}
+	// later callers start a new request rather than
+	// joining the cancelled one
+	if g.calls[key] == c {
+		delete(g.calls, key)
+	}
+	c.cancel()
+}
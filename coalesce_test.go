@@ -0,0 +1,191 @@
+package glo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until n callers are waiting on key
+func waitForWaiters(t *testing.T, g *flightGroup, key string, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		g.mu.Lock()
+		c, ok := g.calls[key]
+		waiters := 0
+		if ok {
+			waiters = c.waiters
+		}
+		g.mu.Unlock()
+		if waiters == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %d callers on %q", n, key)
+}
+
+func TestCoalesceRequests(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+		fmt.Fprint(w, `{"id":"b1","name":"Board"}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("token")
+	client.BaseURI = srv.URL
+	client.CoalesceRequests = true
+
+	const callers = 5
+	boards := make([]*Board, callers)
+	errs := make([]error, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			boards[i], errs[i] = client.GetBoard("b1")
+		}(i)
+	}
+
+	q, err := fieldsQuery(boardResource, boardFields, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := fmt.Sprintf("%s %s/boards/b1?%s %s", http.MethodGet, srv.URL, q.Encode(), "token")
+	waitForWaiters(t, client.flight, key, callers)
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("got %d requests, want 1", got)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil {
+			t.Fatalf("caller %d err:%s", i, errs[i])
+		}
+		if boards[i].Name != "Board" {
+			t.Errorf("caller %d got board %q", i, boards[i].Name)
+		}
+	}
+	for i := 1; i < callers; i++ {
+		if boards[i] == boards[0] {
+			t.Errorf("caller %d shares the first caller's board", i)
+		}
+	}
+}
+
+func TestCoalescePanic(t *testing.T) {
+	g := &flightGroup{}
+	release := make(chan struct{})
+
+	const callers = 3
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			_, _, err := g.do(context.Background(), "key", func(ctx context.Context) ([]byte, http.Header, error) {
+				<-release
+				panic("boom")
+			})
+			errs <- err
+		}()
+	}
+	waitForWaiters(t, g, "key", callers)
+	close(release)
+
+	for i := 0; i < callers; i++ {
+		select {
+		case err := <-errs:
+			if !errors.Is(err, errFlightPanicked) {
+				t.Errorf("got err %v, want %v", err, errFlightPanicked)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("caller was not released after the panic")
+		}
+	}
+
+	// the key is released so the next caller runs fn again
+	data, _, err := g.do(context.Background(), "key", func(ctx context.Context) ([]byte, http.Header, error) {
+		return []byte("ok"), nil, nil
+	})
+	if err != nil || string(data) != "ok" {
+		t.Errorf("got %q err:%v, want \"ok\"", data, err)
+	}
+}
+
+func TestCoalesceWaiterCancel(t *testing.T) {
+	g := &flightGroup{}
+	release := make(chan struct{})
+	started := make(chan context.Context, 1)
+	fn := func(ctx context.Context) ([]byte, http.Header, error) {
+		started <- ctx
+		select {
+		case <-release:
+			return []byte("ok"), nil, nil
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	// the first caller giving up leaves the call running for the second
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(firstCtx, "key", fn)
+		first <- err
+	}()
+	callCtx := <-started
+
+	second := make(chan []byte, 1)
+	go func() {
+		data, _, _ := g.do(context.Background(), "key", fn)
+		second <- data
+	}()
+	waitForWaiters(t, g, "key", 2)
+
+	cancelFirst()
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("first caller got err %v, want %v", err, context.Canceled)
+	}
+	if callCtx.Err() != nil {
+		t.Fatal("call was cancelled while a caller was still waiting")
+	}
+
+	close(release)
+	if data := <-second; string(data) != "ok" {
+		t.Errorf("second caller got %q, want \"ok\"", data)
+	}
+
+	// the call is cancelled once every caller has given up
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := g.do(ctx, "other", func(ctx context.Context) ([]byte, http.Header, error) {
+			started <- ctx
+			<-ctx.Done()
+			return nil, nil, ctx.Err()
+		})
+		done <- err
+	}()
+	callCtx = <-started
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got err %v, want %v", err, context.Canceled)
+	}
+	select {
+	case <-callCtx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("call was not cancelled after every caller gave up")
+	}
+}
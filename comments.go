@@ -52,13 +52,17 @@ func (a *Glo) GetComments(
 	page int,
 	limit int,
 	sortDesc bool,
+	opts ...CallOption,
 ) (
 	commentsResp *CommentsResp,
 	err error,
 ) {
 	addr := fmt.Sprintf("%s/boards/%s/cards/%s/comments", a.BaseURI, boardID, cardID)
 
-	q := utils.AddFields(commentFields)
+	q, err := fieldsQuery(commentResource, commentFields, opts)
+	if err != nil {
+		return
+	}
 	q.Set("page", fmt.Sprint(page))
 	q.Set("per_page", fmt.Sprint(limit))
 
@@ -72,7 +76,7 @@ func (a *Glo) GetComments(
 	}
 
 	commentsResp = &CommentsResp{}
-	err = json.Unmarshal(resp, &commentsResp)
+	err = json.Unmarshal(resp, &commentsResp.Comments)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,278 @@
+package glo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Environment variables consulted by LoadCredentials
+const (
+	EnvToken   = "GLO_TOKEN"
+	EnvProfile = "GLO_PROFILE"
+	EnvConfig  = "GLO_CONFIG"
+)
+
+// DefaultProfile the profile used when none is selected
+const DefaultProfile = "default"
+
+// Credential sources
+const (
+	CredentialsSourceExplicit = "explicit"
+	CredentialsSourceEnv      = "env"
+	CredentialsSourceConfig   = "config"
+)
+
+// ErrNoCredentials returned when no token could be resolved
+var ErrNoCredentials = errors.New(
+	"no glo credentials configured, set " + EnvToken +
+		" or add a profile to ~/.config/glo/config",
+)
+
+// CredentialsInput contains information used to resolve credentials
+type CredentialsInput struct {
+	// Token an explicit token, used before any other source
+	Token string
+
+	// Profile the config file profile, defaults
+	// to GLO_PROFILE then the default profile
+	Profile string
+
+	// ConfigPath the config file, defaults to GLO_CONFIG
+	// then ~/.config/glo/config
+	ConfigPath string
+}
+
+// Credentials contains a resolved token and the base URI
+// of the API it should be used against
+type Credentials struct {
+	Token   string
+	BaseURI string
+	Profile string
+	Source  string
+}
+
+// Profile contains the information related to a config file profile
+type Profile struct {
+	Name    string
+	Token   string
+	BaseURI string
+}
+
+// LoadCredentials resolves credentials in the following order
+//
+//   - an explicit token
+//   - an explicitly selected profile
+//   - the GLO_TOKEN environment variable
+//   - the GLO_PROFILE or default profile of the config file
+//
+// The config file contains named profiles, each with a token
+// and an optional base URI
+//
+//	[default]
+//	token = <personal access token>
+//
+//	[work]
+//	token = <personal access token>
+//	base_uri = https://gloapi.gitkraken.com/v1/glo
+//
+// and must not be accessible by other users.
+func LoadCredentials(input *CredentialsInput) (creds *Credentials, err error) {
+	if input == nil {
+		input = &CredentialsInput{}
+	}
+
+	if input.Token != "" {
+		creds = &Credentials{
+			Token:  input.Token,
+			Source: CredentialsSourceExplicit,
+		}
+		return
+	}
+
+	if input.Profile == "" {
+		if token := os.Getenv(EnvToken); token != "" {
+			creds = &Credentials{
+				Token:  token,
+				Source: CredentialsSourceEnv,
+			}
+			return
+		}
+	}
+
+	path := input.ConfigPath
+	if path == "" {
+		path, err = ConfigPath()
+		if err != nil {
+			return
+		}
+	}
+
+	name := input.Profile
+	explicitProfile := name != ""
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+		explicitProfile = name != ""
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+
+	profiles, err := LoadProfiles(path)
+	if os.IsNotExist(err) {
+		if explicitProfile {
+			err = fmt.Errorf("profile:%s requested but config file:%s does not exist", name, path)
+			return
+		}
+		err = ErrNoCredentials
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		if explicitProfile {
+			err = fmt.Errorf("profile:%s not found in config file:%s", name, path)
+			return
+		}
+		err = ErrNoCredentials
+		return
+	}
+	if profile.Token == "" {
+		err = fmt.Errorf("profile:%s in config file:%s has no token", name, path)
+		return
+	}
+
+	creds = &Credentials{
+		Token:   profile.Token,
+		BaseURI: profile.BaseURI,
+		Profile: profile.Name,
+		Source:  CredentialsSourceConfig,
+	}
+
+	return
+}
+
+// NewClient Glo API Client using the credentials
+func (c *Credentials) NewClient() *Glo {
+	client := NewClient(c.Token)
+	if c.BaseURI != "" {
+		client.BaseURI = c.BaseURI
+	}
+
+	return client
+}
+
+// ConfigPath the config file path, GLO_CONFIG when set otherwise
+// glo/config within $XDG_CONFIG_HOME or ~/.config
+func ConfigPath() (path string, err error) {
+	if path = os.Getenv(EnvConfig); path != "" {
+		return
+	}
+
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, homeErr := os.UserHomeDir()
+		if homeErr != nil {
+			err = fmt.Errorf("failed to locate home directory err:%s", homeErr)
+			return
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	path = filepath.Join(dir, "glo", "config")
+
+	return
+}
+
+// LoadProfiles reads every profile from a config file,
+// the file must not be readable or writable by other users
+func LoadProfiles(path string) (profiles map[string]*Profile, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	err = checkPermissions(f, path)
+	if err != nil {
+		return
+	}
+
+	profiles = map[string]*Profile{}
+	var current *Profile
+
+	scanner := bufio.NewScanner(f)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if name == "" {
+				err = fmt.Errorf("%s:%d empty profile name", path, lineNo)
+				return
+			}
+			current = &Profile{Name: name}
+			profiles[name] = current
+			continue
+		}
+
+		kv := strings.SplitN(line, "=", 2)
+		if len(kv) != 2 {
+			err = fmt.Errorf("%s:%d expected key = value", path, lineNo)
+			return
+		}
+		if current == nil {
+			err = fmt.Errorf("%s:%d key outside of a profile", path, lineNo)
+			return
+		}
+
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		value := strings.Trim(strings.TrimSpace(kv[1]), `"'`)
+
+		switch key {
+		case "token":
+			current.Token = value
+		case "base_uri", "base_url":
+			current.BaseURI = value
+		default:
+			err = fmt.Errorf("%s:%d unknown key:%s", path, lineNo, key)
+			return
+		}
+	}
+	err = scanner.Err()
+
+	return
+}
+
+// checkPermissions ensures a credentials file is private to its owner,
+// Windows does not expose POSIX permissions so it is not checked
+func checkPermissions(f *os.File, path string) (err error) {
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+
+	if mode := info.Mode().Perm(); mode&0077 != 0 {
+		err = fmt.Errorf(
+			"config file:%s is accessible by other users (mode %04o), run chmod 600 %s",
+			path,
+			mode,
+			path,
+		)
+	}
+
+	return
+}
@@ -0,0 +1,201 @@
+package glo
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testConfig = `# glo profiles
+[default]
+token = default-token
+
+[work]
+token = "work-token"
+base_uri = https://glo.example.com/v1/glo
+
+[empty]
+`
+
+// writeConfig writes a config file with the provided permissions
+func writeConfig(t *testing.T, data string, perm os.FileMode) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "config")
+	if err := ioutil.WriteFile(path, []byte(data), perm); err != nil {
+		t.Fatal(err)
+	}
+	// the umask may have removed bits from perm
+	if err := os.Chmod(path, perm); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadCredentials(t *testing.T) {
+	path := writeConfig(t, testConfig, 0600)
+
+	tests := []struct {
+		name       string
+		input      *CredentialsInput
+		envToken   string
+		envProfile string
+		want       *Credentials
+		err        string
+	}{
+		{
+			name:     "explicit token before everything",
+			input:    &CredentialsInput{Token: "explicit", Profile: "work"},
+			envToken: "env-token",
+			want:     &Credentials{Token: "explicit", Source: CredentialsSourceExplicit},
+		},
+		{
+			name:     "explicit profile before GLO_TOKEN",
+			input:    &CredentialsInput{Profile: "work"},
+			envToken: "env-token",
+			want: &Credentials{
+				Token:   "work-token",
+				BaseURI: "https://glo.example.com/v1/glo",
+				Profile: "work",
+				Source:  CredentialsSourceConfig,
+			},
+		},
+		{
+			name:       "GLO_TOKEN before GLO_PROFILE",
+			input:      &CredentialsInput{},
+			envToken:   "env-token",
+			envProfile: "work",
+			want:       &Credentials{Token: "env-token", Source: CredentialsSourceEnv},
+		},
+		{
+			name:       "GLO_PROFILE",
+			input:      &CredentialsInput{},
+			envProfile: "work",
+			want: &Credentials{
+				Token:   "work-token",
+				BaseURI: "https://glo.example.com/v1/glo",
+				Profile: "work",
+				Source:  CredentialsSourceConfig,
+			},
+		},
+		{
+			name:  "default profile",
+			input: nil,
+			want:  &Credentials{Token: "default-token", Profile: "default", Source: CredentialsSourceConfig},
+		},
+		{
+			name:  "unknown profile",
+			input: &CredentialsInput{Profile: "missing"},
+			err:   "profile:missing not found",
+		},
+		{
+			name:       "unknown GLO_PROFILE",
+			input:      &CredentialsInput{},
+			envProfile: "missing",
+			err:        "profile:missing not found",
+		},
+		{
+			name:  "profile without a token",
+			input: &CredentialsInput{Profile: "empty"},
+			err:   "profile:empty in config file:" + path + " has no token",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv(EnvToken, test.envToken)
+			t.Setenv(EnvProfile, test.envProfile)
+			t.Setenv(EnvConfig, path)
+
+			creds, err := LoadCredentials(test.input)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("got err %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *creds != *test.want {
+				t.Errorf("got %+v, want %+v", creds, test.want)
+			}
+		})
+	}
+}
+
+func TestLoadCredentialsWithoutConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	t.Setenv(EnvToken, "")
+	t.Setenv(EnvProfile, "")
+	t.Setenv(EnvConfig, path)
+
+	_, err := LoadCredentials(nil)
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Errorf("got err %v, want %v", err, ErrNoCredentials)
+	}
+
+	_, err = LoadCredentials(&CredentialsInput{Profile: "work"})
+	if err == nil || !strings.Contains(err.Error(), "does not exist") {
+		t.Errorf("got err %v, want a missing config file error", err)
+	}
+}
+
+func TestLoadProfilesPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not checked on windows")
+	}
+
+	tests := []struct {
+		perm os.FileMode
+		ok   bool
+	}{
+		{0600, true},
+		{0400, true},
+		{0700, true},
+		{0640, false},
+		{0604, false},
+		{0660, false},
+		{0644, false},
+		{0610, false},
+	}
+
+	for _, test := range tests {
+		path := writeConfig(t, testConfig, test.perm)
+
+		_, err := LoadProfiles(path)
+		if test.ok && err != nil {
+			t.Errorf("mode %04o got err %v", test.perm, err)
+		}
+		if !test.ok && (err == nil || !strings.Contains(err.Error(), "accessible by other users")) {
+			t.Errorf("mode %04o got err %v, want it rejected", test.perm, err)
+		}
+	}
+}
+
+func TestLoadProfilesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"key outside of a profile", "token = abc\n", ":1 key outside of a profile"},
+		{"empty profile name", "[ ]\n", ":1 empty profile name"},
+		{"missing value", "[default]\ntoken\n", ":2 expected key = value"},
+		{"unknown key", "[default]\ncolour = red\n", ":2 unknown key:colour"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadProfiles(writeConfig(t, test.data, 0600))
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got err %v, want %q", err, test.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package glo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrRateLimited returned when the API rate limit has been reached
+var ErrRateLimited = errors.New("rate limit reached")
+
+// StatusError returned when the API responds
+// with an unsupported HTTP status
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf(
+		"unsupported response httpCode:%d status:%s ",
+		e.StatusCode,
+		e.Status,
+	)
+}
+
+// Error classes returned by ErrorClass
+const (
+	ErrorClassRateLimited = "rate_limited"
+	ErrorClassClient      = "client_error"
+	ErrorClassServer      = "server_error"
+	ErrorClassTransport   = "transport"
+)
+
+// ErrorClass classifies an error returned by an API request,
+// an empty string is returned for a nil error
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if errors.Is(err, ErrRateLimited) {
+		return ErrorClassRateLimited
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode >= http.StatusInternalServerError {
+			return ErrorClassServer
+		}
+		return ErrorClassClient
+	}
+
+	return ErrorClassTransport
+}
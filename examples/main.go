@@ -2,19 +2,17 @@ package main
 
 import (
 	"log"
-	"os"
 
 	"github.com/jackmcguire1/go-glo"
 )
 
-var token string
-
-func init() {
-	token = os.Getenv("TOKEN")
-}
-
 func main() {
-	client := glo.NewClient(token)
+	creds, err := glo.LoadCredentials(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client := creds.NewClient()
 	user, err := client.GetUser()
 	if err != nil {
 		log.Fatal(err)
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// DefaultCalendarMaxAge how long a CalendarHandler caches boards
+const DefaultCalendarMaxAge = 5 * time.Minute
+
+// CalendarHandler serves an iCalendar feed of the due cards of boards.
+//
+// The assignee and label query parameters, repeated or comma
+// separated, replace the corresponding filters of Options.
+type CalendarHandler struct {
+	Client   *glo.Glo
+	BoardIDs []string
+	Options  CalendarOptions
+
+	// MaxAge how long boards are cached between requests,
+	// defaults to DefaultCalendarMaxAge
+	MaxAge time.Duration
+
+	mu      sync.Mutex
+	snaps   []*glo.BoardSnapshot
+	fetched time.Time
+}
+
+// ServeHTTP serves the feed as text/calendar
+func (h *CalendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	snaps, err := h.snapshots()
+	if err != nil {
+		http.Error(w, "failed to fetch boards", http.StatusBadGateway)
+		return
+	}
+
+	opts := h.Options
+	q := r.URL.Query()
+	if assignees := queryList(q["assignee"]); len(assignees) > 0 {
+		opts.Assignees = assignees
+	}
+	if labels := queryList(q["label"]); len(labels) > 0 {
+		opts.Labels = labels
+	}
+
+	var buf bytes.Buffer
+	err = ICalendar(&buf, snaps, &opts)
+	if err != nil {
+		http.Error(w, "failed to render calendar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// snapshots the cached boards, refetched once they are older than MaxAge
+func (h *CalendarHandler) snapshots() (snaps []*glo.BoardSnapshot, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	maxAge := h.MaxAge
+	if maxAge <= 0 {
+		maxAge = DefaultCalendarMaxAge
+	}
+	if h.snaps != nil && time.Since(h.fetched) < maxAge {
+		return h.snaps, nil
+	}
+
+	for _, boardID := range h.BoardIDs {
+		var snap *glo.BoardSnapshot
+		snap, err = h.Client.Snapshot(boardID, nil)
+		if err != nil {
+			return
+		}
+		snaps = append(snaps, snap)
+	}
+	h.snaps = snaps
+	h.fetched = time.Now()
+
+	return
+}
+
+// queryList splits repeated and comma separated query values
+func queryList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+
+	return list
+}
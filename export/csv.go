@@ -0,0 +1,66 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+var csvHeader = []string{
+	"board",
+	"column",
+	"card_id",
+	"name",
+	"labels",
+	"assignees",
+	"due_date",
+	"completed_tasks",
+	"total_tasks",
+	"comments",
+	"attachments",
+	"created_date",
+	"updated_date",
+	"archived_date",
+}
+
+// CSV writes one row per card, ordered by column then position,
+// with labels and assignees resolved to names
+func CSV(w io.Writer, snap *glo.BoardSnapshot) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range groups(snap) {
+		for _, card := range g.cards {
+			err = cw.Write([]string{
+				snap.Board.Name,
+				g.column.Name,
+				card.ID,
+				card.Name,
+				strings.Join(labelNames(snap, card), ";"),
+				strings.Join(assigneeNames(snap, card), ";"),
+				card.DueDate,
+				strconv.Itoa(card.CompletedTaskCount),
+				strconv.Itoa(card.TotalTaskCount),
+				strconv.Itoa(card.CommentCount),
+				strconv.Itoa(card.AttachmentCount),
+				card.CreatedDate,
+				card.UpdatedDate,
+				card.ArchivedDate,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
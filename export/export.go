@@ -0,0 +1,116 @@
+// Package export renders board snapshots as CSV, Markdown, JSON and
+// iCalendar feeds.
+package export
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// group the cards of a column
+type group struct {
+	column *glo.Column
+	cards  []*glo.Card
+}
+
+// groups orders the snapshot's cards by column position then card
+// position, cards of unknown columns are grouped last
+func groups(snap *glo.BoardSnapshot) []*group {
+	var groups []*group
+	known := map[string]bool{}
+	for _, col := range snap.SortedColumns() {
+		known[col.ID] = true
+		cards := snap.CardsByColumn(col.ID)
+		if len(cards) == 0 && col.ArchivedDate != "" {
+			continue
+		}
+		groups = append(groups, &group{column: col, cards: cards})
+	}
+
+	var orphans []*glo.Card
+	for _, card := range snap.Cards {
+		if !known[card.ColumnID] {
+			orphans = append(orphans, card)
+		}
+	}
+	if len(orphans) > 0 {
+		sort.SliceStable(orphans, func(i, j int) bool {
+			return orphans[i].Position < orphans[j].Position
+		})
+		groups = append(groups, &group{
+			column: &glo.Column{Name: "(unknown column)"},
+			cards:  orphans,
+		})
+	}
+
+	return groups
+}
+
+// columnName the name of a card's column
+func columnName(snap *glo.BoardSnapshot, card *glo.Card) string {
+	if col := snap.Column(card.ColumnID); col != nil {
+		return col.Name
+	}
+
+	return card.ColumnID
+}
+
+// labelNames resolves a card's labels to their names
+func labelNames(snap *glo.BoardSnapshot, card *glo.Card) []string {
+	names := []string{}
+	for _, partial := range card.Labels {
+		if label := snap.Label(partial.ID); label != nil {
+			names = append(names, label.Name)
+			continue
+		}
+		if partial.Name != "" {
+			names = append(names, partial.Name)
+			continue
+		}
+		names = append(names, partial.ID)
+	}
+
+	return names
+}
+
+// userName resolves a user to their username when they are a board member
+func userName(snap *glo.BoardSnapshot, user *glo.PartialUser) string {
+	if user == nil {
+		return ""
+	}
+	if member := snap.Member(user.ID); member != nil && member.Username != "" {
+		return member.Username
+	}
+
+	return user.ID
+}
+
+// assigneeNames resolves a card's assignees
+func assigneeNames(snap *glo.BoardSnapshot, card *glo.Card) []string {
+	names := []string{}
+	for _, user := range card.Assignees {
+		names = append(names, userName(snap, user))
+	}
+
+	return names
+}
+
+func description(card *glo.Card) string {
+	if card.Description == nil {
+		return ""
+	}
+
+	return strings.TrimSpace(card.Description.Text)
+}
+
+// comments the comments of a card, oldest first
+func comments(snap *glo.BoardSnapshot, card *glo.Card) []*glo.Comment {
+	list := append([]*glo.Comment{}, snap.Comments[card.ID]...)
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].CreatedDate < list[j].CreatedDate
+	})
+
+	return list
+}
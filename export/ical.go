@@ -0,0 +1,213 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// CalendarOptions contains information used to render a calendar
+type CalendarOptions struct {
+	// Name the calendar's display name
+	Name string
+
+	// Assignees only includes cards assigned to one of
+	// these users, by user ID or username
+	Assignees []string
+
+	// Labels only includes cards with one of these labels,
+	// by label ID or case-insensitive name
+	Labels []string
+}
+
+// CardURL the link to a card in the Glo web app
+func CardURL(boardID, cardID string) string {
+	return fmt.Sprintf("https://app.gitkraken.com/glo/board/%s/card/%s", boardID, cardID)
+}
+
+// event a card with a due date
+type event struct {
+	snap *glo.BoardSnapshot
+	card *glo.Card
+	due  time.Time
+}
+
+// ICalendar writes an iCalendar (RFC 5545) feed with an event for
+// every card with a due date across the snapshots
+func ICalendar(w io.Writer, snaps []*glo.BoardSnapshot, opts *CalendarOptions) error {
+	if opts == nil {
+		opts = &CalendarOptions{}
+	}
+
+	var events []*event
+	for _, snap := range snaps {
+		for _, card := range snap.Cards {
+			if card.DueDate == "" || !opts.matches(snap, card) {
+				continue
+			}
+			due, err := time.Parse(time.RFC3339, card.DueDate)
+			if err != nil {
+				continue
+			}
+			events = append(events, &event{snap: snap, card: card, due: due.UTC()})
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].due.Equal(events[j].due) {
+			return events[i].due.Before(events[j].due)
+		}
+		return events[i].card.ID < events[j].card.ID
+	})
+
+	cw := &calendarWriter{w: w}
+	cw.line("BEGIN", "VCALENDAR")
+	cw.line("VERSION", "2.0")
+	cw.line("PRODID", "-//go-glo//Glo Boards//EN")
+	cw.line("CALSCALE", "GREGORIAN")
+	cw.line("METHOD", "PUBLISH")
+	if opts.Name != "" {
+		cw.line("X-WR-CALNAME", escapeText(opts.Name))
+	}
+	for _, e := range events {
+		e.write(cw)
+	}
+	cw.line("END", "VCALENDAR")
+
+	return cw.err
+}
+
+func (e *event) write(cw *calendarWriter) {
+	card := e.card
+	boardID := e.snap.Board.ID
+	if boardID == "" {
+		boardID = card.BoardID
+	}
+
+	stamp := e.snap.CapturedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	cw.line("BEGIN", "VEVENT")
+	cw.line("UID", fmt.Sprintf("%s@glo.gitkraken.com", card.ID))
+	cw.line("DTSTAMP", stamp.UTC().Format(icalTime))
+
+	// cards due at midnight UTC were given a date without a time
+	if e.due.Hour() == 0 && e.due.Minute() == 0 && e.due.Second() == 0 {
+		cw.line("DTSTART;VALUE=DATE", e.due.Format(icalDate))
+		cw.line("DTEND;VALUE=DATE", e.due.AddDate(0, 0, 1).Format(icalDate))
+	} else {
+		cw.line("DTSTART", e.due.Format(icalTime))
+		cw.line("DTEND", e.due.Format(icalTime))
+	}
+
+	cw.line("SUMMARY", escapeText(card.Name))
+
+	text := description(card)
+	context := fmt.Sprintf("Board: %s\nColumn: %s", e.snap.Board.Name, columnName(e.snap, card))
+	if text != "" {
+		text += "\n\n"
+	}
+	cw.line("DESCRIPTION", escapeText(text+context))
+	cw.line("URL", CardURL(boardID, card.ID))
+
+	if labels := labelNames(e.snap, card); len(labels) > 0 {
+		escaped := make([]string, len(labels))
+		for i, label := range labels {
+			escaped[i] = escapeText(label)
+		}
+		cw.line("CATEGORIES", strings.Join(escaped, ","))
+	}
+	if updated, err := time.Parse(time.RFC3339, card.UpdatedDate); err == nil {
+		cw.line("LAST-MODIFIED", updated.UTC().Format(icalTime))
+	}
+	cw.line("END", "VEVENT")
+}
+
+// matches reports whether a card passes the assignee and label filters
+func (opts *CalendarOptions) matches(snap *glo.BoardSnapshot, card *glo.Card) bool {
+	if len(opts.Assignees) > 0 {
+		found := false
+		for _, user := range card.Assignees {
+			name := userName(snap, user)
+			for _, want := range opts.Assignees {
+				if want == user.ID || strings.EqualFold(want, name) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(opts.Labels) > 0 {
+		found := false
+		for _, partial := range card.Labels {
+			name := partial.Name
+			if label := snap.Label(partial.ID); label != nil {
+				name = label.Name
+			}
+			for _, want := range opts.Labels {
+				if want == partial.ID || strings.EqualFold(want, name) {
+					found = true
+				}
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+const (
+	icalTime = "20060102T150405Z"
+	icalDate = "20060102"
+)
+
+// escapeText escapes an iCalendar TEXT value
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// calendarWriter writes content lines folded at 75 octets
+// with CRLF line endings, keeping the first error
+type calendarWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (cw *calendarWriter) line(name, value string) {
+	if cw.err != nil {
+		return
+	}
+
+	line := name + ":" + value
+	var b strings.Builder
+	width := 0
+	for _, r := range line {
+		size := utf8.RuneLen(r)
+		if width+size > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += size
+	}
+	b.WriteString("\r\n")
+
+	_, cw.err = io.WriteString(cw.w, b.String())
+}
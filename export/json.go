@@ -0,0 +1,157 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// JSON export schema identifiers, the version is incremented
+// whenever the document changes incompatibly
+const (
+	Schema        = "glo-board-export"
+	SchemaVersion = 1
+)
+
+// Document the stable JSON export format of a board
+type Document struct {
+	Schema     string      `json:"schema"`
+	Version    int         `json:"version"`
+	ExportedAt time.Time   `json:"exported_at"`
+	Board      DocBoard    `json:"board"`
+	Columns    []DocColumn `json:"columns"`
+	Labels     []DocLabel  `json:"labels"`
+	Cards      []DocCard   `json:"cards"`
+}
+
+// DocBoard the exported board
+type DocBoard struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// DocColumn an exported column
+type DocColumn struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	Archived bool   `json:"archived"`
+}
+
+// DocLabel an exported label
+type DocLabel struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// DocCard an exported card
+type DocCard struct {
+	ID           string       `json:"id"`
+	Name         string       `json:"name"`
+	ColumnID     string       `json:"column_id"`
+	Column       string       `json:"column"`
+	Position     int          `json:"position"`
+	Labels       []string     `json:"labels"`
+	Assignees    []string     `json:"assignees"`
+	DueDate      string       `json:"due_date,omitempty"`
+	Description  string       `json:"description,omitempty"`
+	Tasks        DocTasks     `json:"tasks"`
+	CreatedDate  string       `json:"created_date,omitempty"`
+	UpdatedDate  string       `json:"updated_date,omitempty"`
+	ArchivedDate string       `json:"archived_date,omitempty"`
+	Comments     []DocComment `json:"comments"`
+}
+
+// DocTasks the task progress of a card
+type DocTasks struct {
+	Completed int `json:"completed"`
+	Total     int `json:"total"`
+}
+
+// DocComment an exported comment
+type DocComment struct {
+	ID          string `json:"id"`
+	Author      string `json:"author"`
+	CreatedDate string `json:"created_date"`
+	Text        string `json:"text"`
+}
+
+// NewDocument converts a snapshot to the export format,
+// columns and cards are ordered by position
+func NewDocument(snap *glo.BoardSnapshot) *Document {
+	doc := &Document{
+		Schema:     Schema,
+		Version:    SchemaVersion,
+		ExportedAt: snap.CapturedAt,
+		Board: DocBoard{
+			ID:   snap.Board.ID,
+			Name: snap.Board.Name,
+		},
+		Columns: []DocColumn{},
+		Labels:  []DocLabel{},
+		Cards:   []DocCard{},
+	}
+
+	for _, col := range snap.SortedColumns() {
+		doc.Columns = append(doc.Columns, DocColumn{
+			ID:       col.ID,
+			Name:     col.Name,
+			Position: col.Position,
+			Archived: col.ArchivedDate != "",
+		})
+	}
+
+	for _, label := range snap.Board.Labels {
+		doc.Labels = append(doc.Labels, DocLabel{
+			ID:    label.ID,
+			Name:  label.Name,
+			Color: label.Color.Hex(),
+		})
+	}
+
+	for _, g := range groups(snap) {
+		for _, card := range g.cards {
+			dc := DocCard{
+				ID:          card.ID,
+				Name:        card.Name,
+				ColumnID:    card.ColumnID,
+				Column:      columnName(snap, card),
+				Position:    card.Position,
+				Labels:      labelNames(snap, card),
+				Assignees:   assigneeNames(snap, card),
+				DueDate:     card.DueDate,
+				Description: description(card),
+				Tasks: DocTasks{
+					Completed: card.CompletedTaskCount,
+					Total:     card.TotalTaskCount,
+				},
+				CreatedDate:  card.CreatedDate,
+				UpdatedDate:  card.UpdatedDate,
+				ArchivedDate: card.ArchivedDate,
+				Comments:     []DocComment{},
+			}
+			for _, comment := range comments(snap, card) {
+				dc.Comments = append(dc.Comments, DocComment{
+					ID:          comment.ID,
+					Author:      userName(snap, comment.CreatedBy),
+					CreatedDate: comment.CreatedDate,
+					Text:        comment.Text,
+				})
+			}
+			doc.Cards = append(doc.Cards, dc)
+		}
+	}
+
+	return doc
+}
+
+// JSON writes the snapshot in the stable, schema versioned export format
+func JSON(w io.Writer, snap *glo.BoardSnapshot) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(NewDocument(snap))
+}
@@ -0,0 +1,122 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Markdown writes a document with a section per column
+// listing its cards, their details and comments
+func Markdown(w io.Writer, snap *glo.BoardSnapshot) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "# %s\n\n", snap.Board.Name)
+	fmt.Fprintf(bw, "_Exported %s_\n", snap.CapturedAt.Format("2006-01-02 15:04 MST"))
+
+	for _, g := range groups(snap) {
+		heading := g.column.Name
+		if g.column.ArchivedDate != "" {
+			heading += " (archived)"
+		}
+		fmt.Fprintf(bw, "\n## %s\n", heading)
+
+		if len(g.cards) == 0 {
+			fmt.Fprintf(bw, "\n_No cards_\n")
+			continue
+		}
+
+		for _, card := range g.cards {
+			writeCard(bw, snap, card)
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeCard(w io.Writer, snap *glo.BoardSnapshot, card *glo.Card) {
+	fmt.Fprintf(w, "\n### %s\n", card.Name)
+
+	var details []string
+	if labels := labelNames(snap, card); len(labels) > 0 {
+		details = append(details, "**Labels:** "+strings.Join(labels, ", "))
+	}
+	if assignees := assigneeNames(snap, card); len(assignees) > 0 {
+		details = append(details, "**Assignees:** "+strings.Join(assignees, ", "))
+	}
+	if card.DueDate != "" {
+		details = append(details, "**Due:** "+card.DueDate)
+	}
+	if card.TotalTaskCount > 0 {
+		details = append(details, fmt.Sprintf("**Tasks:** %d/%d", card.CompletedTaskCount, card.TotalTaskCount))
+	}
+	if card.ArchivedDate != "" {
+		details = append(details, "**Archived:** "+card.ArchivedDate)
+	}
+	if len(details) > 0 {
+		fmt.Fprintln(w)
+	}
+	for _, detail := range details {
+		fmt.Fprintf(w, "- %s\n", detail)
+	}
+
+	if text := description(card); text != "" {
+		fmt.Fprintf(w, "\n%s\n", shiftHeadings(text))
+	}
+
+	cardComments := comments(snap, card)
+	if len(cardComments) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n#### Comments\n")
+	for _, comment := range cardComments {
+		fmt.Fprintf(w, "\n> **%s** %s\n>\n", userName(snap, comment.CreatedBy), comment.CreatedDate)
+		for _, line := range strings.Split(strings.TrimSpace(comment.Text), "\n") {
+			fmt.Fprintf(w, "> %s\n", line)
+		}
+	}
+}
+
+var (
+	heading = regexp.MustCompile(`^(#{1,6})(\s|$)`)
+	fence   = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+)
+
+// shiftHeadings nests description headings below the card's heading,
+// lines within fenced code blocks are left untouched
+func shiftHeadings(text string) string {
+	lines := strings.Split(text, "\n")
+	inFence := ""
+	for i, line := range lines {
+		if m := fence.FindStringSubmatch(line); m != nil {
+			switch {
+			case inFence == "":
+				inFence = m[1]
+			case inFence == m[1]:
+				inFence = ""
+			}
+			continue
+		}
+		if inFence != "" {
+			continue
+		}
+
+		m := heading.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		// headings deeper than six levels are not headings
+		level := len(m[1]) + 3
+		if level > 6 {
+			level = 6
+		}
+		lines[i] = strings.Repeat("#", level) + line[len(m[1]):]
+	}
+
+	return strings.Join(lines, "\n")
+}
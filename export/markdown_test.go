@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func TestShiftHeadings(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "headings",
+			text: "# Title\n## Section\n### Detail\nbody",
+			want: "#### Title\n##### Section\n###### Detail\nbody",
+		},
+		{
+			name: "deep headings stop at six levels",
+			text: "#### Four\n###### Six",
+			want: "###### Four\n###### Six",
+		},
+		{
+			name: "empty heading",
+			text: "#\n##",
+			want: "####\n#####",
+		},
+		{
+			name: "not headings",
+			text: "#hashtag\n####### seven\n #indented tag\ntext # not a heading",
+			want: "#hashtag\n####### seven\n #indented tag\ntext # not a heading",
+		},
+		{
+			name: "backtick fence",
+			text: "# Run\n```sh\n# install\nmake\n```\n# After",
+			want: "#### Run\n```sh\n# install\nmake\n```\n#### After",
+		},
+		{
+			name: "tilde fence containing backticks",
+			text: "~~~\n# comment\n```\n# still code\n~~~\n## Done",
+			want: "~~~\n# comment\n```\n# still code\n~~~\n##### Done",
+		},
+		{
+			name: "indented fence",
+			text: "   ```\n# code\n   ```\n# heading",
+			want: "   ```\n# code\n   ```\n#### heading",
+		},
+		{
+			name: "unclosed fence",
+			text: "```\n# code",
+			want: "```\n# code",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := shiftHeadings(test.text); got != test.want {
+				t.Errorf("got\n%s\nwant\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	snap := &glo.BoardSnapshot{
+		CapturedAt: time.Date(2026, 10, 19, 9, 30, 0, 0, time.UTC),
+		Board: &glo.Board{
+			ID:   "b1",
+			Name: "Team Alpha",
+			Columns: []*glo.Column{
+				{ID: "c2", Name: "Done", Position: 1},
+				{ID: "c1", Name: "Todo", Position: 0},
+			},
+			Labels:  []*glo.Label{{ID: "l1", Name: "bug"}},
+			Members: []*glo.BoardMember{{ID: "u1", Username: "alice"}},
+		},
+		Cards: []*glo.Card{
+			{
+				ID:          "k1",
+				Name:        "Fix login",
+				ColumnID:    "c1",
+				Labels:      []*glo.PartialLabel{{ID: "l1"}},
+				Assignees:   []*glo.PartialUser{{ID: "u1"}},
+				Description: &glo.Description{Text: "# Steps\n```\n# not a heading\n```\n"},
+			},
+		},
+		Comments: map[string][]*glo.Comment{
+			"k1": {{Text: "on it", CreatedDate: "2026-10-18", CreatedBy: &glo.PartialUser{ID: "u1"}}},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	if err := Markdown(buf, snap); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Team Alpha\n\n" +
+		"_Exported 2026-10-19 09:30 UTC_\n" +
+		"\n## Todo\n" +
+		"\n### Fix login\n" +
+		"\n- **Labels:** bug\n" +
+		"- **Assignees:** alice\n" +
+		"\n#### Steps\n```\n# not a heading\n```\n" +
+		"\n#### Comments\n" +
+		"\n> **alice** 2026-10-18\n>\n> on it\n" +
+		"\n## Done\n" +
+		"\n_No cards_\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,182 @@
+package glo
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/jackmcguire1/go-glo/internal/utils"
+)
+
+// Field a resource field which can be requested from the API
+type Field interface {
+	fieldName() string
+	resource() string
+}
+
+// BoardField a field of a Board
+type BoardField string
+
+// CardField a field of a Card
+type CardField string
+
+// CommentField a field of a Comment
+type CommentField string
+
+// AttachmentField a field of an Attachment
+type AttachmentField string
+
+// UserField a field of a User
+type UserField string
+
+// Board fields
+const (
+	BoardFieldArchivedColumns BoardField = "archived_columns"
+	BoardFieldArchivedDate    BoardField = "archived_date"
+	BoardFieldColumns         BoardField = "columns"
+	BoardFieldCreatedBy       BoardField = "created_by"
+	BoardFieldCreatedDate     BoardField = "created_date"
+	BoardFieldInvitedMembers  BoardField = "invited_members"
+	BoardFieldLabels          BoardField = "labels"
+	BoardFieldMembers         BoardField = "members"
+	BoardFieldName            BoardField = "name"
+)
+
+// Card fields
+const (
+	CardFieldArchivedDate       CardField = "archived_date"
+	CardFieldAssignees          CardField = "assignees"
+	CardFieldAttachmentCount    CardField = "attachment_count"
+	CardFieldBoardID            CardField = "board_id"
+	CardFieldColumnID           CardField = "column_id"
+	CardFieldCommentCount       CardField = "comment_count"
+	CardFieldCompletedTaskCount CardField = "completed_task_count"
+	CardFieldCreatedBy          CardField = "created_by"
+	CardFieldCreatedDate        CardField = "created_date"
+	CardFieldDueDate            CardField = "due_date"
+	CardFieldDescription        CardField = "description"
+	CardFieldLabels             CardField = "labels"
+	CardFieldName               CardField = "name"
+	CardFieldPosition           CardField = "position"
+	CardFieldTotalTaskCount     CardField = "total_task_count"
+	CardFieldUpdatedDate        CardField = "updated_date"
+)
+
+// Comment fields
+const (
+	CommentFieldBoardID     CommentField = "board_id"
+	CommentFieldCardID      CommentField = "card_id"
+	CommentFieldCreatedDate CommentField = "created_date"
+	CommentFieldCreatedBy   CommentField = "created_by"
+	CommentFieldUpdatedBy   CommentField = "updated_by"
+	CommentFieldUpdatedDate CommentField = "updated_date"
+	CommentFieldText        CommentField = "text"
+)
+
+// Attachment fields
+const (
+	AttachmentFieldCreatedDate AttachmentField = "created_date"
+	AttachmentFieldCreatedBy   AttachmentField = "created_by"
+	AttachmentFieldFilename    AttachmentField = "filename"
+	AttachmentFieldMimeType    AttachmentField = "mime_type"
+)
+
+// User fields
+const (
+	UserFieldCreatedDate UserField = "created_date"
+	UserFieldEmail       UserField = "email"
+	UserFieldName        UserField = "name"
+	UserFieldUsername    UserField = "username"
+)
+
+const (
+	boardResource      = "board"
+	cardResource       = "card"
+	commentResource    = "comment"
+	attachmentResource = "attachment"
+	userResource       = "user"
+)
+
+func (f BoardField) fieldName() string      { return string(f) }
+func (f BoardField) resource() string       { return boardResource }
+func (f CardField) fieldName() string       { return string(f) }
+func (f CardField) resource() string        { return cardResource }
+func (f CommentField) fieldName() string    { return string(f) }
+func (f CommentField) resource() string     { return commentResource }
+func (f AttachmentField) fieldName() string { return string(f) }
+func (f AttachmentField) resource() string  { return attachmentResource }
+func (f UserField) fieldName() string       { return string(f) }
+func (f UserField) resource() string        { return userResource }
+
+// CallOption configures a single API call
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	fields []Field
+}
+
+// Fields requests only the provided fields instead of
+// every known field of the resource.
+//
+// Struct fields which were not requested are left at their zero value.
+func Fields(fields ...Field) CallOption {
+	return func(o *callOptions) {
+		o.fields = append(o.fields, fields...)
+	}
+}
+
+func newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// fieldsQuery constructs the fields query for a resource, validating
+// any selected fields against the resource's known fields
+func fieldsQuery(
+	resource string,
+	known []string,
+	opts []CallOption,
+) (
+	q url.Values,
+	err error,
+) {
+	o := newCallOptions(opts)
+	if len(o.fields) == 0 {
+		q = utils.AddFields(known)
+		return
+	}
+
+	knownSet := map[string]bool{}
+	for _, field := range known {
+		knownSet[field] = true
+	}
+
+	seen := map[string]bool{}
+	var fields []string
+	for _, field := range o.fields {
+		if field == nil {
+			err = fmt.Errorf("nil field selected for resource:%s", resource)
+			return
+		}
+		if field.resource() != resource || !knownSet[field.fieldName()] {
+			err = fmt.Errorf(
+				"unsupported field:%s for resource:%s",
+				field.fieldName(),
+				resource,
+			)
+			return
+		}
+		if seen[field.fieldName()] {
+			continue
+		}
+		seen[field.fieldName()] = true
+		fields = append(fields, field.fieldName())
+	}
+
+	q = utils.AddFields(fields)
+
+	return
+}
@@ -0,0 +1,81 @@
+package glo
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFieldsQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		resource string
+		known    []string
+		opts     []CallOption
+		want     []string
+		err      string
+	}{
+		{
+			name:     "every known field by default",
+			resource: boardResource,
+			known:    boardFields,
+			want:     boardFields,
+		},
+		{
+			name:     "selected fields",
+			resource: boardResource,
+			known:    boardFields,
+			opts:     []CallOption{Fields(BoardFieldName, BoardFieldColumns)},
+			want:     []string{"name", "columns"},
+		},
+		{
+			name:     "duplicates are removed",
+			resource: cardResource,
+			known:    cardFields,
+			opts: []CallOption{
+				Fields(CardFieldName, CardFieldLabels, CardFieldName),
+				Fields(CardFieldLabels),
+			},
+			want: []string{"name", "labels"},
+		},
+		{
+			name:     "field of another resource",
+			resource: boardResource,
+			known:    boardFields,
+			opts:     []CallOption{Fields(CardFieldName)},
+			err:      "unsupported field:name for resource:board",
+		},
+		{
+			name:     "unknown field",
+			resource: cardResource,
+			known:    cardFields,
+			opts:     []CallOption{Fields(CardField("colour"))},
+			err:      "unsupported field:colour for resource:card",
+		},
+		{
+			name:     "nil field",
+			resource: userResource,
+			known:    userFields,
+			opts:     []CallOption{Fields(nil)},
+			err:      "nil field selected for resource:user",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			q, err := fieldsQuery(test.resource, test.known, test.opts)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("got err %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := q["fields"]; !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got fields %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package fulltext is a local full-text search index over cards and
+// their comments.
+//
+// The Glo API has no search endpoint, so Sync builds an inverted index
+// from GetCards and GetComments, only re-reading cards whose UpdatedDate
+// or comment count changed since they were indexed. The index is pure Go
+// and persisted to a single file.
+package fulltext
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Document the indexed fields of a card
+type Document struct {
+	CardID       string   `json:"card_id"`
+	BoardID      string   `json:"board_id"`
+	ColumnID     string   `json:"column_id"`
+	LabelIDs     []string `json:"label_ids"`
+	Name         string   `json:"name"`
+	UpdatedDate  string   `json:"updated_date"`
+	CommentCount int      `json:"comment_count"`
+	Archived     bool     `json:"archived"`
+
+	// Length the number of indexed tokens
+	Length int `json:"length"`
+
+	// NameLength the number of tokens in the name,
+	// which are the first tokens of the document
+	NameLength int `json:"name_length"`
+}
+
+// BoardInfo the names of a board's columns and labels, used by filters
+type BoardInfo struct {
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Columns map[string]string `json:"columns"`
+	Labels  map[string]string `json:"labels"`
+}
+
+// Index an inverted index of cards, safe for concurrent use
+type Index struct {
+	mu sync.RWMutex
+
+	boards map[string]*BoardInfo
+	docs   map[string]*Document
+
+	// postings the positions of each term by card ID
+	postings map[string]map[string][]int
+
+	// terms the terms of each card, so removals only visit their postings
+	terms map[string][]string
+
+	// totalLength the sum of the lengths of the documents
+	totalLength int
+}
+
+// New an empty index
+func New() *Index {
+	return &Index{
+		boards:   map[string]*BoardInfo{},
+		docs:     map[string]*Document{},
+		postings: map[string]map[string][]int{},
+		terms:    map[string][]string{},
+	}
+}
+
+// fieldGap separates the positions of fields, and of comments,
+// so phrases never match across them
+const fieldGap = 100
+
+// Add indexes a card of a board with its comments,
+// replacing any earlier version of the card
+func (ix *Index) Add(boardID string, card *glo.Card, comments []*glo.Comment) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(card.ID)
+
+	doc := &Document{
+		CardID:       card.ID,
+		BoardID:      boardID,
+		ColumnID:     card.ColumnID,
+		Name:         card.Name,
+		UpdatedDate:  card.UpdatedDate,
+		CommentCount: card.CommentCount,
+		Archived:     card.ArchivedDate != "",
+	}
+	for _, label := range card.Labels {
+		doc.LabelIDs = append(doc.LabelIDs, label.ID)
+	}
+
+	fields := []string{card.Name}
+	if card.Description != nil {
+		fields = append(fields, card.Description.Text)
+	}
+	for _, comment := range comments {
+		fields = append(fields, comment.Text)
+	}
+
+	pos := 0
+	for i, field := range fields {
+		tokens := Tokenize(field)
+		for j, token := range tokens {
+			postings, ok := ix.postings[token]
+			if !ok {
+				postings = map[string][]int{}
+				ix.postings[token] = postings
+			}
+			if _, ok := postings[card.ID]; !ok {
+				ix.terms[card.ID] = append(ix.terms[card.ID], token)
+			}
+			postings[card.ID] = append(postings[card.ID], pos+j)
+		}
+		doc.Length += len(tokens)
+		if i == 0 {
+			doc.NameLength = len(tokens)
+		}
+		pos += len(tokens) + fieldGap
+	}
+
+	ix.docs[card.ID] = doc
+	ix.totalLength += doc.Length
+}
+
+// Remove removes a card from the index
+func (ix *Index) Remove(cardID string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.remove(cardID)
+}
+
+func (ix *Index) remove(cardID string) {
+	doc, ok := ix.docs[cardID]
+	if !ok {
+		return
+	}
+
+	for _, term := range ix.terms[cardID] {
+		postings := ix.postings[term]
+		delete(postings, cardID)
+		if len(postings) == 0 {
+			delete(ix.postings, term)
+		}
+	}
+	ix.totalLength -= doc.Length
+	delete(ix.terms, cardID)
+	delete(ix.docs, cardID)
+}
+
+// SetBoard records the names of a board's columns and labels
+func (ix *Index) SetBoard(board *glo.Board) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	info := &BoardInfo{
+		ID:      board.ID,
+		Name:    board.Name,
+		Columns: map[string]string{},
+		Labels:  map[string]string{},
+	}
+	for _, group := range [][]*glo.Column{board.Columns, board.ArchivedColumns} {
+		for _, col := range group {
+			info.Columns[col.ID] = col.Name
+		}
+	}
+	for _, label := range board.Labels {
+		info.Labels[label.ID] = label.Name
+	}
+	ix.boards[board.ID] = info
+}
+
+// Board the indexed names of a board's columns and labels
+func (ix *Index) Board(boardID string) *BoardInfo {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return ix.boards[boardID]
+}
+
+// Document the indexed fields of a card, or nil
+func (ix *Index) Document(cardID string) *Document {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return ix.docs[cardID]
+}
+
+// Len the number of indexed cards
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.docs)
+}
+
+// cardIDs the IDs of the indexed cards of a board
+func (ix *Index) cardIDs(boardID string) []string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	var ids []string
+	for id, doc := range ix.docs {
+		if doc.BoardID == boardID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Tokenize splits text into lower case words, Markdown
+// punctuation and other symbols separate words
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
@@ -0,0 +1,72 @@
+package fulltext
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", []string{}},
+		{"   ", []string{}},
+		{"Login", []string{"login"}},
+		{"Fix the LOGIN page", []string{"fix", "the", "login", "page"}},
+		{"**bold** _em_ `code`", []string{"bold", "em", "code"}},
+		{"- [x] done\n- [ ] todo", []string{"x", "done", "todo"}},
+		{"[docs](https://example.com/a-b)", []string{"docs", "https", "example", "com", "a", "b"}},
+		{"v1.2.3 #42 @bob", []string{"v1", "2", "3", "42", "bob"}},
+		{"don't", []string{"don", "t"}},
+		{"Grüße Café", []string{"grüße", "café"}},
+		{"日本語 テキスト", []string{"日本語", "テキスト"}},
+		{"tab\tseparated\nlines", []string{"tab", "separated", "lines"}},
+	}
+
+	for _, test := range tests {
+		if got := Tokenize(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
+
+func TestAddRemove(t *testing.T) {
+	ix := New()
+	card := &glo.Card{
+		ID:          "k1",
+		Name:        "Login page",
+		Description: &glo.Description{Text: "Crashes on submit"},
+	}
+	ix.Add("b1", card, []*glo.Comment{{Text: "seen on staging"}})
+
+	doc := ix.Document("k1")
+	if doc == nil {
+		t.Fatal("card was not indexed")
+	}
+	if doc.Length != 8 || doc.NameLength != 2 {
+		t.Errorf("indexed %d tokens, %d in the name, want 8 and 2", doc.Length, doc.NameLength)
+	}
+
+	card.Name = "Logout page"
+	ix.Add("b1", card, nil)
+	if ix.Len() != 1 {
+		t.Errorf("re-adding a card indexed %d cards", ix.Len())
+	}
+	if results, _ := ix.Search("login", nil); len(results) != 0 {
+		t.Errorf("the replaced name still matches")
+	}
+	if results, _ := ix.Search("staging", nil); len(results) != 0 {
+		t.Errorf("the replaced comment still matches")
+	}
+
+	ix.Remove("k1")
+	if ix.Len() != 0 || ix.Document("k1") != nil {
+		t.Errorf("the card was not removed")
+	}
+	if results, _ := ix.Search("page", nil); len(results) != 0 {
+		t.Errorf("a removed card matched")
+	}
+}
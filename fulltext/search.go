@@ -0,0 +1,241 @@
+package fulltext
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// SearchOptions filters and limits search results, filters
+// match IDs or case-insensitive names
+type SearchOptions struct {
+	Boards  []string
+	Columns []string
+	Labels  []string
+
+	// Archived includes archived cards
+	Archived bool
+
+	// Limit the maximum number of results, unlimited when zero
+	Limit int
+}
+
+// Result a card matching a search
+type Result struct {
+	CardID   string  `json:"card_id"`
+	BoardID  string  `json:"board_id"`
+	ColumnID string  `json:"column_id"`
+	Name     string  `json:"name"`
+	Score    float64 `json:"score"`
+}
+
+// BM25 parameters, matches in card names count nameBoost times
+const (
+	k1        = 1.2
+	b         = 0.75
+	nameBoost = 3
+)
+
+// Search finds the cards containing every word and "quoted phrase"
+// of the query, ranked by relevance with BM25
+func (ix *Index) Search(q string, opts *SearchOptions) (results []*Result, err error) {
+	if opts == nil {
+		opts = &SearchOptions{}
+	}
+
+	words, phrases, err := parseQuery(q)
+	if err != nil {
+		return
+	}
+	if len(words) == 0 {
+		return
+	}
+
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	for _, id := range ix.candidates(words) {
+		doc := ix.docs[id]
+		if !ix.filter(doc, opts) || !ix.hasPhrases(id, phrases) {
+			continue
+		}
+		results = append(results, &Result{
+			CardID:   doc.CardID,
+			BoardID:  doc.BoardID,
+			ColumnID: doc.ColumnID,
+			Name:     doc.Name,
+			Score:    ix.score(doc, words),
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].CardID < results[j].CardID
+	})
+	if opts.Limit > 0 && len(results) > opts.Limit {
+		results = results[:opts.Limit]
+	}
+
+	return
+}
+
+// parseQuery splits a query into its distinct words and its phrases
+func parseQuery(q string) (words []string, phrases [][]string, err error) {
+	if strings.Count(q, `"`)%2 != 0 {
+		err = fmt.Errorf("unterminated phrase in %q", q)
+		return
+	}
+
+	seen := map[string]bool{}
+	for i, part := range strings.Split(q, `"`) {
+		tokens := Tokenize(part)
+		// odd parts are quoted
+		if i%2 == 1 && len(tokens) > 1 {
+			phrases = append(phrases, tokens)
+		}
+		for _, token := range tokens {
+			if !seen[token] {
+				seen[token] = true
+				words = append(words, token)
+			}
+		}
+	}
+
+	return
+}
+
+// candidates the cards containing every word
+func (ix *Index) candidates(words []string) (ids []string) {
+	// start from the rarest word
+	sorted := append([]string{}, words...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return len(ix.postings[sorted[i]]) < len(ix.postings[sorted[j]])
+	})
+
+	for id := range ix.postings[sorted[0]] {
+		all := true
+		for _, word := range sorted[1:] {
+			if _, ok := ix.postings[word][id]; !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return
+}
+
+// hasPhrases reports whether a card contains every phrase
+func (ix *Index) hasPhrases(id string, phrases [][]string) bool {
+	for _, phrase := range phrases {
+		if !ix.hasPhrase(id, phrase) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (ix *Index) hasPhrase(id string, phrase []string) bool {
+	next := make([]map[int]bool, len(phrase))
+	for i, word := range phrase[1:] {
+		next[i+1] = map[int]bool{}
+		for _, pos := range ix.postings[word][id] {
+			next[i+1][pos] = true
+		}
+	}
+
+	for _, start := range ix.postings[phrase[0]][id] {
+		found := true
+		for i := 1; i < len(phrase); i++ {
+			if !next[i][start+i] {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filter reports whether a card passes the search filters
+func (ix *Index) filter(doc *Document, opts *SearchOptions) bool {
+	if doc.Archived && !opts.Archived {
+		return false
+	}
+
+	board := ix.boards[doc.BoardID]
+	if board == nil {
+		board = &BoardInfo{ID: doc.BoardID}
+	}
+
+	if len(opts.Boards) > 0 && !matchAny(opts.Boards, doc.BoardID, board.Name) {
+		return false
+	}
+	if len(opts.Columns) > 0 && !matchAny(opts.Columns, doc.ColumnID, board.Columns[doc.ColumnID]) {
+		return false
+	}
+	if len(opts.Labels) > 0 {
+		found := false
+		for _, id := range doc.LabelIDs {
+			if matchAny(opts.Labels, id, board.Labels[id]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchAny(wanted []string, id, name string) bool {
+	for _, want := range wanted {
+		if want == id || (name != "" && strings.EqualFold(want, name)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// score the BM25 score of a card for the words
+func (ix *Index) score(doc *Document, words []string) (score float64) {
+	n := float64(len(ix.docs))
+	avgLength := float64(ix.totalLength) / n
+	if avgLength == 0 {
+		avgLength = 1
+	}
+
+	for _, word := range words {
+		postings := ix.postings[word]
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		var tf float64
+		for _, pos := range postings[doc.CardID] {
+			if pos < doc.NameLength {
+				tf += nameBoost
+			} else {
+				tf++
+			}
+		}
+
+		norm := 1 - b + b*float64(doc.Length)/avgLength
+		score += idf * tf * (k1 + 1) / (tf + k1*norm)
+	}
+
+	return
+}
@@ -0,0 +1,141 @@
+package fulltext
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func testIndex() *Index {
+	ix := New()
+	ix.SetBoard(&glo.Board{
+		ID:      "b1",
+		Name:    "Team",
+		Columns: []*glo.Column{{ID: "c1", Name: "To Do"}, {ID: "c2", Name: "Done"}},
+		Labels:  []*glo.Label{{ID: "l1", Name: "bug"}},
+	})
+
+	ix.Add("b1", &glo.Card{
+		ID:          "k1",
+		Name:        "Login page crashes",
+		ColumnID:    "c1",
+		Labels:      []*glo.PartialLabel{{ID: "l1"}},
+		Description: &glo.Description{Text: "The page crashes after submitting the login form"},
+	}, []*glo.Comment{{Text: "Reproduced on the staging server"}})
+	ix.Add("b1", &glo.Card{
+		ID:          "k2",
+		Name:        "Server setup",
+		ColumnID:    "c2",
+		Description: &glo.Description{Text: "Staging login works, page loads"},
+	}, nil)
+	ix.Add("b2", &glo.Card{
+		ID:           "k3",
+		Name:         "Old login",
+		ColumnID:     "c9",
+		ArchivedDate: "2019-01-01T00:00:00Z",
+	}, nil)
+
+	return ix
+}
+
+func ids(results []*Result) []string {
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.CardID)
+	}
+
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	ix := testIndex()
+
+	tests := []struct {
+		query string
+		opts  *SearchOptions
+		want  []string
+	}{
+		{"", nil, nil},
+		{`""`, nil, nil},
+		{"missing", nil, nil},
+		{"login", nil, []string{"k1", "k2"}},
+		{"LOGIN", nil, []string{"k1", "k2"}},
+		{"login missing", nil, nil},
+		{"staging", nil, []string{"k2", "k1"}},
+		{"server", nil, []string{"k2", "k1"}},
+		{"crashes", nil, []string{"k1"}},
+		{`"login page"`, nil, []string{"k1"}},
+		{`"page login"`, nil, nil},
+		{`"login form"`, nil, []string{"k1"}},
+		{`"staging server"`, nil, []string{"k1"}},
+		{`"staging login"`, nil, []string{"k2"}},
+		{`"the staging"`, nil, []string{"k1"}},
+		{`"login" page`, nil, []string{"k1", "k2"}},
+		{`"crashes reproduced"`, nil, nil},
+		{`"crashes the"`, nil, nil},
+		{`"login page" "staging server"`, nil, []string{"k1"}},
+		{`"login page" "staging login"`, nil, nil},
+		{"login", &SearchOptions{Archived: true}, []string{"k3", "k1", "k2"}},
+		{"login", &SearchOptions{Boards: []string{"team"}}, []string{"k1", "k2"}},
+		{"login", &SearchOptions{Boards: []string{"b2"}, Archived: true}, []string{"k3"}},
+		{"login", &SearchOptions{Columns: []string{"done"}}, []string{"k2"}},
+		{"login", &SearchOptions{Columns: []string{"c1"}}, []string{"k1"}},
+		{"login", &SearchOptions{Labels: []string{"BUG"}}, []string{"k1"}},
+		{"login", &SearchOptions{Labels: []string{"feature"}}, nil},
+		{"login", &SearchOptions{Limit: 1}, []string{"k1"}},
+	}
+
+	for _, test := range tests {
+		results, err := ix.Search(test.query, test.opts)
+		if err != nil {
+			t.Errorf("Search(%q) err:%s", test.query, err)
+			continue
+		}
+		if got := ids(results); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Search(%q, %+v) = %v, want %v", test.query, test.opts, got, test.want)
+		}
+	}
+}
+
+func TestSearchUnterminatedPhrase(t *testing.T) {
+	_, err := testIndex().Search(`"login page`, nil)
+	if err == nil {
+		t.Error("an unterminated phrase was accepted")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "index.json")
+
+	missing, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load of a missing file err:%s", err)
+	}
+	if missing.Len() != 0 {
+		t.Errorf("a missing file loaded %d cards", missing.Len())
+	}
+
+	ix := testIndex()
+	err = ix.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range []string{"login", `"staging server"`, "crashes"} {
+		opts := &SearchOptions{Archived: true}
+		want, _ := ix.Search(query, opts)
+		got, _ := loaded.Search(query, opts)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Search(%q) of the loaded index = %v, want %v", query, ids(got), ids(want))
+		}
+	}
+	if !reflect.DeepEqual(loaded.Board("b1"), ix.Board("b1")) {
+		t.Errorf("board names were not saved")
+	}
+}
@@ -0,0 +1,108 @@
+package fulltext
+
+import (
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// formatVersion the version of the index file format
+const formatVersion = 1
+
+// stored the persisted form of an index
+type stored struct {
+	Version  int
+	Boards   map[string]*BoardInfo
+	Docs     map[string]*Document
+	Postings map[string]map[string][]int
+}
+
+// DefaultPath the index file stored alongside the glo config file
+func DefaultPath() (path string, err error) {
+	config, err := glo.ConfigPath()
+	if err != nil {
+		return
+	}
+
+	return filepath.Join(filepath.Dir(config), "index.gob"), nil
+}
+
+// Load reads an index file, a missing file is an empty index
+func Load(path string) (ix *Index, err error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return New(), nil
+	}
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	s := &stored{}
+	err = gob.NewDecoder(f).Decode(s)
+	if err != nil {
+		err = fmt.Errorf("failed to read index %s err:%s", path, err)
+		return
+	}
+	if s.Version != formatVersion {
+		err = fmt.Errorf("index %s has unsupported version %d, remove it to rebuild", path, s.Version)
+		return
+	}
+
+	ix = New()
+	if s.Boards != nil {
+		ix.boards = s.Boards
+	}
+	if s.Docs != nil {
+		ix.docs = s.Docs
+	}
+	if s.Postings != nil {
+		ix.postings = s.Postings
+	}
+	for term, postings := range ix.postings {
+		for id := range postings {
+			ix.terms[id] = append(ix.terms[id], term)
+		}
+	}
+	for _, doc := range ix.docs {
+		ix.totalLength += doc.Length
+	}
+
+	return
+}
+
+// Save writes the index to a file, replacing it atomically
+func (ix *Index) Save(path string) (err error) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return
+	}
+
+	err = gob.NewEncoder(f).Encode(&stored{
+		Version:  formatVersion,
+		Boards:   ix.boards,
+		Docs:     ix.docs,
+		Postings: ix.postings,
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return
+	}
+
+	return os.Rename(tmp, path)
+}
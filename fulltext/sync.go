@@ -0,0 +1,165 @@
+package fulltext
+
+import (
+	"github.com/jackmcguire1/go-glo"
+)
+
+// SyncOptions contains information used to sync an index
+type SyncOptions struct {
+	// Archived indexes archived cards
+	Archived bool
+}
+
+// SyncStats counts the changes made by a sync
+type SyncStats struct {
+	Boards    int `json:"boards"`
+	Indexed   int `json:"indexed"`
+	Unchanged int `json:"unchanged"`
+	Removed   int `json:"removed"`
+}
+
+// Sync brings the index up to date with boards, every board when none
+// are given. Only cards whose UpdatedDate or comment count changed are
+// re-read with their comments, cards which no longer exist are removed.
+func (ix *Index) Sync(
+	client *glo.Glo,
+	boardIDs []string,
+	opts *SyncOptions,
+) (
+	stats *SyncStats,
+	err error,
+) {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+	stats = &SyncStats{}
+
+	all := len(boardIDs) == 0
+	if all {
+		var boards []*glo.Board
+		boards, err = client.AllBoards(false, glo.Fields(glo.BoardFieldName))
+		if err != nil {
+			return
+		}
+		for _, board := range boards {
+			boardIDs = append(boardIDs, board.ID)
+		}
+	}
+
+	for _, boardID := range boardIDs {
+		err = ix.syncBoard(client, boardID, opts, stats)
+		if err != nil {
+			return
+		}
+		stats.Boards++
+	}
+
+	if all {
+		stats.Removed += ix.prune(boardIDs)
+	}
+
+	return
+}
+
+func (ix *Index) syncBoard(
+	client *glo.Glo,
+	boardID string,
+	opts *SyncOptions,
+	stats *SyncStats,
+) (err error) {
+	board, err := client.GetBoard(boardID)
+	if err != nil {
+		return
+	}
+	board.ID = boardID
+	ix.SetBoard(board)
+
+	cards, err := client.AllCards(boardID, false)
+	if err != nil {
+		return
+	}
+	if opts.Archived {
+		var archived []*glo.Card
+		archived, err = client.AllCards(boardID, true)
+		if err != nil {
+			return
+		}
+		cards = append(cards, archived...)
+	}
+
+	listed := map[string]bool{}
+	for _, card := range cards {
+		listed[card.ID] = true
+
+		if ix.refresh(boardID, card) {
+			stats.Unchanged++
+			continue
+		}
+
+		var comments []*glo.Comment
+		if card.CommentCount > 0 {
+			comments, err = client.AllComments(boardID, card.ID)
+			if err != nil {
+				return
+			}
+		}
+		ix.Add(boardID, card, comments)
+		stats.Indexed++
+	}
+
+	for _, id := range ix.cardIDs(boardID) {
+		if !listed[id] {
+			ix.Remove(id)
+			stats.Removed++
+		}
+	}
+
+	return
+}
+
+// refresh updates the column, labels and archived state of an indexed
+// card whose text is unchanged, reporting false when it must be re-read
+func (ix *Index) refresh(boardID string, card *glo.Card) bool {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	doc, ok := ix.docs[card.ID]
+	if !ok || card.UpdatedDate == "" || doc.BoardID != boardID ||
+		doc.UpdatedDate != card.UpdatedDate || doc.CommentCount != card.CommentCount {
+		return false
+	}
+
+	doc.ColumnID = card.ColumnID
+	doc.Archived = card.ArchivedDate != ""
+	doc.LabelIDs = nil
+	for _, label := range card.Labels {
+		doc.LabelIDs = append(doc.LabelIDs, label.ID)
+	}
+
+	return true
+}
+
+// prune removes the cards of boards which are not listed
+func (ix *Index) prune(boardIDs []string) (removed int) {
+	keep := map[string]bool{}
+	for _, id := range boardIDs {
+		keep[id] = true
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	for id, doc := range ix.docs {
+		if !keep[doc.BoardID] {
+			ix.remove(id)
+			removed++
+		}
+	}
+	for id := range ix.boards {
+		if !keep[id] {
+			delete(ix.boards, id)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,129 @@
+package gitlink
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Commit a git commit
+type Commit struct {
+	Hash    string    `json:"hash"`
+	Author  string    `json:"author"`
+	Email   string    `json:"email"`
+	Date    time.Time `json:"date"`
+	Message string    `json:"message"`
+}
+
+// ShortHash the abbreviated commit hash
+func (c *Commit) ShortHash() string {
+	if len(c.Hash) > 7 {
+		return c.Hash[:7]
+	}
+
+	return c.Hash
+}
+
+// Subject the first line of the commit message
+func (c *Commit) Subject() string {
+	return strings.SplitN(c.Message, "\n", 2)[0]
+}
+
+// Repository a local git repository
+type Repository struct {
+	// Dir a directory within the working tree
+	Dir string
+}
+
+// git runs a git command in the repository, returning its output
+func (r *Repository) git(args ...string) (out []byte, err error) {
+	cmd := exec.Command("git", append([]string{"-C", r.Dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err = cmd.Output()
+	if err != nil {
+		err = fmt.Errorf("git %s failed err:%s %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return
+}
+
+const (
+	fieldSep  = "\x1f"
+	recordSep = "\x1e"
+)
+
+// Log the commits of a revision range, oldest first
+func (r *Repository) Log(revisions ...string) (commits []*Commit, err error) {
+	if len(revisions) == 0 {
+		revisions = []string{"HEAD"}
+	}
+
+	args := []string{
+		"log",
+		"--reverse",
+		"--format=%H%x1f%an%x1f%ae%x1f%aI%x1f%B%x1e",
+	}
+	out, err := r.git(append(append(args, revisions...), "--")...)
+	if err != nil {
+		return
+	}
+
+	for _, record := range strings.Split(string(out), recordSep) {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		fields := strings.SplitN(record, fieldSep, 5)
+		if len(fields) != 5 {
+			err = fmt.Errorf("unexpected git log output %q", record)
+			return
+		}
+
+		commit := &Commit{
+			Hash:    fields[0],
+			Author:  fields[1],
+			Email:   fields[2],
+			Message: strings.TrimSpace(fields[4]),
+		}
+		commit.Date, err = time.Parse(time.RFC3339, fields[3])
+		if err != nil {
+			err = fmt.Errorf("failed to parse date of commit %s err:%s", commit.Hash, err)
+			return
+		}
+		commits = append(commits, commit)
+	}
+
+	return
+}
+
+// GitDir the absolute path of the repository's git directory
+func (r *Repository) GitDir() (dir string, err error) {
+	out, err := r.git("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return
+	}
+
+	return filepath.Clean(strings.TrimSpace(string(out))), nil
+}
+
+// Config a git config value, empty when it is not set
+func (r *Repository) Config(key string) (value string, err error) {
+	cmd := exec.Command("git", "-C", r.Dir, "config", "--get", key)
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		// the key is not set
+		return "", nil
+	}
+	if err != nil {
+		err = fmt.Errorf("git config failed err:%s", err)
+		return
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,169 @@
+package gitlink
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Hooks the git hooks installed by InstallHooks
+var Hooks = []string{"prepare-commit-msg", "post-commit"}
+
+// hookMarker identifies hooks written by InstallHooks
+const hookMarker = "# installed by glo hooks"
+
+// ErrHookExists returned when a hook which was not
+// installed by InstallHooks already exists
+var ErrHookExists = errors.New("hook already exists")
+
+// BranchPattern matches the card ID in branch names such as
+// glo-<cardID>, feature/<cardID>-login or <cardID>_fix, Glo
+// card IDs are 24 hexadecimal characters
+var BranchPattern = regexp.MustCompile(`(?i)(?:^|[/_.-])(?:glo[-_#]?)?([0-9a-f]{24})(?:$|[/_.-])`)
+
+// CardFromBranch the ID of the card a branch name refers to, or empty
+func CardFromBranch(branch string) string {
+	m := BranchPattern.FindStringSubmatch(branch)
+	if m == nil {
+		return ""
+	}
+
+	return strings.ToLower(m[1])
+}
+
+// Branch the name of the checked out branch, empty when HEAD is detached
+func (r *Repository) Branch() (branch string, err error) {
+	out, err := r.git("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return
+	}
+	branch = strings.TrimSpace(string(out))
+	if branch == "HEAD" {
+		branch = ""
+	}
+
+	return
+}
+
+// HooksDir the directory git runs hooks from, honouring core.hooksPath
+func (r *Repository) HooksDir() (dir string, err error) {
+	out, err := r.git("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return
+	}
+
+	dir = strings.TrimSpace(string(out))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.Dir, dir)
+	}
+
+	return
+}
+
+// PrepareMessage prefixes the subject of a commit message with a
+// reference to the card, unless the message already references it
+func PrepareMessage(message, cardID string) string {
+	for _, ref := range ParseReferences(message, nil) {
+		if strings.EqualFold(ref.CardID, cardID) {
+			return message
+		}
+	}
+
+	// a template starting with comments gets a subject line
+	// of its own, otherwise the reference starts the subject
+	if strings.HasPrefix(message, "#") {
+		return Format(cardID) + " \n" + message
+	}
+
+	return Format(cardID) + " " + message
+}
+
+// hookScript runs a glo hook subcommand, prepare-commit-msg
+// must never block a commit because glo failed
+func hookScript(command, hook string) string {
+	return fmt.Sprintf(
+		"#!/bin/sh\n%s\n%s hooks run %s \"$@\" || true\n",
+		hookMarker,
+		shellQuote(command),
+		hook,
+	)
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// InstallHooks writes the hooks into the repository's hooks directory,
+// running command, the path of the glo executable. Existing hooks not
+// written by InstallHooks are only replaced when force is set.
+func (r *Repository) InstallHooks(command string, force bool) (paths []string, err error) {
+	dir, err := r.HooksDir()
+	if err != nil {
+		return
+	}
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return
+	}
+
+	for _, hook := range Hooks {
+		path := filepath.Join(dir, hook)
+		if !force && foreignHook(path) {
+			err = fmt.Errorf("%s: %w, use force to replace it", path, ErrHookExists)
+			return
+		}
+	}
+
+	for _, hook := range Hooks {
+		path := filepath.Join(dir, hook)
+		err = ioutil.WriteFile(path, []byte(hookScript(command, hook)), 0755)
+		if err != nil {
+			return
+		}
+		// WriteFile keeps the mode of existing files
+		err = os.Chmod(path, 0755)
+		if err != nil {
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	return
+}
+
+// UninstallHooks removes the hooks written by InstallHooks
+func (r *Repository) UninstallHooks() (paths []string, err error) {
+	dir, err := r.HooksDir()
+	if err != nil {
+		return
+	}
+
+	for _, hook := range Hooks {
+		path := filepath.Join(dir, hook)
+		data, readErr := ioutil.ReadFile(path)
+		if readErr != nil || !strings.Contains(string(data), hookMarker) {
+			continue
+		}
+		err = os.Remove(path)
+		if err != nil {
+			return
+		}
+		paths = append(paths, path)
+	}
+
+	return
+}
+
+// foreignHook reports whether a hook exists which glo did not install
+func foreignHook(path string) bool {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+
+	return !strings.Contains(string(data), hookMarker)
+}
@@ -0,0 +1,188 @@
+package gitlink
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCardFromBranch(t *testing.T) {
+	id := "5d1e8d6c3b4a2f0012345678"
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{id, id},
+		{"glo-" + id, id},
+		{"glo#" + id, id},
+		{"GLO_" + strings.ToUpper(id), id},
+		{"feature/" + id + "-login", id},
+		{"fix/glo-" + id + "/retry", id},
+		{id + "_fix", id},
+		{"release." + id, id},
+		{"main", ""},
+		{"", ""},
+		// too short, too long and not hexadecimal
+		{"glo-" + id[:23], ""},
+		{"glo-" + id + "9", ""},
+		{"glo-" + id[:23] + "g", ""},
+		// the ID must be a whole part of the name
+		{"feature" + id, ""},
+		{"feature/" + id + "login", ""},
+	}
+
+	for _, test := range tests {
+		if got := CardFromBranch(test.branch); got != test.want {
+			t.Errorf("CardFromBranch(%q) got %q, want %q", test.branch, got, test.want)
+		}
+	}
+}
+
+func TestPrepareMessage(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"empty", "", "glo#c1 "},
+		{"subject", "Add login\n", "glo#c1 Add login\n"},
+		{"template comments", "# Please enter the commit message\n", "glo#c1 \n# Please enter the commit message\n"},
+		{"already referenced", "Add login\n\nfixes glo#C1\n", "Add login\n\nfixes glo#C1\n"},
+		{"other card referenced", "Add login glo#c2", "glo#c1 Add login glo#c2"},
+	}
+
+	for _, test := range tests {
+		if got := PrepareMessage(test.message, "c1"); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
+
+func TestBranch(t *testing.T) {
+	repo := newRepo(t, "initial")
+	if _, err := repo.git("checkout", "-q", "-b", "glo-5d1e8d6c3b4a2f0012345678"); err != nil {
+		t.Fatal(err)
+	}
+
+	branch, err := repo.Branch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if CardFromBranch(branch) != "5d1e8d6c3b4a2f0012345678" {
+		t.Errorf("got branch %q", branch)
+	}
+
+	if _, err := repo.git("checkout", "-q", "--detach"); err != nil {
+		t.Fatal(err)
+	}
+	branch, err = repo.Branch()
+	if err != nil || branch != "" {
+		t.Errorf("got detached branch %q err:%v", branch, err)
+	}
+}
+
+func TestInstallHooks(t *testing.T) {
+	repo := newRepo(t)
+	dir := filepath.Join(repo.Dir, ".git", "hooks")
+
+	// a hook glo did not install, which must never be removed
+	foreign := filepath.Join(dir, "post-commit")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(foreign, []byte("#!/bin/sh\necho mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := repo.InstallHooks("/usr/local/bin/glo", false)
+	if !errors.Is(err, ErrHookExists) {
+		t.Fatalf("got err %v, want ErrHookExists", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "prepare-commit-msg")); !os.IsNotExist(err) {
+		t.Error("a hook was installed despite the existing hook")
+	}
+
+	// uninstalling leaves the existing hook alone
+	paths, err := repo.UninstallHooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 0 || readFile(t, foreign) != "#!/bin/sh\necho mine\n" {
+		t.Errorf("uninstall removed %v", paths)
+	}
+
+	paths, err = repo.InstallHooks("/opt/my glo's/glo", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != len(Hooks) {
+		t.Fatalf("installed %v", paths)
+	}
+	for i, hook := range Hooks {
+		path := filepath.Join(dir, hook)
+		if paths[i] != path {
+			t.Errorf("installed %s, want %s", paths[i], path)
+		}
+
+		script := readFile(t, path)
+		want := `'/opt/my glo'\''s/glo' hooks run ` + hook + ` "$@" || true`
+		if !strings.HasPrefix(script, "#!/bin/sh\n"+hookMarker+"\n") || !strings.Contains(script, want) {
+			t.Errorf("got %s script:\n%s", hook, script)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0755 {
+			t.Errorf("%s has mode %s, want it executable", hook, info.Mode())
+		}
+	}
+
+	// glo's own hooks are replaced without force
+	if _, err := repo.InstallHooks("glo", false); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err = repo.UninstallHooks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != len(Hooks) {
+		t.Errorf("uninstalled %v", paths)
+	}
+	for _, hook := range Hooks {
+		if _, err := os.Stat(filepath.Join(dir, hook)); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", hook)
+		}
+	}
+}
+
+func TestInstallHooksPath(t *testing.T) {
+	repo := newRepo(t)
+	if _, err := repo.git("config", "core.hooksPath", "githooks"); err != nil {
+		t.Fatal(err)
+	}
+
+	paths, err := repo.InstallHooks("glo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(repo.Dir, "githooks", Hooks[0]); len(paths) == 0 || paths[0] != want {
+		t.Errorf("installed %v, want %s first", paths, want)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,184 @@
+package gitlink
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Linker comments on, and optionally moves, the cards referenced by commits
+type Linker struct {
+	Client  *glo.Glo
+	BoardID string
+
+	// DoneColumn the column, by ID or name, cards are moved to when a
+	// commit closes them, cards are never moved when it is empty
+	DoneColumn string
+
+	// Keywords the closing keywords, defaults to DefaultKeywords
+	Keywords []string
+
+	// CommitURL a format string linking to a commit by its hash,
+	// e.g. https://github.com/owner/repo/commit/%s
+	CommitURL string
+
+	// State the references already linked, every reference
+	// is linked again when it is nil
+	State *State
+
+	// DryRun reports the links which would be made without making them
+	DryRun bool
+
+	doneColumnID string
+}
+
+// Link the outcome of linking a commit to a card
+type Link struct {
+	Commit *Commit `json:"commit"`
+	Reference
+
+	Commented bool   `json:"commented"`
+	Moved     bool   `json:"moved"`
+	Skipped   bool   `json:"skipped"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Link links the commits to the cards they reference, in order,
+// errors linking a single card are recorded on its Link and
+// the state is saved as references are linked
+func (l *Linker) Link(commits []*Commit) (links []*Link, err error) {
+	state := l.State
+	if state == nil {
+		state = &State{Links: map[string]*LinkRecord{}}
+	}
+	defer func() {
+		if l.DryRun {
+			return
+		}
+		if saveErr := state.Save(); err == nil && saveErr != nil {
+			err = fmt.Errorf("failed to save link state err:%s", saveErr)
+		}
+	}()
+
+	for _, commit := range commits {
+		for _, ref := range ParseReferences(commit.Message, l.Keywords) {
+			link := &Link{Commit: commit, Reference: *ref}
+			links = append(links, link)
+
+			record := state.Record(commit.Hash, ref.CardID)
+			move := ref.Closes && l.DoneColumn != ""
+			if record.Commented != nil && (!move || record.Moved != nil) {
+				link.Skipped = true
+				continue
+			}
+			if l.DryRun {
+				link.Commented = record.Commented == nil
+				link.Moved = move && record.Moved == nil
+				continue
+			}
+
+			linkErr := l.link(link, record, move)
+			if linkErr != nil {
+				link.Error = linkErr.Error()
+			}
+			if err = state.Save(); err != nil {
+				return
+			}
+		}
+	}
+
+	return
+}
+
+func (l *Linker) link(link *Link, record *LinkRecord, move bool) (err error) {
+	if record.Commented == nil {
+		_, err = l.Client.CreateComment(l.BoardID, link.CardID, &glo.CommentInput{
+			Text: l.comment(link.Commit),
+		})
+		if err != nil {
+			return
+		}
+		now := time.Now().UTC()
+		record.Commented = &now
+		link.Commented = true
+	}
+
+	if move && record.Moved == nil {
+		err = l.move(link.CardID)
+		if err != nil {
+			return
+		}
+		now := time.Now().UTC()
+		record.Moved = &now
+		link.Moved = true
+	}
+
+	return
+}
+
+// comment describes a commit in Markdown
+func (l *Linker) comment(commit *Commit) string {
+	hash := fmt.Sprintf("`%s`", commit.ShortHash())
+	if l.CommitURL != "" {
+		hash = fmt.Sprintf("[%s](%s)", hash, fmt.Sprintf(l.CommitURL, commit.Hash))
+	}
+
+	lines := strings.Split(commit.Message, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+
+	return fmt.Sprintf(
+		"Referenced in commit %s by %s on %s\n\n%s",
+		hash,
+		commit.Author,
+		commit.Date.Format("2006-01-02 15:04 MST"),
+		strings.Join(lines, "\n"),
+	)
+}
+
+// move moves a card to the done column
+func (l *Linker) move(cardID string) (err error) {
+	columnID, err := l.doneColumn()
+	if err != nil {
+		return
+	}
+
+	card, err := l.Client.GetCard(l.BoardID, cardID)
+	if err != nil {
+		return
+	}
+	if card.ColumnID == columnID {
+		return
+	}
+
+	input := card.Input()
+	input.ColumnID = columnID
+	_, err = l.Client.EditCard(l.BoardID, cardID, input)
+
+	return
+}
+
+// doneColumn resolves the done column by ID or name
+func (l *Linker) doneColumn() (id string, err error) {
+	if l.doneColumnID != "" {
+		return l.doneColumnID, nil
+	}
+
+	board, err := l.Client.GetBoard(l.BoardID, glo.Fields(glo.BoardFieldColumns))
+	if err != nil {
+		return
+	}
+	for _, col := range board.Columns {
+		if col.ID == l.DoneColumn || strings.EqualFold(col.Name, l.DoneColumn) {
+			l.doneColumnID = col.ID
+			return col.ID, nil
+		}
+	}
+
+	err = fmt.Errorf("board %s has no column %q", l.BoardID, l.DoneColumn)
+
+	return
+}
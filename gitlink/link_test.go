@@ -0,0 +1,250 @@
+package gitlink
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// newRepo creates a git repository with a commit for each message
+func newRepo(t *testing.T, messages ...string) *Repository {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Ada")
+	t.Setenv("GIT_AUTHOR_EMAIL", "ada@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Ada")
+	t.Setenv("GIT_COMMITTER_EMAIL", "ada@example.com")
+
+	repo := &Repository{Dir: t.TempDir()}
+	if _, err := repo.git("init", "-q", "-b", "main"); err != nil {
+		t.Fatal(err)
+	}
+	for _, message := range messages {
+		if _, err := repo.git("commit", "-q", "--allow-empty", "-m", message); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return repo
+}
+
+// fakeBoard a board with Doing and Done columns, recording
+// the comments created and the cards moved
+type fakeBoard struct {
+	mu       sync.Mutex
+	comments map[string][]string
+	moves    map[string]string
+	failCard string
+}
+
+func (f *fakeBoard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var v interface{}
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 2:
+		v = &glo.Board{ID: "b1", Columns: []*glo.Column{{ID: "doing", Name: "Doing"}, {ID: "done", Name: "Done"}}}
+	case len(parts) >= 4 && parts[3] == f.failCard:
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	case r.Method == http.MethodGet && len(parts) == 4:
+		v = &glo.Card{ID: parts[3], Name: "Card", ColumnID: "doing"}
+	case r.Method == http.MethodPost && len(parts) == 4:
+		input := &glo.CardsInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		f.moves[parts[3]] = input.ColumnID
+		v = &glo.Card{ID: parts[3], ColumnID: input.ColumnID}
+	case r.Method == http.MethodPost && len(parts) == 5 && parts[4] == "comments":
+		input := &glo.CommentInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		f.comments[parts[3]] = append(f.comments[parts[3]], input.Text)
+		v = &glo.Comment{ID: "comment"}
+	}
+	if v == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	json.NewEncoder(w).Encode(v)
+}
+
+func newLinker(t *testing.T, fake *fakeBoard, state *State) *Linker {
+	t.Helper()
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client := glo.NewClient("token")
+	client.BaseURI = srv.URL
+
+	return &Linker{
+		Client:     client,
+		BoardID:    "b1",
+		DoneColumn: "done",
+		CommitURL:  "https://example.com/commit/%s",
+		State:      state,
+	}
+}
+
+func TestLinkIsIdempotent(t *testing.T) {
+	repo := newRepo(t,
+		"Start the login page glo#card1",
+		"Fix the tests\n\nfixes glo#card1, see glo#card2",
+	)
+	commits, err := repo.Log()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Subject() != "Start the login page glo#card1" || commits[1].Author != "Ada" {
+		t.Fatalf("got commits %+v", commits)
+	}
+
+	path, err := DefaultStatePath(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(".git", "glo", "links.json"); !strings.HasSuffix(path, want) {
+		t.Errorf("got state path %s, want it to end with %s", path, want)
+	}
+
+	fake := &fakeBoard{comments: map[string][]string{}, moves: map[string]string{}}
+
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links, err := newLinker(t, fake, state).Link(commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 3 {
+		t.Fatalf("got %d links, want 3", len(links))
+	}
+	for _, link := range links {
+		if !link.Commented || link.Skipped || link.Error != "" || link.Moved != link.Closes {
+			t.Errorf("got link %+v", link)
+		}
+	}
+	if len(fake.comments["card1"]) != 2 || len(fake.comments["card2"]) != 1 {
+		t.Errorf("got comments %v", fake.comments)
+	}
+	if len(fake.moves) != 1 || fake.moves["card1"] != "done" {
+		t.Errorf("got moves %v", fake.moves)
+	}
+	if comment := fake.comments["card2"][0]; !strings.Contains(comment, "https://example.com/commit/"+commits[1].Hash) ||
+		!strings.Contains(comment, "> fixes glo#card1, see glo#card2") {
+		t.Errorf("got comment %q", comment)
+	}
+
+	// a second run reloads the state and makes no requests
+	state, err = LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Links) != 3 {
+		t.Fatalf("saved %d links, want 3", len(state.Links))
+	}
+	links, err = newLinker(t, fake, state).Link(commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, link := range links {
+		if !link.Skipped || link.Commented || link.Moved {
+			t.Errorf("got link %+v, want it skipped", link)
+		}
+	}
+	if len(fake.comments["card1"]) != 2 || len(fake.comments["card2"]) != 1 || len(fake.moves) != 1 {
+		t.Errorf("second run made requests, comments %v moves %v", fake.comments, fake.moves)
+	}
+}
+
+func TestLinkRetriesFailures(t *testing.T) {
+	commits := []*Commit{{Hash: "abc", Message: "fixes glo#card1 glo#card2"}}
+	path := filepath.Join(t.TempDir(), "links.json")
+
+	fake := &fakeBoard{comments: map[string][]string{}, moves: map[string]string{}, failCard: "card1"}
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linker := newLinker(t, fake, state)
+	linker.DoneColumn = ""
+
+	links, err := linker.Link(commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 2 || links[0].Error == "" || links[0].Commented || !links[1].Commented {
+		t.Fatalf("got links %+v %+v", links[0], links[1])
+	}
+
+	// a done column set later moves the closed card without commenting again
+	fake.failCard = ""
+	state, err = LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	links, err = newLinker(t, fake, state).Link(commits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !links[0].Commented || !links[0].Moved || !links[1].Skipped {
+		t.Errorf("got links %+v %+v", links[0], links[1])
+	}
+	if len(fake.comments["card1"]) != 1 || len(fake.comments["card2"]) != 1 || fake.moves["card1"] != "done" {
+		t.Errorf("got comments %v moves %v", fake.comments, fake.moves)
+	}
+}
+
+func TestLinkDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "links.json")
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeBoard{comments: map[string][]string{}, moves: map[string]string{}}
+	linker := newLinker(t, fake, state)
+	linker.DryRun = true
+
+	links, err := linker.Link([]*Commit{{Hash: "abc", Message: "closes glo#card1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || !links[0].Commented || !links[0].Moved {
+		t.Errorf("got links %+v", links)
+	}
+	if len(fake.comments) != 0 || len(fake.moves) != 0 {
+		t.Error("dry run made changes")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("dry run saved the state err:%v", err)
+	}
+}
+
+func TestLoadStateErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "links.json")
+	if err := ioutil.WriteFile(path, []byte("{"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadState(path)
+	if err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Errorf("got err %v, want a parse error", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Package gitlink links git commits to the cards they reference.
+//
+// Commit messages reference cards as glo#<cardID>, a closing keyword
+// before the reference, such as "fixes glo#<cardID>", marks the card as
+// done. Each referenced card gets a comment describing the commit and
+// closed cards can be moved to a done column. Processed commits are
+// recorded so that linking is idempotent.
+package gitlink
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultKeywords the keywords which close the card they precede
+var DefaultKeywords = []string{
+	"close", "closes", "closed",
+	"fix", "fixes", "fixed",
+	"resolve", "resolves", "resolved",
+}
+
+// Reference a card referenced by a commit message
+type Reference struct {
+	CardID string `json:"card_id"`
+	Closes bool   `json:"closes"`
+}
+
+var reference = regexp.MustCompile(`(?i)(?:\b([a-z]+)\s*:?\s+)?\bglo#([a-z0-9_-]+)`)
+
+// ParseReferences the cards referenced by a commit message, in order of
+// first reference, a card closes when any of its references follows
+// one of the keywords
+func ParseReferences(message string, keywords []string) []*Reference {
+	if keywords == nil {
+		keywords = DefaultKeywords
+	}
+	closing := map[string]bool{}
+	for _, keyword := range keywords {
+		closing[strings.ToLower(keyword)] = true
+	}
+
+	var refs []*Reference
+	byCard := map[string]*Reference{}
+	for _, m := range reference.FindAllStringSubmatch(message, -1) {
+		ref, ok := byCard[m[2]]
+		if !ok {
+			ref = &Reference{CardID: m[2]}
+			byCard[m[2]] = ref
+			refs = append(refs, ref)
+		}
+		if closing[strings.ToLower(m[1])] {
+			ref.Closes = true
+		}
+	}
+
+	return refs
+}
+
+// Format a reference to a card, for use in commit messages
+func Format(cardID string) string {
+	return "glo#" + cardID
+}
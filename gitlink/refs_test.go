@@ -0,0 +1,114 @@
+package gitlink
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		keywords []string
+		want     []*Reference
+	}{
+		{
+			name:    "none",
+			message: "Refactor the parser #12",
+		},
+		{
+			name:    "plain reference",
+			message: "Add login glo#abc123",
+			want:    []*Reference{{CardID: "abc123"}},
+		},
+		{
+			name:    "closing keywords",
+			message: "Fixes glo#a1\n\ncloses: glo#b2, Resolved glo#c3",
+			want: []*Reference{
+				{CardID: "a1", Closes: true},
+				{CardID: "b2", Closes: true},
+				{CardID: "c3", Closes: true},
+			},
+		},
+		{
+			name:    "other verbs do not close",
+			message: "see glo#a1, hotfixes glo#b2 and glo#c3",
+			want: []*Reference{
+				{CardID: "a1"},
+				{CardID: "b2"},
+				{CardID: "c3"},
+			},
+		},
+		{
+			name:    "verb at the start of the message",
+			message: "fix glo#a1",
+			want:    []*Reference{{CardID: "a1", Closes: true}},
+		},
+		{
+			name:    "colon without a space",
+			message: "fix:glo#a1",
+			want:    []*Reference{{CardID: "a1"}},
+		},
+		{
+			name:    "only the first keyword of a list closes",
+			message: "fixes glo#a1 glo#b2",
+			want: []*Reference{
+				{CardID: "a1", Closes: true},
+				{CardID: "b2"},
+			},
+		},
+		{
+			name:    "repeated references close once any closes",
+			message: "Start glo#a1\n\nfixes glo#a1",
+			want:    []*Reference{{CardID: "a1", Closes: true}},
+		},
+		{
+			name:    "reference must start a word",
+			message: "xglo#a1 Glo#B2 (glo#c3)",
+			want: []*Reference{
+				{CardID: "B2"},
+				{CardID: "c3"},
+			},
+		},
+		{
+			name:    "ids stop at punctuation",
+			message: "fixes glo#a1-b_2.",
+			want:    []*Reference{{CardID: "a1-b_2", Closes: true}},
+		},
+		{
+			name:     "custom keywords",
+			message:  "Completes glo#a1, fixes glo#b2",
+			keywords: []string{"COMPLETES"},
+			want: []*Reference{
+				{CardID: "a1", Closes: true},
+				{CardID: "b2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := ParseReferences(test.message, test.keywords)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", refs(got), refs(test.want))
+			}
+		})
+	}
+}
+
+// refs formats references for failure messages
+func refs(list []*Reference) (out []Reference) {
+	for _, ref := range list {
+		out = append(out, *ref)
+	}
+
+	return
+}
+
+func TestFormat(t *testing.T) {
+	id := "5d1e8d6c3b4a2f0012345678"
+	got := ParseReferences("fixes "+Format(id), nil)
+	if len(got) != 1 || got[0].CardID != id || !got[0].Closes {
+		t.Errorf("got %v", refs(got))
+	}
+}
@@ -0,0 +1,97 @@
+package gitlink
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LinkRecord what has been done for a commit's reference to a card
+type LinkRecord struct {
+	Commented *time.Time `json:"commented,omitempty"`
+	Moved     *time.Time `json:"moved,omitempty"`
+}
+
+// State the references already linked, persisted as JSON
+type State struct {
+	path string
+
+	// Links records by "<commit hash> <card ID>"
+	Links map[string]*LinkRecord `json:"links"`
+}
+
+// DefaultStatePath the state file of a repository, kept in its git directory
+func DefaultStatePath(repo *Repository) (path string, err error) {
+	dir, err := repo.GitDir()
+	if err != nil {
+		return
+	}
+
+	return filepath.Join(dir, "glo", "links.json"), nil
+}
+
+// LoadState reads a state file, a missing file is an empty state
+func LoadState(path string) (state *State, err error) {
+	state = &State{
+		path:  path,
+		Links: map[string]*LinkRecord{},
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		err = fmt.Errorf("failed to parse %s err:%s", path, err)
+		return
+	}
+	if state.Links == nil {
+		state.Links = map[string]*LinkRecord{}
+	}
+
+	return
+}
+
+// Record the record of a commit's reference to a card, created if missing
+func (s *State) Record(hash, cardID string) *LinkRecord {
+	key := hash + " " + cardID
+	record, ok := s.Links[key]
+	if !ok {
+		record = &LinkRecord{}
+		s.Links[key] = record
+	}
+
+	return record
+}
+
+// Save writes the state file, replacing it atomically
+func (s *State) Save() (err error) {
+	if s.path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return
+	}
+	err = os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return
+	}
+
+	tmp := s.path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp, s.path)
+}
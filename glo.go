@@ -1,19 +1,85 @@
 package glo
 
-import "net/http"
+import (
+	"context"
+	"net/http"
+)
 
 // Glo API object
 type Glo struct {
-	token   string
-	client  *http.Client
-	BaseURI string
+	tokenSource TokenSource
+	client      *http.Client
+	BaseURI     string
+
+	// CoalesceRequests when enabled, identical concurrent GET
+	// requests share a single in-flight HTTP request
+	CoalesceRequests bool
+
+	// MaxRetries the number of times a rate limited
+	// request is retried before giving up
+	MaxRetries int
+
+	// Logger when set, receives a record of every API request
+	Logger Logger
+
+	// LogBodies when enabled, JSON request and response
+	// bodies are logged at debug level
+	LogBodies bool
+
+	// Middleware wraps every API request, the first
+	// middleware is the outermost
+	Middleware []Middleware
+
+	flight *flightGroup
+	ctx    context.Context
+}
+
+// TokenSource supplies the access token used to
+// authorise each request, allowing tokens to be
+// refreshed or rotated without rebuilding the client
+type TokenSource interface {
+	Token() (token string, err error)
+}
+
+// StaticTokenSource a TokenSource which always returns the same token
+type StaticTokenSource string
+
+// Token returns the static token
+func (s StaticTokenSource) Token() (string, error) {
+	return string(s), nil
 }
 
 // NewClient Glo API Client
 func NewClient(token string) *Glo {
+	return NewClientWithTokenSource(StaticTokenSource(token))
+}
+
+// NewClientWithTokenSource Glo API Client which consults
+// the token source for every request
+func NewClientWithTokenSource(tokenSource TokenSource) *Glo {
 	return &Glo{
-		client:  &http.Client{},
-		token:   token,
-		BaseURI: "https://gloapi.gitkraken.com/v1/glo",
+		client:      &http.Client{},
+		tokenSource: tokenSource,
+		flight:      &flightGroup{},
+		BaseURI:     "https://gloapi.gitkraken.com/v1/glo",
+	}
+}
+
+// WithContext returns a copy of the client whose requests are made with
+// ctx, cancelling ctx cancels in-flight requests and retries, and
+// middleware receives ctx in Request.Context
+func (a *Glo) WithContext(ctx context.Context) *Glo {
+	c := *a
+	c.ctx = ctx
+
+	return &c
+}
+
+// context the context requests are made with
+func (a *Glo) context() context.Context {
+	if a.ctx == nil {
+		return context.Background()
 	}
+
+	return a.ctx
 }
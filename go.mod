@@ -1 +1,5 @@
 module github.com/jackmcguire1/go-glo
+
+go 1.21
+
+require gopkg.in/yaml.v3 v3.0.1
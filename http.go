@@ -2,11 +2,15 @@ package glo
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strconv"
+	"time"
 )
 
 func (a *Glo) multiPartReq(
@@ -45,12 +49,146 @@ func (a *Glo) do(
 	header http.Header,
 	err error,
 ) {
-	req, err := http.NewRequest(method, url, r)
+	if a.tokenSource == nil {
+		err = fmt.Errorf("no token source configured")
+		return
+	}
+	token, err := a.tokenSource.Token()
+	if err != nil {
+		err = fmt.Errorf("failed to retrieve token err:%s", err)
+		return
+	}
+
+	// coalesced requests share a context which outlives any one caller,
+	// it carries the values of the first caller's context
+	if a.CoalesceRequests && method == http.MethodGet {
+		key := fmt.Sprintf("%s %s?%s %s", method, url, q.Encode(), token)
+		return a.flight.do(a.context(), key, func(ctx context.Context) ([]byte, http.Header, error) {
+			return a.WithContext(ctx).call(method, url, r, q, contentType, token)
+		})
+	}
+
+	return a.call(method, url, r, q, contentType, token)
+}
+
+// call sends a request through the middleware chain
+func (a *Glo) call(
+	method string,
+	url string,
+	r io.Reader,
+	q url.Values,
+	contentType string,
+	token string,
+) (
+	data []byte,
+	header http.Header,
+	err error,
+) {
+	req := &Request{
+		Context:  a.context(),
+		Method:   method,
+		URL:      url,
+		Endpoint: endpoint(a.BaseURI, url),
+		Query:    q,
+	}
+
+	handler := func(req *Request) (*Response, error) {
+		return a.send(req.Context, req.Method, req.URL, r, req.Query, contentType, token)
+	}
+	for i := len(a.Middleware) - 1; i >= 0; i-- {
+		handler = a.Middleware[i](handler)
+	}
+
+	resp, err := handler(req)
+	if resp != nil {
+		data = resp.Data
+		header = resp.Header
+	}
+
+	return
+}
+
+func (a *Glo) send(
+	ctx context.Context,
+	method string,
+	url string,
+	r io.Reader,
+	q url.Values,
+	contentType string,
+	token string,
+) (
+	resp *Response,
+	err error,
+) {
+	resp = &Response{}
+	rec := &requestRecord{
+		ctx:    ctx,
+		method: method,
+		url:    url,
+		query:  q,
+		token:  token,
+		start:  time.Now(),
+	}
+
+	if a.logBodies(ctx) && contentType == "application/json" && r != nil {
+		rec.reqBody, err = ioutil.ReadAll(r)
+		if err != nil {
+			return
+		}
+		r = bytes.NewReader(rec.reqBody)
+	}
+
+	defer func() {
+		a.logRequest(rec, resp, err)
+	}()
+
+	for {
+		resp.Data, resp.Header, resp.StatusCode, err = a.roundTrip(ctx, method, url, r, q, contentType, token)
+		if !errors.Is(err, ErrRateLimited) || resp.Retries >= a.MaxRetries {
+			return
+		}
+
+		// only bodies which can be rewound are safe to resend
+		if r != nil {
+			seeker, ok := r.(io.Seeker)
+			if !ok {
+				return
+			}
+			if _, seekErr := seeker.Seek(0, io.SeekStart); seekErr != nil {
+				return
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+			return
+		case <-time.After(retryAfter(resp.Header)):
+		}
+		resp.Retries++
+	}
+}
+
+func (a *Glo) roundTrip(
+	ctx context.Context,
+	method string,
+	url string,
+	r io.Reader,
+	q url.Values,
+	contentType string,
+	token string,
+) (
+	data []byte,
+	header http.Header,
+	status int,
+	err error,
+) {
+	req, err := http.NewRequestWithContext(ctx, method, url, r)
 	if err != nil {
 		err = fmt.Errorf("failed to construct request err:%s", err)
 		return
 	}
-	err = setRequestHeaders(req, a.token, contentType)
+	err = setRequestHeaders(req, token, contentType)
 	if err != nil {
 		return
 	}
@@ -63,28 +201,36 @@ func (a *Glo) do(
 	defer resp.Body.Close()
 
 	header = resp.Header
+	status = resp.StatusCode
 
 	switch resp.StatusCode {
 	case http.StatusOK:
 		data, err = ioutil.ReadAll(resp.Body)
 	case http.StatusNoContent:
 	case http.StatusTooManyRequests:
-		err = fmt.Errorf(
-			"rate limit reached",
-		)
+		err = ErrRateLimited
 		return
 	default:
-		err = fmt.Errorf(
-			"unsupported response httpCode:%d status:%s ",
-			resp.StatusCode,
-			resp.Status,
-		)
+		err = &StatusError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
 		return
 	}
 
 	return
 }
 
+// retryAfter the delay requested by a rate limited response
+func retryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 1 {
+		return time.Second
+	}
+
+	return time.Duration(seconds) * time.Second
+}
+
 func setRequestHeaders(
 	req *http.Request,
 	token string,
@@ -0,0 +1,347 @@
+package csvimport
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/importer"
+)
+
+// Options contains information used to import a CSV file
+type Options struct {
+	// Mapping the CSV columns of each card field, defaults to DefaultMapping
+	Mapping *Mapping
+
+	// Members maps assignees, by username or email, to Glo user IDs
+	Members importer.MemberMap
+
+	// DefaultColumn the column of rows without one
+	DefaultColumn string
+
+	// BatchSize the number of cards created per request,
+	// defaults to and is limited by glo.MaxCardsBatch
+	BatchSize int
+
+	// DryRun validates the file and reports the columns and labels
+	// which would be created without making any changes
+	DryRun bool
+}
+
+// RowError describes a row which was not imported
+type RowError struct {
+	// Line the line the row starts on, the header is line 1
+	Line   int      `json:"line"`
+	Name   string   `json:"name,omitempty"`
+	Errors []string `json:"errors"`
+}
+
+// Report describes the outcome of an import
+type Report struct {
+	DryRun  bool   `json:"dry_run"`
+	BoardID string `json:"board_id"`
+
+	Rows           int      `json:"rows"`
+	Cards          int      `json:"cards"`
+	ColumnsCreated []string `json:"columns_created,omitempty"`
+	LabelsCreated  []string `json:"labels_created,omitempty"`
+
+	// UnmappedAssignees assignees without a Glo user,
+	// their cards are imported unassigned
+	UnmappedAssignees []string `json:"unmapped_assignees,omitempty"`
+
+	// Invalid rows which failed validation
+	Invalid []*RowError `json:"invalid,omitempty"`
+
+	// Failed rows whose batch could not be created
+	Failed []*RowError `json:"failed,omitempty"`
+}
+
+// WriteTo writes a summary of the report
+func (r *Report) WriteTo(w io.Writer) (n int64, err error) {
+	var b strings.Builder
+	verb := "imported"
+	if r.DryRun {
+		verb = "would import"
+	}
+
+	fmt.Fprintf(&b, "%s %d of %d rows into board %s\n", verb, r.Cards, r.Rows, r.BoardID)
+	if len(r.ColumnsCreated) > 0 {
+		fmt.Fprintf(&b, "new columns: %s\n", strings.Join(r.ColumnsCreated, ", "))
+	}
+	if len(r.LabelsCreated) > 0 {
+		fmt.Fprintf(&b, "new labels: %s\n", strings.Join(r.LabelsCreated, ", "))
+	}
+	if len(r.UnmappedAssignees) > 0 {
+		fmt.Fprintf(&b, "unmapped assignees: %s\n", strings.Join(r.UnmappedAssignees, ", "))
+	}
+	for _, row := range r.Invalid {
+		fmt.Fprintf(&b, "invalid line %d: %s\n", row.Line, strings.Join(row.Errors, "; "))
+	}
+	for _, row := range r.Failed {
+		fmt.Fprintf(&b, "failed line %d: %s\n", row.Line, strings.Join(row.Errors, "; "))
+	}
+
+	written, err := io.WriteString(w, b.String())
+
+	return int64(written), err
+}
+
+// row a validated CSV row
+type row struct {
+	line     int
+	name     string
+	column   string
+	labels   []string
+	assignee string
+	dueDate  string
+	text     string
+}
+
+// Import creates a card on the board for each valid row of a CSV file
+func Import(
+	client *glo.Glo,
+	boardID string,
+	r io.Reader,
+	opts *Options,
+) (
+	report *Report,
+	err error,
+) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	mapping := opts.Mapping
+	if mapping == nil {
+		mapping = &DefaultMapping
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 || batchSize > glo.MaxCardsBatch {
+		batchSize = glo.MaxCardsBatch
+	}
+
+	report = &Report{
+		DryRun:  opts.DryRun,
+		BoardID: boardID,
+	}
+
+	rows, err := readRows(r, mapping, opts, report)
+	if err != nil {
+		return
+	}
+
+	board, err := importer.OpenBoard(client, boardID)
+	if err != nil {
+		return
+	}
+
+	inputs, err := resolve(board, rows, opts, report)
+	if err != nil {
+		return
+	}
+	if opts.DryRun {
+		report.Cards = len(rows)
+		return
+	}
+
+	for start := 0; start < len(inputs); start += batchSize {
+		end := start + batchSize
+		if end > len(inputs) {
+			end = len(inputs)
+		}
+
+		cards, batchErr := client.CreateCardsBatch(boardID, &glo.CardsBatchInput{
+			Cards: inputs[start:end],
+		})
+		if batchErr != nil {
+			for _, row := range rows[start:end] {
+				report.Failed = append(report.Failed, &RowError{
+					Line:   row.line,
+					Name:   row.name,
+					Errors: []string{batchErr.Error()},
+				})
+			}
+			continue
+		}
+		report.Cards += len(cards)
+	}
+
+	return
+}
+
+// readRows reads and validates the rows of a CSV file
+func readRows(
+	r io.Reader,
+	mapping *Mapping,
+	opts *Options,
+	report *Report,
+) (
+	rows []*row,
+	err error,
+) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	fields, err := reader.Read()
+	if err != nil {
+		err = fmt.Errorf("failed to read CSV header err:%s", err)
+		return
+	}
+	h, err := mapping.resolve(fields)
+	if err != nil {
+		return
+	}
+	if len(h.column) == 0 && opts.DefaultColumn == "" {
+		err = fmt.Errorf("CSV header has no %q column and no default column was set", mapping.Column)
+		return
+	}
+
+	unmapped := map[string]bool{}
+	for {
+		record, readErr := reader.Read()
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			err = fmt.Errorf("failed to read CSV err:%s", readErr)
+			return
+		}
+		report.Rows++
+		line, _ := reader.FieldPos(0)
+
+		rw := &row{
+			line:   line,
+			name:   first(record, h.name),
+			column: first(record, h.column),
+			text:   first(record, h.description),
+		}
+		var problems []string
+
+		if rw.name == "" {
+			problems = append(problems, "missing name")
+		}
+		if rw.column == "" {
+			rw.column = opts.DefaultColumn
+		}
+		if rw.column == "" {
+			problems = append(problems, "missing column")
+		}
+
+		if due := first(record, h.dueDate); due != "" {
+			rw.dueDate, err = mapping.parseDate(due)
+			if err != nil {
+				problems = append(problems, err.Error())
+				err = nil
+			}
+		}
+
+		if assignee := first(record, h.assignee); assignee != "" {
+			if id, ok := opts.Members.Lookup(assignee); ok {
+				rw.assignee = id
+			} else if !unmapped[assignee] {
+				unmapped[assignee] = true
+				report.UnmappedAssignees = append(report.UnmappedAssignees, assignee)
+			}
+		}
+
+		seen := map[string]bool{}
+		for _, i := range h.labels {
+			if i >= len(record) {
+				continue
+			}
+			for _, label := range strings.Split(record[i], mapping.labelSeparator()) {
+				label = strings.TrimSpace(label)
+				if label != "" && !seen[strings.ToLower(label)] {
+					seen[strings.ToLower(label)] = true
+					rw.labels = append(rw.labels, label)
+				}
+			}
+		}
+
+		if len(problems) > 0 {
+			report.Invalid = append(report.Invalid, &RowError{
+				Line:   line,
+				Name:   rw.name,
+				Errors: problems,
+			})
+			continue
+		}
+		rows = append(rows, rw)
+	}
+
+	return
+}
+
+// resolve creates the columns and labels the rows need, with dry runs
+// they are only reported, and builds the input of each card
+func resolve(
+	board *importer.Board,
+	rows []*row,
+	opts *Options,
+	report *Report,
+) (
+	inputs []*glo.CardsInput,
+	err error,
+) {
+	planned := map[string]bool{}
+	positions := map[string]int{}
+
+	for _, rw := range rows {
+		for _, label := range rw.labels {
+			if board.HasLabel(label) || planned["label:"+strings.ToLower(label)] {
+				continue
+			}
+			planned["label:"+strings.ToLower(label)] = true
+			report.LabelsCreated = append(report.LabelsCreated, label)
+		}
+		if !board.HasColumn(rw.column) && !planned["column:"+strings.ToLower(rw.column)] {
+			planned["column:"+strings.ToLower(rw.column)] = true
+			report.ColumnsCreated = append(report.ColumnsCreated, rw.column)
+		}
+		if opts.DryRun {
+			continue
+		}
+
+		col, _, colErr := board.Column(rw.column)
+		if colErr != nil {
+			return nil, colErr
+		}
+		// cards are added after those already in the column
+		if _, ok := positions[col.ID]; !ok {
+			count, countErr := board.CardCount(col.ID)
+			if countErr != nil {
+				return nil, countErr
+			}
+			positions[col.ID] = count
+		}
+
+		input := &glo.CardsInput{
+			Name:     rw.name,
+			Position: positions[col.ID],
+			ColumnID: col.ID,
+			DueDate:  rw.dueDate,
+		}
+		positions[col.ID]++
+
+		if rw.text != "" {
+			input.Description = &glo.MinimizedDescription{Text: rw.text}
+		}
+		if rw.assignee != "" {
+			input.Assignees = []*glo.PartialUser{{ID: rw.assignee}}
+		}
+		for _, name := range rw.labels {
+			label, _, labelErr := board.Label(name, importer.DefaultLabelColor)
+			if labelErr != nil {
+				return nil, labelErr
+			}
+			input.Labels = append(input.Labels, &glo.PartialLabel{ID: label.ID})
+		}
+
+		inputs = append(inputs, input)
+	}
+
+	return
+}
@@ -0,0 +1,352 @@
+package csvimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/importer"
+)
+
+// fakeAPI serves a board with a Todo column holding two cards and a
+// bug label, recording the columns, labels and card batches created
+type fakeAPI struct {
+	mu       sync.Mutex
+	nextID   int
+	board    *glo.Board
+	existing []*glo.Card
+	batches  [][]*glo.CardsInput
+
+	// failBatch the batch, starting at 1, which fails
+	failBatch int
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		board: &glo.Board{
+			ID:      "b1",
+			Name:    "Team Alpha",
+			Columns: []*glo.Column{{ID: "todo", Name: "Todo", Position: 0}},
+			Labels:  []*glo.Label{{ID: "bug", Name: "bug"}},
+		},
+		existing: []*glo.Card{
+			{ID: "k1", ColumnID: "todo"},
+			{ID: "k2", ColumnID: "todo"},
+		},
+	}
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var v interface{}
+	switch {
+	case r.Method == http.MethodGet && len(parts) == 2:
+		v = f.board
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "cards":
+		w.Header().Set("has-more", "false")
+		v = f.existing
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "columns":
+		input := &glo.ColumnInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		f.nextID++
+		col := &glo.Column{ID: fmt.Sprintf("col%d", f.nextID), Name: input.Name, Position: input.Position}
+		f.board.Columns = append(f.board.Columns, col)
+		v = col
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[2] == "labels":
+		input := &glo.LabelInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		f.nextID++
+		label := &glo.Label{ID: fmt.Sprintf("label%d", f.nextID), Name: input.Name}
+		f.board.Labels = append(f.board.Labels, label)
+		v = label
+	case r.Method == http.MethodPost && len(parts) == 4 && parts[3] == "batch":
+		input := &glo.CardsBatchInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		f.batches = append(f.batches, input.Cards)
+		if len(f.batches) == f.failBatch {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		resp := &glo.CardsResp{}
+		for _, card := range input.Cards {
+			f.nextID++
+			resp.Cards = append(resp.Cards, &glo.Card{ID: fmt.Sprintf("card%d", f.nextID), Name: card.Name})
+		}
+		v = resp
+	}
+	if v == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeAPI) cards() (cards []*glo.CardsInput) {
+	for _, batch := range f.batches {
+		cards = append(cards, batch...)
+	}
+
+	return
+}
+
+func (f *fakeAPI) column(name string) string {
+	for _, col := range f.board.Columns {
+		if col.Name == name {
+			return col.ID
+		}
+	}
+
+	return ""
+}
+
+func newClient(t *testing.T, fake *fakeAPI) *glo.Glo {
+	t.Helper()
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client := glo.NewClient("token")
+	client.BaseURI = srv.URL
+
+	return client
+}
+
+func TestImportDefaultMapping(t *testing.T) {
+	fake := newFakeAPI()
+	client := newClient(t, fake)
+
+	csv := "name,column,labels,assignee,due_date,description\n" +
+		"Fix login,Todo,\"bug, UI\",alice,2026-10-19,Login fails\n" +
+		"Write docs,Doing,ui,bob,,\n" +
+		"Ship it,todo,Bug,,,\n"
+
+	report, err := Import(client, "b1", strings.NewReader(csv), &Options{
+		Members: importer.MemberMap{"alice": "u1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Rows != 3 || report.Cards != 3 || len(report.Invalid) != 0 || len(report.Failed) != 0 {
+		t.Errorf("got report %+v", report)
+	}
+	if !reflect.DeepEqual(report.ColumnsCreated, []string{"Doing"}) {
+		t.Errorf("got columns created %v", report.ColumnsCreated)
+	}
+	if !reflect.DeepEqual(report.LabelsCreated, []string{"UI"}) {
+		t.Errorf("got labels created %v", report.LabelsCreated)
+	}
+	if !reflect.DeepEqual(report.UnmappedAssignees, []string{"bob"}) {
+		t.Errorf("got unmapped assignees %v", report.UnmappedAssignees)
+	}
+
+	cards := fake.cards()
+	if len(cards) != 3 {
+		t.Fatalf("created %d cards, want 3", len(cards))
+	}
+	doing := fake.column("Doing")
+	want := []struct {
+		name     string
+		column   string
+		position int
+		labels   int
+	}{
+		// cards are added after the two already in Todo
+		{"Fix login", "todo", 2, 2},
+		{"Write docs", doing, 0, 1},
+		{"Ship it", "todo", 3, 1},
+	}
+	for i, w := range want {
+		card := cards[i]
+		if card.Name != w.name || card.ColumnID != w.column || card.Position != w.position || len(card.Labels) != w.labels {
+			t.Errorf("card %d got %q in %s at %d with %d labels, want %+v",
+				i, card.Name, card.ColumnID, card.Position, len(card.Labels), w)
+		}
+	}
+
+	first := cards[0]
+	if first.DueDate != "2026-10-19T00:00:00Z" ||
+		first.Description == nil || first.Description.Text != "Login fails" ||
+		len(first.Assignees) != 1 || first.Assignees[0].ID != "u1" {
+		t.Errorf("got card %+v", first)
+	}
+	if cards[1].Assignees != nil {
+		t.Errorf("unmapped assignee was assigned %+v", cards[1].Assignees)
+	}
+}
+
+func TestImportJiraMapping(t *testing.T) {
+	fake := newFakeAPI()
+	client := newClient(t, fake)
+
+	csv := "Summary,Issue key,Status,Labels,Labels,Assignee,Due Date,Description\n" +
+		"Crash on start,APP-1,Todo,bug crash,urgent,alice,19/Oct/26 2:30 PM,Stack trace\n"
+
+	report, err := Import(client, "b1", strings.NewReader(csv), &Options{
+		Mapping: &JiraMapping,
+		Members: importer.MemberMap{"alice": "u1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Cards != 1 {
+		t.Fatalf("got report %+v", report)
+	}
+	if !reflect.DeepEqual(report.LabelsCreated, []string{"crash", "urgent"}) {
+		t.Errorf("got labels created %v", report.LabelsCreated)
+	}
+
+	card := fake.cards()[0]
+	if card.Name != "Crash on start" || card.ColumnID != "todo" || card.DueDate != "2026-10-19T14:30:00Z" ||
+		len(card.Labels) != 3 || card.Description.Text != "Stack trace" {
+		t.Errorf("got card %+v", card)
+	}
+}
+
+func TestImportInvalidRows(t *testing.T) {
+	fake := newFakeAPI()
+	client := newClient(t, fake)
+
+	csv := "name,column,due_date\n" +
+		"Valid,Todo,\n" +
+		",Todo,\n" +
+		"No column,,\n" +
+		"Bad date,Todo,someday\n" +
+		",,someday\n" +
+		"\"Multi\nline\",Todo,\n"
+
+	report, err := Import(client, "b1", strings.NewReader(csv), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Rows != 6 || report.Cards != 2 {
+		t.Errorf("got %d rows and %d cards, want 6 and 2", report.Rows, report.Cards)
+	}
+	want := []*RowError{
+		{Line: 3, Errors: []string{"missing name"}},
+		{Line: 4, Name: "No column", Errors: []string{"missing column"}},
+		{Line: 5, Name: "Bad date", Errors: []string{`invalid due date "someday"`}},
+		{Line: 6, Errors: []string{"missing name", "missing column", `invalid due date "someday"`}},
+	}
+	if !reflect.DeepEqual(report.Invalid, want) {
+		got, _ := json.Marshal(report.Invalid)
+		t.Errorf("got invalid rows %s", got)
+	}
+}
+
+func TestImportHeaderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+		opts *Options
+		err  string
+	}{
+		{"empty file", "", nil, "failed to read CSV header"},
+		{"no name column", "title,column\n", nil, `no "name" column`},
+		{"no column and no default", "name\nCard\n", nil, "no default column was set"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeAPI()
+			_, err := Import(newClient(t, fake), "b1", strings.NewReader(test.csv), test.opts)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got err %v, want %q", err, test.err)
+			}
+			if len(fake.batches) != 0 {
+				t.Errorf("created %d batches", len(fake.batches))
+			}
+		})
+	}
+}
+
+func TestImportBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		rows      int
+		batchSize int
+		failBatch int
+		sizes     []int
+		failed    int
+	}{
+		{name: "default batch size", rows: 250, sizes: []int{100, 100, 50}},
+		{name: "batch size above the maximum", rows: 201, batchSize: 500, sizes: []int{100, 100, 1}},
+		{name: "small batches", rows: 5, batchSize: 2, sizes: []int{2, 2, 1}},
+		{name: "exactly one batch", rows: 100, sizes: []int{100}},
+		{name: "failed batch", rows: 250, failBatch: 2, sizes: []int{100, 100, 50}, failed: 100},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeAPI()
+			fake.failBatch = test.failBatch
+			client := newClient(t, fake)
+
+			var b strings.Builder
+			b.WriteString("name\n")
+			for i := 0; i < test.rows; i++ {
+				fmt.Fprintf(&b, "card %d\n", i)
+			}
+
+			report, err := Import(client, "b1", strings.NewReader(b.String()), &Options{
+				DefaultColumn: "Todo",
+				BatchSize:     test.batchSize,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sizes []int
+			for _, batch := range fake.batches {
+				sizes = append(sizes, len(batch))
+			}
+			if !reflect.DeepEqual(sizes, test.sizes) {
+				t.Errorf("got batch sizes %v, want %v", sizes, test.sizes)
+			}
+			if report.Cards != test.rows-test.failed || len(report.Failed) != test.failed {
+				t.Errorf("got %d cards and %d failed rows", report.Cards, len(report.Failed))
+			}
+			if test.failed > 0 && report.Failed[0].Line != 102 {
+				t.Errorf("first failed row on line %d, want 102", report.Failed[0].Line)
+			}
+
+			// positions continue across batches after the existing cards
+			for i, card := range fake.cards() {
+				if card.Position != i+2 {
+					t.Fatalf("card %d at position %d, want %d", i, card.Position, i+2)
+				}
+			}
+		})
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	fake := newFakeAPI()
+	client := newClient(t, fake)
+
+	csv := "name,column,labels\nFirst,Doing,new\nSecond,Doing,bug\n"
+	report, err := Import(client, "b1", strings.NewReader(csv), &Options{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !report.DryRun || report.Cards != 2 ||
+		!reflect.DeepEqual(report.ColumnsCreated, []string{"Doing"}) ||
+		!reflect.DeepEqual(report.LabelsCreated, []string{"new"}) {
+		t.Errorf("got report %+v", report)
+	}
+	if len(fake.board.Columns) != 1 || len(fake.board.Labels) != 1 || len(fake.batches) != 0 {
+		t.Error("dry run changed the board")
+	}
+}
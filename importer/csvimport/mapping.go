@@ -0,0 +1,194 @@
+// Package csvimport imports cards from CSV files, such as Jira issue
+// exports, into an existing Glo board.
+//
+// A Mapping names the CSV columns holding each card field, columns and
+// labels missing from the board are created and cards are created in
+// batches. Rows which fail validation are skipped and reported.
+package csvimport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Mapping names the CSV columns holding each card field, header names
+// are case-insensitive and fields with an empty name are not imported
+type Mapping struct {
+	Name        string
+	Column      string
+	Labels      string
+	Assignee    string
+	DueDate     string
+	Description string
+
+	// LabelSeparator splits the labels of a row, defaults to a comma,
+	// labels spread over several columns with the same header are combined
+	LabelSeparator string
+
+	// DateLayouts the time layouts due dates are parsed with,
+	// defaults to RFC 3339 and ISO dates
+	DateLayouts []string
+}
+
+// DefaultMapping a mapping of lower case field names
+var DefaultMapping = Mapping{
+	Name:        "name",
+	Column:      "column",
+	Labels:      "labels",
+	Assignee:    "assignee",
+	DueDate:     "due_date",
+	Description: "description",
+}
+
+// JiraMapping a mapping of Jira's CSV issue export
+var JiraMapping = Mapping{
+	Name:           "Summary",
+	Column:         "Status",
+	Labels:         "Labels",
+	Assignee:       "Assignee",
+	DueDate:        "Due Date",
+	Description:    "Description",
+	LabelSeparator: " ",
+	DateLayouts: []string{
+		"02/Jan/06 3:04 PM",
+		"02/Jan/06",
+		"2006-01-02 15:04",
+		"2006-01-02",
+	},
+}
+
+var defaultDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// ParseMapping overrides fields of a mapping with a
+// comma separated list of field=header pairs
+//
+//	name=Title,column=State,due_date=
+func ParseMapping(base Mapping, spec string) (mapping Mapping, err error) {
+	mapping = base
+
+	for _, pair := range strings.Split(spec, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			err = fmt.Errorf("invalid mapping %q, expected field=header", pair)
+			return
+		}
+
+		field := mapping.field(strings.TrimSpace(parts[0]))
+		if field == nil {
+			err = fmt.Errorf(
+				"unknown mapping field %q, expected one of name, column, labels, assignee, due_date or description",
+				parts[0],
+			)
+			return
+		}
+		*field = strings.TrimSpace(parts[1])
+	}
+
+	return
+}
+
+func (m *Mapping) field(name string) *string {
+	switch strings.ToLower(name) {
+	case "name", "summary":
+		return &m.Name
+	case "column", "status":
+		return &m.Column
+	case "labels", "label":
+		return &m.Labels
+	case "assignee":
+		return &m.Assignee
+	case "due_date", "due", "duedate":
+		return &m.DueDate
+	case "description":
+		return &m.Description
+	}
+
+	return nil
+}
+
+func (m *Mapping) labelSeparator() string {
+	if m.LabelSeparator == "" {
+		return ","
+	}
+
+	return m.LabelSeparator
+}
+
+// parseDate parses a due date with the mapping's layouts
+func (m *Mapping) parseDate(value string) (date string, err error) {
+	layouts := m.DateLayouts
+	if len(layouts) == 0 {
+		layouts = defaultDateLayouts
+	}
+
+	for _, layout := range layouts {
+		t, parseErr := time.Parse(layout, value)
+		if parseErr == nil {
+			date = t.UTC().Format(time.RFC3339)
+			return
+		}
+	}
+	err = fmt.Errorf("invalid due date %q", value)
+
+	return
+}
+
+// header the indexes of each mapped field in a CSV header
+type header struct {
+	name        []int
+	column      []int
+	labels      []int
+	assignee    []int
+	dueDate     []int
+	description []int
+}
+
+func (m *Mapping) resolve(fields []string) (h *header, err error) {
+	indexes := map[string][]int{}
+	for i, field := range fields {
+		// Excel writes a byte order mark before the first header
+		field = strings.TrimPrefix(field, "\ufeff")
+		k := strings.ToLower(strings.TrimSpace(field))
+		indexes[k] = append(indexes[k], i)
+	}
+	lookup := func(name string) []int {
+		return indexes[strings.ToLower(strings.TrimSpace(name))]
+	}
+
+	h = &header{
+		name:        lookup(m.Name),
+		column:      lookup(m.Column),
+		labels:      lookup(m.Labels),
+		assignee:    lookup(m.Assignee),
+		dueDate:     lookup(m.DueDate),
+		description: lookup(m.Description),
+	}
+	if len(h.name) == 0 {
+		err = fmt.Errorf("CSV header has no %q column for card names", m.Name)
+	}
+
+	return
+}
+
+// first the first non-empty value of the indexed fields
+func first(record []string, indexes []int) string {
+	for _, i := range indexes {
+		if i < len(record) {
+			if value := strings.TrimSpace(record[i]); value != "" {
+				return value
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,107 @@
+package csvimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMapping(t *testing.T) {
+	tests := []struct {
+		name string
+		base Mapping
+		spec string
+		want Mapping
+		err  string
+	}{
+		{
+			name: "empty",
+			base: DefaultMapping,
+			want: DefaultMapping,
+		},
+		{
+			name: "overrides and aliases",
+			base: DefaultMapping,
+			spec: " name = Title , Status=State,due_date=",
+			want: Mapping{
+				Name:        "Title",
+				Column:      "State",
+				Labels:      "labels",
+				Assignee:    "assignee",
+				Description: "description",
+			},
+		},
+		{
+			name: "missing separator",
+			base: DefaultMapping,
+			spec: "name",
+			err:  `invalid mapping "name", expected field=header`,
+		},
+		{
+			name: "unknown field",
+			base: DefaultMapping,
+			spec: "colour=Red",
+			err:  `unknown mapping field "colour"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseMapping(test.base, test.spec)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Errorf("got err %v, want %q", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		mapping Mapping
+		value   string
+		want    string
+		err     bool
+	}{
+		{DefaultMapping, "2026-10-19", "2026-10-19T00:00:00Z", false},
+		{DefaultMapping, "2026-10-19 14:30", "2026-10-19T14:30:00Z", false},
+		{DefaultMapping, "2026-10-19T14:30:00+02:00", "2026-10-19T12:30:00Z", false},
+		{DefaultMapping, "19/Oct/26", "", true},
+		{JiraMapping, "19/Oct/26 2:30 PM", "2026-10-19T14:30:00Z", false},
+		{JiraMapping, "19/Oct/26", "2026-10-19T00:00:00Z", false},
+		{JiraMapping, "tomorrow", "", true},
+	}
+
+	for _, test := range tests {
+		got, err := test.mapping.parseDate(test.value)
+		if (err != nil) != test.err || got != test.want {
+			t.Errorf("parseDate(%q) got %q err:%v, want %q", test.value, got, err, test.want)
+		}
+	}
+}
+
+func TestResolveHeader(t *testing.T) {
+	h, err := DefaultMapping.resolve([]string{"\ufeffName", " COLUMN ", "labels", "Labels", "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(h.name, []int{0}) ||
+		!reflect.DeepEqual(h.column, []int{1}) ||
+		!reflect.DeepEqual(h.labels, []int{2, 3}) ||
+		h.assignee != nil {
+		t.Errorf("got header %+v", h)
+	}
+
+	_, err = JiraMapping.resolve([]string{"name", "Status"})
+	if err == nil || !strings.Contains(err.Error(), `no "Summary" column`) {
+		t.Errorf("got err %v, want a missing name column error", err)
+	}
+}
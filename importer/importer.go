@@ -0,0 +1,202 @@
+// Package importer contains helpers shared by the importers which
+// create Glo boards from other tools' exports.
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Board a target board whose columns and labels are resolved
+// by name, creating any which are missing
+type Board struct {
+	client *glo.Glo
+	board  *glo.Board
+
+	columns   map[string]*glo.Column
+	labels    map[string]*glo.Label
+	positions int
+
+	// cardCounts the active cards of each column, loaded on first use
+	cardCounts map[string]int
+}
+
+// NewBoard wraps a board, its columns and labels must be populated
+func NewBoard(client *glo.Glo, board *glo.Board) *Board {
+	b := &Board{
+		client:  client,
+		board:   board,
+		columns: map[string]*glo.Column{},
+		labels:  map[string]*glo.Label{},
+	}
+
+	for _, col := range board.Columns {
+		b.columns[key(col.Name)] = col
+		if col.Position >= b.positions {
+			b.positions = col.Position + 1
+		}
+	}
+	for _, label := range board.Labels {
+		b.labels[key(label.Name)] = label
+	}
+
+	return b
+}
+
+// OpenBoard fetches an existing board
+func OpenBoard(client *glo.Glo, boardID string) (b *Board, err error) {
+	board, err := client.GetBoard(
+		boardID,
+		glo.Fields(glo.BoardFieldName, glo.BoardFieldColumns, glo.BoardFieldLabels),
+	)
+	if err != nil {
+		return
+	}
+	board.ID = boardID
+	b = NewBoard(client, board)
+
+	return
+}
+
+// ID the ID of the board
+func (b *Board) ID() string {
+	return b.board.ID
+}
+
+// HasColumn reports whether the board has a column, names are case-insensitive
+func (b *Board) HasColumn(name string) bool {
+	_, ok := b.columns[key(name)]
+	return ok
+}
+
+// HasLabel reports whether the board has a label, names are case-insensitive
+func (b *Board) HasLabel(name string) bool {
+	_, ok := b.labels[key(name)]
+	return ok
+}
+
+// Column resolves a column by name, creating it after
+// the existing columns when it does not exist
+func (b *Board) Column(name string) (col *glo.Column, created bool, err error) {
+	if col, ok := b.columns[key(name)]; ok {
+		return col, false, nil
+	}
+
+	col, err = b.CreateColumn(name)
+	if err != nil {
+		return
+	}
+	created = true
+
+	return
+}
+
+// CreateColumn creates a column after the existing columns, even
+// when the board already has a column with the same name, Column
+// resolves the name to the first column created with it
+func (b *Board) CreateColumn(name string) (col *glo.Column, err error) {
+	col, err = b.client.CreateColumn(b.board.ID, &glo.ColumnInput{
+		Name:     name,
+		Position: b.positions,
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create column %q err:%s", name, err)
+		return
+	}
+	b.positions++
+	if _, ok := b.columns[key(name)]; !ok {
+		b.columns[key(name)] = col
+	}
+
+	return
+}
+
+// Label resolves a label by name, creating it with
+// the colour when it does not exist
+func (b *Board) Label(name string, color glo.Color) (label *glo.Label, created bool, err error) {
+	if label, ok := b.labels[key(name)]; ok {
+		return label, false, nil
+	}
+
+	label, err = b.client.CreateLabel(b.board.ID, &glo.LabelInput{Name: name, Color: color})
+	if err != nil {
+		err = fmt.Errorf("failed to create label %q err:%s", name, err)
+		return
+	}
+	b.labels[key(name)] = label
+	created = true
+
+	return
+}
+
+// CardCount the number of active cards in a column, the board's
+// cards are fetched the first time a count is requested
+func (b *Board) CardCount(columnID string) (n int, err error) {
+	if b.cardCounts == nil {
+		cards, cardsErr := b.client.AllCards(b.board.ID, false, glo.Fields(glo.CardFieldColumnID))
+		if cardsErr != nil {
+			err = fmt.Errorf("failed to fetch the cards of board:%s err:%s", b.board.ID, cardsErr)
+			return
+		}
+		b.cardCounts = map[string]int{}
+		for _, card := range cards {
+			b.cardCounts[card.ColumnID]++
+		}
+	}
+	n = b.cardCounts[columnID]
+
+	return
+}
+
+func key(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// DefaultLabelColor the colour of labels created without one
+var DefaultLabelColor = glo.Color{R: 128, G: 128, B: 128, A: 1}
+
+// MemberMap maps users of another tool, by ID, username or
+// email, to Glo user IDs
+type MemberMap map[string]string
+
+// LoadMemberMap reads a JSON object mapping users to Glo user IDs
+//
+//	{"alice": "<glo user id>", "bob@example.com": "<glo user id>"}
+func LoadMemberMap(path string) (members MemberMap, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	members = MemberMap{}
+	err = json.Unmarshal(data, &members)
+	if err != nil {
+		err = fmt.Errorf("%s: %s", path, err)
+	}
+
+	return
+}
+
+// Lookup resolves the first of the keys which is mapped,
+// keys are case-insensitive
+func (m MemberMap) Lookup(keys ...string) (id string, ok bool) {
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		if id, ok = m[k]; ok {
+			return
+		}
+		for mapped, mappedID := range m {
+			if strings.EqualFold(mapped, k) {
+				return mappedID, true
+			}
+		}
+	}
+
+	return
+}
@@ -0,0 +1,141 @@
+// Package trello imports Trello board JSON exports into Glo.
+//
+// Lists become columns and cards keep their descriptions, due dates,
+// labels and members, checklists are appended to descriptions as
+// Markdown task lists and comments and attachments are recreated.
+package trello
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Export a Trello board JSON export
+type Export struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	Desc       string       `json:"desc"`
+	Closed     bool         `json:"closed"`
+	Labels     []*Label     `json:"labels"`
+	Lists      []*List      `json:"lists"`
+	Cards      []*Card      `json:"cards"`
+	Checklists []*Checklist `json:"checklists"`
+	Members    []*Member    `json:"members"`
+	Actions    []*Action    `json:"actions"`
+}
+
+// Label a Trello label
+type Label struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+// List a Trello list
+type List struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+// Card a Trello card
+type Card struct {
+	ID           string        `json:"id"`
+	Name         string        `json:"name"`
+	Desc         string        `json:"desc"`
+	Closed       bool          `json:"closed"`
+	IDList       string        `json:"idList"`
+	IDLabels     []string      `json:"idLabels"`
+	IDMembers    []string      `json:"idMembers"`
+	IDChecklists []string      `json:"idChecklists"`
+	Due          string        `json:"due"`
+	Pos          float64       `json:"pos"`
+	ShortURL     string        `json:"shortUrl"`
+	Attachments  []*Attachment `json:"attachments"`
+}
+
+// Attachment a file uploaded to, or link attached to, a Trello card
+type Attachment struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	MimeType string `json:"mimeType"`
+	IsUpload bool   `json:"isUpload"`
+	Date     string `json:"date"`
+}
+
+// Checklist a Trello checklist
+type Checklist struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	IDCard     string       `json:"idCard"`
+	Pos        float64      `json:"pos"`
+	CheckItems []*CheckItem `json:"checkItems"`
+}
+
+// CheckItem an item of a Trello checklist
+type CheckItem struct {
+	ID    string  `json:"id"`
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+// Member a Trello board member
+type Member struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+	FullName string `json:"fullName"`
+}
+
+// Action a Trello board action, only comments are imported
+type Action struct {
+	ID            string     `json:"id"`
+	Type          string     `json:"type"`
+	Date          string     `json:"date"`
+	MemberCreator *Member    `json:"memberCreator"`
+	Data          ActionData `json:"data"`
+}
+
+// ActionData the data of a Trello action
+type ActionData struct {
+	Text string `json:"text"`
+	Card struct {
+		ID string `json:"id"`
+	} `json:"card"`
+}
+
+const actionCommentCard = "commentCard"
+
+// Parse reads a Trello board JSON export
+func Parse(r io.Reader) (export *Export, err error) {
+	export = &Export{}
+	err = json.NewDecoder(r).Decode(export)
+	if err != nil {
+		err = fmt.Errorf("failed to parse trello export err:%s", err)
+		return
+	}
+	if export.Name == "" {
+		err = fmt.Errorf("trello export has no board name")
+	}
+
+	return
+}
+
+// colors approximations of Trello's named label colours
+var colors = map[string]glo.Color{
+	"green":  {R: 97, G: 189, B: 79, A: 1},
+	"yellow": {R: 242, G: 214, B: 0, A: 1},
+	"orange": {R: 255, G: 159, B: 26, A: 1},
+	"red":    {R: 235, G: 90, B: 70, A: 1},
+	"purple": {R: 195, G: 119, B: 224, A: 1},
+	"blue":   {R: 0, G: 121, B: 191, A: 1},
+	"sky":    {R: 0, G: 194, B: 224, A: 1},
+	"lime":   {R: 81, G: 232, B: 152, A: 1},
+	"pink":   {R: 255, G: 120, B: 203, A: 1},
+	"black":  {R: 52, G: 69, B: 99, A: 1},
+}
@@ -0,0 +1,477 @@
+package trello
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+	"github.com/jackmcguire1/go-glo/importer"
+)
+
+// Options contains information used to import a Trello export
+type Options struct {
+	// BoardName the name of the created board, defaults to the Trello board's name
+	BoardName string
+
+	// DryRun reports what would be imported without making any changes
+	DryRun bool
+
+	// Members maps Trello members, by ID or username, to Glo user IDs
+	Members importer.MemberMap
+
+	// IncludeClosed imports archived lists and cards
+	IncludeClosed bool
+
+	// FetchAttachment downloads uploaded attachments, uploads which cannot
+	// be fetched are linked from a comment instead, defaults to an
+	// unauthenticated GET of the attachment URL
+	FetchAttachment func(attachment *Attachment) (io.ReadCloser, error)
+}
+
+// Report describes the outcome of an import
+type Report struct {
+	DryRun  bool   `json:"dry_run"`
+	BoardID string `json:"board_id,omitempty"`
+	Board   string `json:"board"`
+
+	Columns     int `json:"columns"`
+	Labels      int `json:"labels"`
+	Cards       int `json:"cards"`
+	Checklists  int `json:"checklists"`
+	Comments    int `json:"comments"`
+	Attachments int `json:"attachments"`
+	Links       int `json:"links"`
+
+	// UnmappedMembers Trello members without a Glo user,
+	// cards assigned to them are imported unassigned
+	UnmappedMembers []string `json:"unmapped_members,omitempty"`
+	Warnings        []string `json:"warnings,omitempty"`
+}
+
+func (r *Report) warn(format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// WriteTo writes a summary of the report
+func (r *Report) WriteTo(w io.Writer) (n int64, err error) {
+	var b strings.Builder
+	verb := "imported"
+	if r.DryRun {
+		verb = "would import"
+	}
+
+	fmt.Fprintf(&b, "%s board %q", verb, r.Board)
+	if r.BoardID != "" {
+		fmt.Fprintf(&b, " (%s)", r.BoardID)
+	}
+	fmt.Fprintf(
+		&b,
+		"\n  %d columns\n  %d labels\n  %d cards\n  %d checklists\n  %d comments\n  %d attachments\n  %d links\n",
+		r.Columns,
+		r.Labels,
+		r.Cards,
+		r.Checklists,
+		r.Comments,
+		r.Attachments,
+		r.Links,
+	)
+	if len(r.UnmappedMembers) > 0 {
+		fmt.Fprintf(&b, "unmapped members: %s\n", strings.Join(r.UnmappedMembers, ", "))
+	}
+	for _, warning := range r.Warnings {
+		fmt.Fprintf(&b, "warning: %s\n", warning)
+	}
+
+	written, err := io.WriteString(w, b.String())
+
+	return int64(written), err
+}
+
+// importer state of a single import
+type trelloImport struct {
+	client *glo.Glo
+	export *Export
+	opts   *Options
+	report *Report
+
+	board      *importer.Board
+	columns    map[string]*glo.Column
+	labelNames map[string]bool
+	labels     map[string]*Label
+	members    map[string]string
+	checklists map[string][]*Checklist
+	comments   map[string][]*Action
+}
+
+// Import creates a Glo board from a Trello export, with dry runs
+// only the report is produced
+func Import(
+	client *glo.Glo,
+	export *Export,
+	opts *Options,
+) (
+	report *Report,
+	err error,
+) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	name := opts.BoardName
+	if name == "" {
+		name = export.Name
+	}
+
+	t := &trelloImport{
+		client: client,
+		export: export,
+		opts:   opts,
+		report: &Report{
+			DryRun: opts.DryRun,
+			Board:  name,
+		},
+	}
+	t.index()
+	report = t.report
+
+	if !opts.DryRun {
+		var board *glo.Board
+		board, err = client.CreateBoard(&glo.BoardInput{Name: name})
+		if err != nil {
+			return
+		}
+		report.BoardID = board.ID
+		t.board = importer.NewBoard(client, board)
+	}
+
+	lists := t.sortedLists()
+	for _, list := range lists {
+		err = t.importColumn(list)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, label := range export.Labels {
+		err = t.importLabel(label)
+		if err != nil {
+			return
+		}
+	}
+
+	for _, list := range lists {
+		for position, card := range t.sortedCards(list) {
+			err = t.importCard(card, position)
+			if err != nil {
+				err = fmt.Errorf("failed to import card %q err:%s", card.Name, err)
+				return
+			}
+		}
+	}
+
+	return
+}
+
+// index builds lookups of the export and resolves members
+func (t *trelloImport) index() {
+	t.columns = map[string]*glo.Column{}
+	t.labelNames = map[string]bool{}
+
+	t.labels = map[string]*Label{}
+	for _, label := range t.export.Labels {
+		t.labels[label.ID] = label
+	}
+
+	t.members = map[string]string{}
+	for _, member := range t.export.Members {
+		id, ok := t.opts.Members.Lookup(member.ID, member.Username)
+		if !ok {
+			t.report.UnmappedMembers = append(t.report.UnmappedMembers, member.Username)
+			continue
+		}
+		t.members[member.ID] = id
+	}
+
+	t.checklists = map[string][]*Checklist{}
+	for _, checklist := range t.export.Checklists {
+		t.checklists[checklist.IDCard] = append(t.checklists[checklist.IDCard], checklist)
+	}
+	for _, checklists := range t.checklists {
+		sort.SliceStable(checklists, func(i, j int) bool {
+			return checklists[i].Pos < checklists[j].Pos
+		})
+	}
+
+	t.comments = map[string][]*Action{}
+	for _, action := range t.export.Actions {
+		if action.Type == actionCommentCard {
+			t.comments[action.Data.Card.ID] = append(t.comments[action.Data.Card.ID], action)
+		}
+	}
+	// exports list actions newest first
+	for _, actions := range t.comments {
+		sort.SliceStable(actions, func(i, j int) bool {
+			return actions[i].Date < actions[j].Date
+		})
+	}
+}
+
+func (t *trelloImport) sortedLists() []*List {
+	var lists []*List
+	for _, list := range t.export.Lists {
+		if list.Closed && !t.opts.IncludeClosed {
+			continue
+		}
+		lists = append(lists, list)
+	}
+	sort.SliceStable(lists, func(i, j int) bool {
+		return lists[i].Pos < lists[j].Pos
+	})
+
+	return lists
+}
+
+func (t *trelloImport) sortedCards(list *List) []*Card {
+	var cards []*Card
+	for _, card := range t.export.Cards {
+		if card.IDList != list.ID || (card.Closed && !t.opts.IncludeClosed) {
+			continue
+		}
+		cards = append(cards, card)
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].Pos < cards[j].Pos
+	})
+
+	return cards
+}
+
+// importColumn creates a column for every list, lists which
+// share a name are imported as separate columns
+func (t *trelloImport) importColumn(list *List) (err error) {
+	if t.opts.DryRun {
+		t.report.Columns++
+		return
+	}
+
+	col, err := t.board.CreateColumn(list.Name)
+	if err != nil {
+		return
+	}
+	t.columns[list.ID] = col
+	t.report.Columns++
+
+	return
+}
+
+// labelName Trello labels may be unnamed, they are named after their colour
+func labelName(label *Label) string {
+	if label.Name != "" {
+		return label.Name
+	}
+	if label.Color != "" {
+		return label.Color
+	}
+
+	return label.ID
+}
+
+// importLabel creates a label, labels are resolved by name
+// so those which share a name are merged
+func (t *trelloImport) importLabel(label *Label) (err error) {
+	name := labelName(label)
+	if t.opts.DryRun {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if !t.labelNames[key] {
+			t.labelNames[key] = true
+			t.report.Labels++
+		}
+		return
+	}
+
+	color, ok := colors[label.Color]
+	if !ok {
+		color = importer.DefaultLabelColor
+	}
+
+	_, created, err := t.board.Label(name, color)
+	if err != nil {
+		return
+	}
+	if created {
+		t.report.Labels++
+	}
+
+	return
+}
+
+func (t *trelloImport) importCard(card *Card, position int) (err error) {
+	t.report.Cards++
+	t.report.Checklists += len(t.checklists[card.ID])
+
+	input := &glo.CardsInput{
+		Name:     card.Name,
+		Position: position,
+		DueDate:  card.Due,
+	}
+
+	text := t.description(card)
+	if text != "" {
+		input.Description = &glo.MinimizedDescription{Text: text}
+	}
+
+	for _, memberID := range card.IDMembers {
+		if id, ok := t.members[memberID]; ok {
+			input.Assignees = append(input.Assignees, &glo.PartialUser{ID: id})
+		}
+	}
+
+	if t.opts.DryRun {
+		t.report.Comments += len(t.comments[card.ID])
+		for _, attachment := range card.Attachments {
+			if attachment.IsUpload {
+				t.report.Attachments++
+			} else {
+				t.report.Links++
+			}
+		}
+		return
+	}
+
+	col, ok := t.columns[card.IDList]
+	if !ok {
+		err = fmt.Errorf("list:%s of the card was not imported", card.IDList)
+		return
+	}
+	input.ColumnID = col.ID
+	for _, labelID := range card.IDLabels {
+		label, ok := t.labels[labelID]
+		if !ok {
+			continue
+		}
+		glabel, _, labelErr := t.board.Label(labelName(label), importer.DefaultLabelColor)
+		if labelErr != nil {
+			return labelErr
+		}
+		input.Labels = append(input.Labels, &glo.PartialLabel{ID: glabel.ID})
+	}
+
+	created, err := t.client.CreateCard(t.board.ID(), input)
+	if err != nil {
+		return
+	}
+
+	for _, action := range t.comments[card.ID] {
+		_, err = t.client.CreateComment(t.board.ID(), created.ID, &glo.CommentInput{
+			Text: commentText(action),
+		})
+		if err != nil {
+			return
+		}
+		t.report.Comments++
+	}
+
+	for _, attachment := range card.Attachments {
+		err = t.importAttachment(created.ID, card, attachment)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// description appends the card's checklists to its description as task lists
+func (t *trelloImport) description(card *Card) string {
+	parts := []string{}
+	if desc := strings.TrimSpace(card.Desc); desc != "" {
+		parts = append(parts, desc)
+	}
+
+	for _, checklist := range t.checklists[card.ID] {
+		items := append([]*CheckItem{}, checklist.CheckItems...)
+		sort.SliceStable(items, func(i, j int) bool {
+			return items[i].Pos < items[j].Pos
+		})
+
+		lines := []string{"### " + checklist.Name}
+		for _, item := range items {
+			mark := " "
+			if item.State == "complete" {
+				mark = "x"
+			}
+			lines = append(lines, fmt.Sprintf("- [%s] %s", mark, item.Name))
+		}
+		parts = append(parts, strings.Join(lines, "\n"))
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// commentText attributes an imported comment to its original author
+func commentText(action *Action) string {
+	author := "unknown"
+	if action.MemberCreator != nil {
+		author = action.MemberCreator.FullName
+		if author == "" {
+			author = action.MemberCreator.Username
+		}
+	}
+
+	return fmt.Sprintf("**%s** (%s):\n\n%s", author, action.Date, action.Data.Text)
+}
+
+func (t *trelloImport) importAttachment(
+	cardID string,
+	card *Card,
+	attachment *Attachment,
+) (err error) {
+	name := attachment.Name
+	if name == "" {
+		name = attachment.URL
+	}
+
+	if attachment.IsUpload {
+		body, fetchErr := t.fetch(attachment)
+		if fetchErr == nil {
+			defer body.Close()
+			_, err = t.client.CreateAttachment(t.board.ID(), cardID, name, body)
+			if err == nil {
+				t.report.Attachments++
+			}
+			return
+		}
+		t.report.warn("card %q: attachment %q could not be fetched, linked instead: %s", card.Name, name, fetchErr)
+	}
+
+	_, err = t.client.CreateComment(t.board.ID(), cardID, &glo.CommentInput{
+		Text: fmt.Sprintf("[%s](%s)", name, attachment.URL),
+	})
+	if err == nil {
+		t.report.Links++
+	}
+
+	return
+}
+
+func (t *trelloImport) fetch(attachment *Attachment) (body io.ReadCloser, err error) {
+	if t.opts.FetchAttachment != nil {
+		return t.opts.FetchAttachment(attachment)
+	}
+
+	resp, err := http.Get(attachment.URL)
+	if err != nil {
+		return
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		err = fmt.Errorf("unexpected response status:%s", resp.Status)
+		return
+	}
+	body = resp.Body
+
+	return
+}
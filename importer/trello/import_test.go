@@ -0,0 +1,153 @@
+package trello
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// fakeAPI records the boards, columns, labels, cards
+// and comments created by an import
+type fakeAPI struct {
+	mu       sync.Mutex
+	nextID   int
+	columns  []*glo.Column
+	labels   []*glo.Label
+	cards    []*glo.CardsInput
+	comments int
+}
+
+func (f *fakeAPI) id() string {
+	f.nextID++
+	return fmt.Sprintf("id%d", f.nextID)
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var v interface{}
+	switch {
+	case len(parts) == 1:
+		input := &glo.BoardInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		v = &glo.Board{ID: "b1", Name: input.Name}
+	case len(parts) == 3 && parts[2] == "columns":
+		input := &glo.ColumnInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		col := &glo.Column{ID: f.id(), Name: input.Name, Position: input.Position}
+		f.columns = append(f.columns, col)
+		v = col
+	case len(parts) == 3 && parts[2] == "labels":
+		input := &glo.LabelInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		label := &glo.Label{ID: f.id(), Name: input.Name, Color: input.Color}
+		f.labels = append(f.labels, label)
+		v = label
+	case len(parts) == 3 && parts[2] == "cards":
+		input := &glo.CardsInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		f.cards = append(f.cards, input)
+		v = &glo.Card{ID: f.id(), Name: input.Name, ColumnID: input.ColumnID}
+	case len(parts) == 5 && parts[4] == "comments":
+		f.comments++
+		v = &glo.Comment{ID: f.id()}
+	}
+	if v == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	json.NewEncoder(w).Encode(v)
+}
+
+const testExport = `{
+	"name": "Roadmap",
+	"lists": [
+		{"id": "l1", "name": "Doing", "pos": 1},
+		{"id": "l2", "name": "doing ", "pos": 2},
+		{"id": "l3", "name": "Done", "pos": 3},
+		{"id": "l4", "name": "Old", "pos": 4, "closed": true}
+	],
+	"labels": [
+		{"id": "g1", "name": "", "color": "green"},
+		{"id": "g2", "name": "", "color": "green"},
+		{"id": "g3", "name": "Bug", "color": "red"}
+	],
+	"cards": [
+		{"id": "k1", "name": "First", "idList": "l1", "idLabels": ["g1"], "pos": 1},
+		{"id": "k2", "name": "Second", "idList": "l2", "idLabels": ["g2", "g3"], "pos": 1},
+		{"id": "k3", "name": "Third", "idList": "l2", "pos": 2},
+		{"id": "k4", "name": "Archived", "idList": "l4", "pos": 1}
+	],
+	"actions": [
+		{"type": "commentCard", "date": "2026-01-01", "data": {"text": "hi", "card": {"id": "k2"}}}
+	]
+}`
+
+func TestImportKeysColumnsByList(t *testing.T) {
+	export, err := Parse(strings.NewReader(testExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeAPI{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := glo.NewClient("token")
+	client.BaseURI = srv.URL
+
+	report, err := Import(client, export, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.columns) != 3 {
+		t.Fatalf("created %d columns, want 3", len(fake.columns))
+	}
+	if report.Columns != 3 || report.Labels != 2 || report.Cards != 3 || report.Comments != 1 {
+		t.Errorf("got report %+v", report)
+	}
+	if len(fake.labels) != 2 {
+		t.Errorf("created %d labels, want 2 as the unnamed green labels merge", len(fake.labels))
+	}
+
+	// cards of lists which share a name stay in their own columns
+	want := map[string]string{
+		"First":  fake.columns[0].ID,
+		"Second": fake.columns[1].ID,
+		"Third":  fake.columns[1].ID,
+	}
+	for _, card := range fake.cards {
+		if card.ColumnID != want[card.Name] {
+			t.Errorf("card %q in column %s, want %s", card.Name, card.ColumnID, want[card.Name])
+		}
+	}
+	for i, col := range fake.columns {
+		if col.Position != i {
+			t.Errorf("column %q at position %d, want %d", col.Name, col.Position, i)
+		}
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	export, err := Parse(strings.NewReader(testExport))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Import(nil, export, &Options{DryRun: true, IncludeClosed: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Columns != 4 || report.Labels != 2 || report.Cards != 4 || report.Comments != 1 {
+		t.Errorf("got report %+v", report)
+	}
+}
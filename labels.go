@@ -0,0 +1,76 @@
+package glo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jackmcguire1/go-glo/internal/utils"
+)
+
+// LabelInput contains information used
+// to create or edit a label
+type LabelInput struct {
+	Name  string `json:"name"`
+	Color Color  `json:"color"`
+}
+
+// CreateLabel Creates a Label
+// https://gloapi.gitkraken.com/v1/docs/#/Labels/post_boards__board_id__labels
+func (a *Glo) CreateLabel(
+	boardID string,
+	input *LabelInput,
+) (
+	label *Label,
+	err error,
+) {
+	addr := fmt.Sprintf("%s/boards/%s/labels", a.BaseURI, boardID)
+
+	resp, _, err := a.jsonReq(http.MethodPost, addr, utils.ToRawMessage(input), nil)
+	if err != nil {
+		return
+	}
+
+	label = &Label{}
+	err = json.Unmarshal(resp, &label)
+
+	return
+}
+
+// EditLabel Edits a Label
+// https://gloapi.gitkraken.com/v1/docs/#/Labels/post_boards__board_id__labels__label_id_
+func (a *Glo) EditLabel(
+	boardID string,
+	labelID string,
+	input *LabelInput,
+) (
+	label *Label,
+	err error,
+) {
+	addr := fmt.Sprintf("%s/boards/%s/labels/%s", a.BaseURI, boardID, labelID)
+
+	resp, _, err := a.jsonReq(http.MethodPost, addr, utils.ToRawMessage(input), nil)
+	if err != nil {
+		return
+	}
+
+	label = &Label{}
+	err = json.Unmarshal(resp, &label)
+
+	return
+}
+
+// DeleteLabel Deletes a Label
+// https://gloapi.gitkraken.com/v1/docs/#/Labels/delete_boards__board_id__labels__label_id_
+func (a *Glo) DeleteLabel(
+	boardID string,
+	labelID string,
+) (
+	err error,
+) {
+	addr := fmt.Sprintf("%s/boards/%s/labels/%s", a.BaseURI, boardID, labelID)
+
+	_, _, err = a.jsonReq(http.MethodDelete, addr, nil, nil)
+
+	return
+}
@@ -0,0 +1,92 @@
+package glo
+
+import (
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Logger receives structured records of API requests,
+// a *slog.Logger satisfies this interface.
+type Logger interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+	Log(ctx context.Context, level slog.Level, msg string, args ...interface{})
+}
+
+const redacted = "[REDACTED]"
+
+// requestRecord information gathered about a single API request
+type requestRecord struct {
+	ctx     context.Context
+	method  string
+	url     string
+	query   url.Values
+	token   string
+	start   time.Time
+	reqBody []byte
+}
+
+func (a *Glo) logBodies(ctx context.Context) bool {
+	return a.Logger != nil &&
+		a.LogBodies &&
+		a.Logger.Enabled(ctx, slog.LevelDebug)
+}
+
+func (a *Glo) logRequest(
+	rec *requestRecord,
+	resp *Response,
+	err error,
+) {
+	if a.Logger == nil {
+		return
+	}
+	ctx := rec.ctx
+
+	path := rec.url
+	if u, parseErr := url.Parse(rec.url); parseErr == nil {
+		path = u.Path
+	}
+
+	args := []interface{}{
+		slog.String("method", rec.method),
+		slog.String("path", rec.redact(path)),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", time.Since(rec.start)),
+		slog.Int("retries", resp.Retries),
+		slog.Int("size", len(resp.Data)),
+	}
+
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelError
+		args = append(args, slog.String("error", rec.redact(err.Error())))
+	}
+	a.Logger.Log(ctx, level, "glo request", args...)
+
+	if !a.logBodies(ctx) {
+		return
+	}
+
+	a.Logger.Log(
+		ctx,
+		slog.LevelDebug,
+		"glo request body",
+		slog.String("method", rec.method),
+		slog.String("path", rec.redact(path)),
+		slog.String("query", rec.redact(rec.query.Encode())),
+		slog.String("authorization", "Bearer "+redacted),
+		slog.String("request", rec.redact(string(rec.reqBody))),
+		slog.String("response", rec.redact(string(resp.Data))),
+	)
+}
+
+// redact removes the request's token from logged values
+func (rec *requestRecord) redact(s string) string {
+	if rec.token == "" {
+		return s
+	}
+
+	return strings.ReplaceAll(s, rec.token, redacted)
+}
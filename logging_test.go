@@ -0,0 +1,121 @@
+package glo
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const testToken = "s3cr3t-t0ken"
+
+func newLoggedClient(t *testing.T, handler http.HandlerFunc, level slog.Level) (*Glo, *bytes.Buffer) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	buf := &bytes.Buffer{}
+	client := NewClient(testToken)
+	client.BaseURI = srv.URL
+	client.Logger = slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level}))
+
+	return client, buf
+}
+
+func TestLogRedactsToken(t *testing.T) {
+	client, buf := newLoggedClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer "+testToken {
+			t.Errorf("got authorization %q", got)
+		}
+		fmt.Fprintf(w, `{"echo":%q}`, testToken)
+	}, slog.LevelDebug)
+	client.LogBodies = true
+
+	_, _, err := client.jsonReq(
+		http.MethodPost,
+		client.BaseURI+"/boards/"+testToken,
+		[]byte(fmt.Sprintf(`{"token":%q}`, testToken)),
+		url.Values{"q": {testToken}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logged := buf.String()
+	if strings.Contains(logged, testToken) {
+		t.Errorf("token logged:\n%s", logged)
+	}
+	for _, want := range []string{
+		`"msg":"glo request"`,
+		`"msg":"glo request body"`,
+		`"path":"/boards/[REDACTED]"`,
+		`"authorization":"Bearer [REDACTED]"`,
+	} {
+		if !strings.Contains(logged, want) {
+			t.Errorf("log is missing %s:\n%s", want, logged)
+		}
+	}
+}
+
+func TestLogLevels(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		level     slog.Level
+		logBodies bool
+		want      []string
+		notWant   []string
+	}{
+		{
+			name:    "success without bodies",
+			status:  http.StatusOK,
+			level:   slog.LevelDebug,
+			want:    []string{`"level":"INFO"`, `"status":200`},
+			notWant: []string{`glo request body`},
+		},
+		{
+			name:      "bodies need debug level",
+			status:    http.StatusOK,
+			level:     slog.LevelInfo,
+			logBodies: true,
+			want:      []string{`"level":"INFO"`},
+			notWant:   []string{`glo request body`},
+		},
+		{
+			name:    "failure",
+			status:  http.StatusInternalServerError,
+			level:   slog.LevelInfo,
+			want:    []string{`"level":"ERROR"`, `"status":500`, `"error":`},
+			notWant: []string{testToken},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, buf := newLoggedClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, `{}`)
+			}, test.level)
+			client.LogBodies = test.logBodies
+
+			client.jsonReq(http.MethodGet, client.BaseURI+"/boards", nil, nil)
+
+			logged := buf.String()
+			for _, want := range test.want {
+				if !strings.Contains(logged, want) {
+					t.Errorf("log is missing %s:\n%s", want, logged)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(logged, notWant) {
+					t.Errorf("log contains %s:\n%s", notWant, logged)
+				}
+			}
+		})
+	}
+}
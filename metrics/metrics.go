@@ -0,0 +1,284 @@
+// Package metrics provides a Prometheus text format metrics
+// collector for the Glo API client.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// DefaultBuckets latency histogram buckets in seconds
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type endpointKey struct {
+	method   string
+	endpoint string
+}
+
+type requestKey struct {
+	endpointKey
+	status int
+}
+
+type errorKey struct {
+	endpointKey
+	class string
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// Collector records per endpoint request metrics
+type Collector struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	requests   map[requestKey]uint64
+	errors     map[errorKey]uint64
+	rateLimits map[endpointKey]uint64
+	latency    map[endpointKey]*histogram
+}
+
+// NewCollector creates a Collector using the provided latency
+// buckets in seconds, DefaultBuckets are used when none are provided
+func NewCollector(buckets ...float64) *Collector {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64{}, buckets...)
+	sort.Float64s(buckets)
+
+	return &Collector{
+		buckets:    buckets,
+		requests:   map[requestKey]uint64{},
+		errors:     map[errorKey]uint64{},
+		rateLimits: map[endpointKey]uint64{},
+		latency:    map[endpointKey]*histogram{},
+	}
+}
+
+// Middleware records metrics for every request made by a client
+//
+//	client.Middleware = append(client.Middleware, collector.Middleware())
+func (c *Collector) Middleware() glo.Middleware {
+	return func(next glo.Handler) glo.Handler {
+		return func(req *glo.Request) (*glo.Response, error) {
+			start := time.Now()
+			resp, err := next(req)
+			c.observe(req, resp, err, time.Since(start))
+
+			return resp, err
+		}
+	}
+}
+
+func (c *Collector) observe(
+	req *glo.Request,
+	resp *glo.Response,
+	err error,
+	latency time.Duration,
+) {
+	key := endpointKey{method: req.Method, endpoint: req.Endpoint}
+
+	var status, retries int
+	if resp != nil {
+		status = resp.StatusCode
+		retries = resp.Retries
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requests[requestKey{endpointKey: key, status: status}]++
+
+	if class := glo.ErrorClass(err); class != "" {
+		c.errors[errorKey{endpointKey: key, class: class}]++
+	}
+
+	// every retry followed a rate limited response
+	rateLimits := uint64(retries)
+	if status == http.StatusTooManyRequests {
+		rateLimits++
+	}
+	if rateLimits > 0 {
+		c.rateLimits[key] += rateLimits
+	}
+
+	h, ok := c.latency[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(c.buckets))}
+		c.latency[key] = h
+	}
+	seconds := latency.Seconds()
+	for i, bound := range c.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// ServeHTTP serves the collected metrics in the Prometheus text format
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	c.WriteTo(w)
+}
+
+// WriteTo writes the collected metrics in the Prometheus text format
+func (c *Collector) WriteTo(w io.Writer) (n int64, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cw := &countWriter{w: bufio.NewWriter(w)}
+
+	cw.header("glo_requests_total", "Total Glo API requests.", "counter")
+	requests := make([]requestKey, 0, len(c.requests))
+	for key := range c.requests {
+		requests = append(requests, key)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].endpointKey != requests[j].endpointKey {
+			return lessEndpoint(requests[i].endpointKey, requests[j].endpointKey)
+		}
+		return requests[i].status < requests[j].status
+	})
+	for _, key := range requests {
+		cw.sample(
+			"glo_requests_total",
+			labels(key.endpointKey, "status", strconv.Itoa(key.status)),
+			float64(c.requests[key]),
+		)
+	}
+
+	cw.header("glo_request_errors_total", "Total failed Glo API requests by error class.", "counter")
+	errs := make([]errorKey, 0, len(c.errors))
+	for key := range c.errors {
+		errs = append(errs, key)
+	}
+	sort.Slice(errs, func(i, j int) bool {
+		if errs[i].endpointKey != errs[j].endpointKey {
+			return lessEndpoint(errs[i].endpointKey, errs[j].endpointKey)
+		}
+		return errs[i].class < errs[j].class
+	})
+	for _, key := range errs {
+		cw.sample(
+			"glo_request_errors_total",
+			labels(key.endpointKey, "class", key.class),
+			float64(c.errors[key]),
+		)
+	}
+
+	cw.header("glo_rate_limit_hits_total", "Total rate limited Glo API responses.", "counter")
+	for _, key := range sortedEndpoints(c.rateLimits) {
+		cw.sample("glo_rate_limit_hits_total", labels(key), float64(c.rateLimits[key]))
+	}
+
+	cw.header("glo_request_duration_seconds", "Glo API request latency in seconds.", "histogram")
+	latencies := make([]endpointKey, 0, len(c.latency))
+	for key := range c.latency {
+		latencies = append(latencies, key)
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		return lessEndpoint(latencies[i], latencies[j])
+	})
+	for _, key := range latencies {
+		h := c.latency[key]
+		for i, bound := range c.buckets {
+			cw.sample(
+				"glo_request_duration_seconds_bucket",
+				labels(key, "le", formatFloat(bound)),
+				float64(h.counts[i]),
+			)
+		}
+		cw.sample("glo_request_duration_seconds_bucket", labels(key, "le", "+Inf"), float64(h.count))
+		cw.sample("glo_request_duration_seconds_sum", labels(key), h.sum)
+		cw.sample("glo_request_duration_seconds_count", labels(key), float64(h.count))
+	}
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+
+	return cw.n, cw.err
+}
+
+func sortedEndpoints(m map[endpointKey]uint64) []endpointKey {
+	keys := make([]endpointKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return lessEndpoint(keys[i], keys[j])
+	})
+
+	return keys
+}
+
+func lessEndpoint(a, b endpointKey) bool {
+	if a.endpoint != b.endpoint {
+		return a.endpoint < b.endpoint
+	}
+	return a.method < b.method
+}
+
+// labels formats a label set for the endpoint key
+// followed by any extra name/value pairs
+func labels(key endpointKey, extra ...string) string {
+	pairs := append([]string{"method", key.method, "endpoint", key.endpoint}, extra...)
+
+	var parts []string
+	for i := 0; i+1 < len(pairs); i += 2 {
+		parts = append(parts, fmt.Sprintf("%s=\"%s\"", pairs[i], escape(pairs[i+1])))
+	}
+
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countWriter tracks bytes written and the first write error
+type countWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countWriter) printf(format string, args ...interface{}) {
+	if cw.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(cw.w, format, args...)
+	cw.n += int64(n)
+	cw.err = err
+}
+
+func (cw *countWriter) header(name, help, kind string) {
+	cw.printf("# HELP %s %s\n", name, help)
+	cw.printf("# TYPE %s %s\n", name, kind)
+}
+
+func (cw *countWriter) sample(name, labels string, value float64) {
+	cw.printf("%s%s %s\n", name, labels, formatFloat(value))
+}
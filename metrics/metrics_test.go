@@ -0,0 +1,181 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func render(t *testing.T, c *Collector) string {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	n, err := c.WriteTo(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("WriteTo reported %d bytes, wrote %d", n, buf.Len())
+	}
+
+	return buf.String()
+}
+
+func assertLines(t *testing.T, out string, want ...string) {
+	t.Helper()
+
+	lines := map[string]bool{}
+	for _, line := range strings.Split(out, "\n") {
+		lines[line] = true
+	}
+	for _, line := range want {
+		if !lines[line] {
+			t.Errorf("output is missing %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestHistogramBucketsAreCumulative(t *testing.T) {
+	c := NewCollector(1, 0.1, 0.5)
+	req := &glo.Request{Method: http.MethodGet, Endpoint: "/boards"}
+	resp := &glo.Response{StatusCode: http.StatusOK}
+
+	for _, latency := range []time.Duration{
+		50 * time.Millisecond,
+		100 * time.Millisecond,
+		300 * time.Millisecond,
+		2 * time.Second,
+	} {
+		c.observe(req, resp, nil, latency)
+	}
+
+	assertLines(t, render(t, c),
+		`glo_request_duration_seconds_bucket{method="GET",endpoint="/boards",le="0.1"} 2`,
+		`glo_request_duration_seconds_bucket{method="GET",endpoint="/boards",le="0.5"} 3`,
+		`glo_request_duration_seconds_bucket{method="GET",endpoint="/boards",le="1"} 3`,
+		`glo_request_duration_seconds_bucket{method="GET",endpoint="/boards",le="+Inf"} 4`,
+		`glo_request_duration_seconds_sum{method="GET",endpoint="/boards"} 2.45`,
+		`glo_request_duration_seconds_count{method="GET",endpoint="/boards"} 4`,
+		`glo_requests_total{method="GET",endpoint="/boards",status="200"} 4`,
+	)
+}
+
+func TestLabelEscaping(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+	}{
+		{`/boards`, `{method="GET",endpoint="/boards"}`},
+		{`/a"b`, `{method="GET",endpoint="/a\"b"}`},
+		{`/a\b`, `{method="GET",endpoint="/a\\b"}`},
+		{"/a\nb", `{method="GET",endpoint="/a\nb"}`},
+	}
+
+	for _, test := range tests {
+		got := labels(endpointKey{method: http.MethodGet, endpoint: test.endpoint})
+		if got != test.want {
+			t.Errorf("labels(%q) got %s, want %s", test.endpoint, got, test.want)
+		}
+	}
+}
+
+func TestRateLimitAccounting(t *testing.T) {
+	tests := []struct {
+		name       string
+		resp       *glo.Response
+		err        error
+		rateLimits string
+		class      string
+	}{
+		{
+			name: "success",
+			resp: &glo.Response{StatusCode: http.StatusOK},
+		},
+		{
+			name:       "success after retries",
+			resp:       &glo.Response{StatusCode: http.StatusOK, Retries: 2},
+			rateLimits: "2",
+		},
+		{
+			name:       "rate limited after retries",
+			resp:       &glo.Response{StatusCode: http.StatusTooManyRequests, Retries: 3},
+			err:        glo.ErrRateLimited,
+			rateLimits: "4",
+			class:      glo.ErrorClassRateLimited,
+		},
+		{
+			name:  "server error",
+			resp:  &glo.Response{StatusCode: http.StatusBadGateway},
+			err:   &glo.StatusError{StatusCode: http.StatusBadGateway},
+			class: glo.ErrorClassServer,
+		},
+		{
+			name:  "transport error",
+			err:   errors.New("connection refused"),
+			class: glo.ErrorClassTransport,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c := NewCollector()
+			req := &glo.Request{Method: http.MethodGet, Endpoint: "/boards"}
+			c.observe(req, test.resp, test.err, time.Millisecond)
+			out := render(t, c)
+
+			rateLimits := `glo_rate_limit_hits_total{method="GET",endpoint="/boards"}`
+			if test.rateLimits == "" {
+				if strings.Contains(out, rateLimits) {
+					t.Errorf("unexpected rate limit hits:\n%s", out)
+				}
+			} else {
+				assertLines(t, out, rateLimits+" "+test.rateLimits)
+			}
+
+			errs := `glo_request_errors_total{method="GET",endpoint="/boards",class="`
+			if test.class == "" {
+				if strings.Contains(out, errs) {
+					t.Errorf("unexpected errors:\n%s", out)
+				}
+			} else {
+				assertLines(t, out, errs+test.class+`"} 1`)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := NewCollector()
+	client := glo.NewClient("token")
+	client.BaseURI = srv.URL
+	client.Middleware = append(client.Middleware, c.Middleware())
+
+	if _, err := client.GetBoard("b1"); !errors.Is(err, glo.ErrRateLimited) {
+		t.Errorf("got err %v, want %v", err, glo.ErrRateLimited)
+	}
+	client.GetBoard("missing")
+
+	assertLines(t, render(t, c),
+		`glo_requests_total{method="GET",endpoint="/boards/{board_id}",status="404"} 1`,
+		`glo_requests_total{method="GET",endpoint="/boards/{board_id}",status="429"} 1`,
+		`glo_request_errors_total{method="GET",endpoint="/boards/{board_id}",class="`+glo.ErrorClassClient+`"} 1`,
+		`glo_request_errors_total{method="GET",endpoint="/boards/{board_id}",class="`+glo.ErrorClassRateLimited+`"} 1`,
+		`glo_rate_limit_hits_total{method="GET",endpoint="/boards/{board_id}"} 1`,
+		`glo_request_duration_seconds_count{method="GET",endpoint="/boards/{board_id}"} 2`,
+	)
+}
@@ -0,0 +1,55 @@
+package glo
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Request describes an API request passing through the middleware chain
+type Request struct {
+	Context context.Context
+	Method  string
+	URL     string
+
+	// Endpoint the request path with IDs replaced
+	// by placeholders e.g. /boards/{board_id}/cards
+	Endpoint string
+	Query    url.Values
+}
+
+// Response describes the outcome of an API request
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Data       []byte
+
+	// Retries the number of times the request
+	// was retried after being rate limited
+	Retries int
+}
+
+// Handler performs an API request
+type Handler func(req *Request) (resp *Response, err error)
+
+// Middleware wraps a Handler, allowing requests to be
+// observed or modified e.g. for tracing or metrics
+type Middleware func(next Handler) Handler
+
+// endpoint templates the path of addr relative to baseURI,
+// replacing resource IDs with named placeholders
+func endpoint(baseURI, addr string) string {
+	path := strings.TrimPrefix(addr, baseURI)
+	if u, err := url.Parse(path); err == nil {
+		path = u.Path
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 1; i < len(segments); i += 2 {
+		resource := strings.TrimSuffix(segments[i-1], "s")
+		segments[i] = "{" + resource + "_id}"
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,373 @@
+// Package mirror keeps an offline copy of boards, their columns, cards,
+// comments and attachment metadata in a local Store.
+//
+// Sync only re-reads cards whose UpdatedDate or comment and attachment
+// counts changed, and records boards and cards which no longer exist as
+// tombstones. Reads, such as GetBoard and GetCards, answer from the
+// store without calling the API.
+package mirror
+
+import (
+	"encoding/json"
+	"errors"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Mirror a local copy of boards
+type Mirror struct {
+	Client *glo.Glo
+	Store  Store
+
+	mu sync.Mutex
+}
+
+// New a mirror kept in a store
+func New(client *glo.Glo, store Store) *Mirror {
+	return &Mirror{Client: client, Store: store}
+}
+
+// Open a mirror kept in a directory
+func Open(client *glo.Glo, dir string) *Mirror {
+	return New(client, NewDirStore(dir))
+}
+
+// DefaultDir the mirror directory alongside the glo config file
+func DefaultDir() (dir string, err error) {
+	config, err := glo.ConfigPath()
+	if err != nil {
+		return
+	}
+
+	return filepath.Join(filepath.Dir(config), "mirror"), nil
+}
+
+// boardRecord a mirrored board
+type boardRecord struct {
+	Board    *glo.Board `json:"board"`
+	SyncedAt time.Time  `json:"synced_at"`
+}
+
+// Tombstone records a board or card which was deleted
+type Tombstone struct {
+	Kind      string    `json:"kind"`
+	ID        string    `json:"id"`
+	BoardID   string    `json:"board_id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// Tombstone kinds
+const (
+	KindBoard = "board"
+	KindCard  = "card"
+)
+
+// SyncStats counts the changes made by a sync
+type SyncStats struct {
+	Boards      int `json:"boards"`
+	Cards       int `json:"cards"`
+	Updated     int `json:"updated"`
+	Unchanged   int `json:"unchanged"`
+	Comments    int `json:"comments"`
+	Attachments int `json:"attachments"`
+	Deleted     int `json:"deleted"`
+}
+
+// store keys
+func boardKey(boardID string) string {
+	return path.Join("boards", boardID)
+}
+
+func cardKey(boardID, cardID string) string {
+	return path.Join("cards", boardID, cardID)
+}
+
+func commentsKey(boardID, cardID string) string {
+	return path.Join("comments", boardID, cardID)
+}
+
+func attachmentsKey(boardID, cardID string) string {
+	return path.Join("attachments", boardID, cardID)
+}
+
+func boardTombstoneKey(boardID string) string {
+	return path.Join("deleted", "boards", boardID)
+}
+
+func cardTombstoneKey(boardID, cardID string) string {
+	return path.Join("deleted", "cards", boardID, cardID)
+}
+
+func (m *Mirror) get(key string, v interface{}) (err error) {
+	data, err := m.Store.Get(key)
+	if err != nil {
+		return
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+func (m *Mirror) put(key string, v interface{}) (err error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	return m.Store.Put(key, data)
+}
+
+// Sync mirrors boards, including archived boards and cards, every board
+// when none are given. When every board is synced, boards which no
+// longer exist are deleted from the mirror.
+func (m *Mirror) Sync(boardIDs ...string) (stats *SyncStats, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats = &SyncStats{}
+
+	all := len(boardIDs) == 0
+	if all {
+		for _, archived := range []bool{false, true} {
+			var boards []*glo.Board
+			boards, err = m.Client.AllBoards(archived, glo.Fields(glo.BoardFieldName))
+			if err != nil {
+				return
+			}
+			for _, board := range boards {
+				boardIDs = append(boardIDs, board.ID)
+			}
+		}
+	}
+
+	listed := map[string]bool{}
+	for _, boardID := range boardIDs {
+		if listed[boardID] {
+			continue
+		}
+		listed[boardID] = true
+		err = m.syncBoard(boardID, stats)
+		if err != nil {
+			return
+		}
+		stats.Boards++
+	}
+
+	if !all {
+		return
+	}
+
+	keys, err := m.Store.List("boards")
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		boardID := path.Base(key)
+		if listed[boardID] {
+			continue
+		}
+		err = m.deleteBoard(boardID)
+		if err != nil {
+			return
+		}
+		stats.Deleted++
+	}
+
+	return
+}
+
+func (m *Mirror) syncBoard(boardID string, stats *SyncStats) (err error) {
+	board, err := m.Client.GetBoard(boardID)
+	if err != nil {
+		return
+	}
+	board.ID = boardID
+
+	cards, err := m.Client.AllCards(boardID, false)
+	if err != nil {
+		return
+	}
+	archived, err := m.Client.AllCards(boardID, true)
+	if err != nil {
+		return
+	}
+	cards = append(cards, archived...)
+
+	listed := map[string]bool{}
+	for _, card := range cards {
+		if listed[card.ID] {
+			continue
+		}
+		listed[card.ID] = true
+		stats.Cards++
+
+		err = m.syncCard(boardID, card, stats)
+		if err != nil {
+			return
+		}
+	}
+
+	keys, err := m.Store.List(path.Join("cards", boardID))
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		cardID := path.Base(key)
+		if listed[cardID] {
+			continue
+		}
+		err = m.deleteCard(boardID, cardID)
+		if err != nil {
+			return
+		}
+		stats.Deleted++
+	}
+
+	err = m.Store.Delete(boardTombstoneKey(boardID))
+	if err != nil {
+		return
+	}
+
+	// the board is written last, so a mirrored board's cards are complete
+	return m.put(boardKey(boardID), &boardRecord{
+		Board:    board,
+		SyncedAt: time.Now().UTC(),
+	})
+}
+
+// syncCard stores a card, re-reading its comments and
+// attachments when the card changed
+func (m *Mirror) syncCard(boardID string, card *glo.Card, stats *SyncStats) (err error) {
+	var prev *glo.Card
+	err = m.get(cardKey(boardID, card.ID), &prev)
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		return
+	}
+	err = nil
+
+	changed := prev == nil ||
+		card.UpdatedDate == "" ||
+		prev.UpdatedDate != card.UpdatedDate ||
+		prev.ArchivedDate != card.ArchivedDate ||
+		prev.ColumnID != card.ColumnID
+	commentsChanged := changed || prev.CommentCount != card.CommentCount
+	attachmentsChanged := changed || prev.AttachmentCount != card.AttachmentCount
+
+	if !commentsChanged && !attachmentsChanged {
+		stats.Unchanged++
+		return
+	}
+	stats.Updated++
+
+	if commentsChanged {
+		comments := []*glo.Comment{}
+		if card.CommentCount > 0 {
+			comments, err = m.Client.AllComments(boardID, card.ID)
+			if err != nil {
+				return
+			}
+		}
+		err = m.put(commentsKey(boardID, card.ID), comments)
+		if err != nil {
+			return
+		}
+		stats.Comments += len(comments)
+	}
+
+	if attachmentsChanged {
+		attachments := []*glo.Attachment{}
+		if card.AttachmentCount > 0 {
+			attachments, err = m.Client.AllAttachments(boardID, card.ID)
+			if err != nil {
+				return
+			}
+		}
+		err = m.put(attachmentsKey(boardID, card.ID), attachments)
+		if err != nil {
+			return
+		}
+		stats.Attachments += len(attachments)
+	}
+
+	if prev == nil {
+		err = m.Store.Delete(cardTombstoneKey(boardID, card.ID))
+		if err != nil {
+			return
+		}
+	}
+
+	// the card is written last, so a failed sync re-reads
+	// its comments and attachments
+	return m.put(cardKey(boardID, card.ID), card)
+}
+
+// deleteCard removes a card and records its tombstone
+func (m *Mirror) deleteCard(boardID, cardID string) (err error) {
+	tombstone := &Tombstone{
+		Kind:      KindCard,
+		ID:        cardID,
+		BoardID:   boardID,
+		DeletedAt: time.Now().UTC(),
+	}
+	var card *glo.Card
+	if m.get(cardKey(boardID, cardID), &card) == nil && card != nil {
+		tombstone.Name = card.Name
+	}
+
+	err = m.put(cardTombstoneKey(boardID, cardID), tombstone)
+	if err != nil {
+		return
+	}
+
+	for _, key := range []string{
+		commentsKey(boardID, cardID),
+		attachmentsKey(boardID, cardID),
+		cardKey(boardID, cardID),
+	} {
+		err = m.Store.Delete(key)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// deleteBoard removes a board with its cards and records its tombstone
+func (m *Mirror) deleteBoard(boardID string) (err error) {
+	tombstone := &Tombstone{
+		Kind:      KindBoard,
+		ID:        boardID,
+		BoardID:   boardID,
+		DeletedAt: time.Now().UTC(),
+	}
+	record := &boardRecord{}
+	if m.get(boardKey(boardID), record) == nil && record.Board != nil {
+		tombstone.Name = record.Board.Name
+	}
+
+	err = m.put(boardTombstoneKey(boardID), tombstone)
+	if err != nil {
+		return
+	}
+
+	for _, prefix := range []string{"cards", "comments", "attachments"} {
+		var keys []string
+		keys, err = m.Store.List(path.Join(prefix, boardID))
+		if err != nil {
+			return
+		}
+		for _, key := range keys {
+			err = m.Store.Delete(key)
+			if err != nil {
+				return
+			}
+		}
+	}
+
+	return m.Store.Delete(boardKey(boardID))
+}
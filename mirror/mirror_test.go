@@ -0,0 +1,269 @@
+package mirror
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// fakeAPI serves boards, cards, comments and attachments
+// as the Glo API does, counting the requests for each path
+type fakeAPI struct {
+	mu          sync.Mutex
+	boards      []*glo.Board
+	cards       map[string][]*glo.Card
+	comments    map[string][]*glo.Comment
+	attachments map[string][]*glo.Attachment
+	requests    map[string]int
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		cards:       map[string][]*glo.Card{},
+		comments:    map[string][]*glo.Comment{},
+		attachments: map[string][]*glo.Attachment{},
+		requests:    map[string]int{},
+	}
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.requests[r.URL.Path]++
+	archived := r.URL.Query().Get("archived") == "true"
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	var v interface{}
+	switch {
+	case len(parts) == 1 && parts[0] == "boards":
+		boards := []*glo.Board{}
+		for _, board := range f.boards {
+			if (board.ArchivedDate != "") == archived {
+				boards = append(boards, board)
+			}
+		}
+		v = boards
+	case len(parts) == 2:
+		for _, board := range f.boards {
+			if board.ID == parts[1] {
+				v = board
+			}
+		}
+	case len(parts) == 3 && parts[2] == "cards":
+		cards := []*glo.Card{}
+		for _, card := range f.cards[parts[1]] {
+			if (card.ArchivedDate != "") == archived {
+				cards = append(cards, card)
+			}
+		}
+		v = cards
+	case len(parts) == 5 && parts[4] == "comments":
+		v = f.comments[parts[3]]
+	case len(parts) == 5 && parts[4] == "attachments":
+		v = f.attachments[parts[3]]
+	}
+	if v == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("has-more", "false")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeAPI) count(path string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.requests[path]
+}
+
+func newTestMirror(t *testing.T) (m *Mirror, api *fakeAPI) {
+	api = newFakeAPI()
+	api.boards = []*glo.Board{
+		{ID: "b1", Name: "Team", Columns: []*glo.Column{{ID: "c1", Name: "To Do"}, {ID: "c2", Name: "Done", Position: 1}}},
+		{ID: "b2", Name: "Archive", ArchivedDate: "2019-01-01T00:00:00Z"},
+	}
+	api.cards["b1"] = []*glo.Card{
+		{ID: "k1", Name: "first", ColumnID: "c2", UpdatedDate: "1", CommentCount: 2},
+		{ID: "k2", Name: "second", ColumnID: "c1", Position: 1, UpdatedDate: "1", AttachmentCount: 1},
+		{ID: "k3", Name: "third", ColumnID: "c1", UpdatedDate: "1"},
+		{ID: "k4", Name: "old", ColumnID: "c1", UpdatedDate: "1", ArchivedDate: "2019-01-01T00:00:00Z"},
+	}
+	api.cards["b2"] = []*glo.Card{{ID: "k5", Name: "archived board card", UpdatedDate: "1"}}
+	api.comments["k1"] = []*glo.Comment{
+		{ID: "m2", Text: "later", CreatedDate: "2019-01-02T00:00:00Z"},
+		{ID: "m1", Text: "earlier", CreatedDate: "2019-01-01T00:00:00Z"},
+	}
+	api.attachments["k2"] = []*glo.Attachment{{BaseAttachment: glo.BaseAttachment{ID: "a1", Filename: "f.txt"}}}
+
+	server := httptest.NewServer(api)
+	t.Cleanup(server.Close)
+
+	client := glo.NewClient("token")
+	client.BaseURI = server.URL
+
+	return New(client, NewMemoryStore()), api
+}
+
+func cardNames(cards []*glo.Card) []string {
+	var names []string
+	for _, card := range cards {
+		names = append(names, card.Name)
+	}
+
+	return names
+}
+
+func TestSync(t *testing.T) {
+	m, api := newTestMirror(t)
+
+	tests := []struct {
+		name   string
+		change func()
+		boards []string
+		want   SyncStats
+	}{
+		{
+			name: "initial",
+			want: SyncStats{Boards: 2, Cards: 5, Updated: 5, Comments: 2, Attachments: 1},
+		},
+		{
+			name: "unchanged",
+			want: SyncStats{Boards: 2, Cards: 5, Unchanged: 5},
+		},
+		{
+			name: "comment added",
+			change: func() {
+				api.cards["b1"][0].CommentCount = 3
+				api.comments["k1"] = append(api.comments["k1"], &glo.Comment{ID: "m3", Text: "latest", CreatedDate: "2019-01-03T00:00:00Z"})
+			},
+			want: SyncStats{Boards: 2, Cards: 5, Updated: 1, Unchanged: 4, Comments: 3},
+		},
+		{
+			name:   "card edited, one board",
+			change: func() { api.cards["b1"][2].UpdatedDate = "2" },
+			boards: []string{"b1"},
+			want:   SyncStats{Boards: 1, Cards: 4, Updated: 1, Unchanged: 3},
+		},
+		{
+			name:   "card deleted",
+			change: func() { api.cards["b1"] = api.cards["b1"][:3] },
+			want:   SyncStats{Boards: 2, Cards: 4, Unchanged: 4, Deleted: 1},
+		},
+		{
+			name:   "board deleted",
+			change: func() { api.boards = api.boards[:1] },
+			want:   SyncStats{Boards: 1, Cards: 3, Unchanged: 3, Deleted: 1},
+		},
+	}
+
+	for _, test := range tests {
+		if test.change != nil {
+			api.mu.Lock()
+			test.change()
+			api.mu.Unlock()
+		}
+
+		stats, err := m.Sync(test.boards...)
+		if err != nil {
+			t.Fatalf("%s: Sync err:%s", test.name, err)
+		}
+		if *stats != test.want {
+			t.Errorf("%s: Sync = %+v, want %+v", test.name, *stats, test.want)
+		}
+	}
+
+	// unchanged cards with comments are not re-read
+	if got := api.count("/boards/b1/cards/k1/comments"); got != 2 {
+		t.Errorf("comments of k1 were read %d times, want 2", got)
+	}
+	if got := api.count("/boards/b1/cards/k2/attachments"); got != 1 {
+		t.Errorf("attachments of k2 were read %d times, want 1", got)
+	}
+	if got := api.count("/boards/b1/cards/k3/comments"); got != 0 {
+		t.Errorf("comments of k3, which has none, were read %d times", got)
+	}
+
+	deleted, err := m.Deleted("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tombstones []string
+	for _, tombstone := range deleted {
+		tombstones = append(tombstones, tombstone.Kind+":"+tombstone.ID+":"+tombstone.Name)
+	}
+	sort.Strings(tombstones)
+	if want := []string{"board:b2:Archive", "card:k4:old"}; !reflect.DeepEqual(tombstones, want) {
+		t.Errorf("Deleted = %v, want %v", tombstones, want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	m, _ := newTestMirror(t)
+
+	if _, err := m.GetBoard("b1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBoard before a sync err:%v, want ErrNotFound", err)
+	}
+
+	_, err := m.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	boards, err := m.GetBoards(false)
+	if err != nil || len(boards) != 1 || boards[0].Name != "Team" {
+		t.Errorf("GetBoards(false) = %v err:%v", boards, err)
+	}
+	boards, err = m.GetBoards(true)
+	if err != nil || len(boards) != 1 || boards[0].Name != "Archive" {
+		t.Errorf("GetBoards(true) = %v err:%v", boards, err)
+	}
+
+	tests := []struct {
+		name string
+		read func() ([]*glo.Card, error)
+		want []string
+	}{
+		{"active cards", func() ([]*glo.Card, error) { return m.GetCards("b1", false) }, []string{"third", "second", "first"}},
+		{"archived cards", func() ([]*glo.Card, error) { return m.GetCards("b1", true) }, []string{"old"}},
+		{"column", func() ([]*glo.Card, error) { return m.CardsByColumn("b1", "c1", false) }, []string{"third", "second"}},
+		{"archived board", func() ([]*glo.Card, error) { return m.GetCards("b2", false) }, []string{"archived board card"}},
+	}
+	for _, test := range tests {
+		cards, err := test.read()
+		if err != nil {
+			t.Errorf("%s err:%s", test.name, err)
+			continue
+		}
+		if got := cardNames(cards); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s = %v, want %v", test.name, got, test.want)
+		}
+	}
+
+	comments, err := m.GetComments("b1", "k1")
+	if err != nil || len(comments) != 2 || comments[0].Text != "earlier" {
+		t.Errorf("GetComments = %v err:%v, want the oldest first", comments, err)
+	}
+	attachments, err := m.GetAttachments("b1", "k2")
+	if err != nil || len(attachments) != 1 || attachments[0].Filename != "f.txt" {
+		t.Errorf("GetAttachments = %v err:%v", attachments, err)
+	}
+
+	snapshot, err := m.Snapshot("b1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cardNames(snapshot.Cards); !reflect.DeepEqual(got, []string{"third", "second", "first"}) {
+		t.Errorf("Snapshot cards = %v", got)
+	}
+}
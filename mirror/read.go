@@ -0,0 +1,227 @@
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// GetBoards the mirrored boards, archived or not, by name
+func (m *Mirror) GetBoards(archived bool) (boards []*glo.Board, err error) {
+	keys, err := m.Store.List("boards")
+	if err != nil {
+		return
+	}
+
+	for _, key := range keys {
+		record := &boardRecord{}
+		err = m.get(key, record)
+		if err != nil {
+			return
+		}
+		if (record.Board.ArchivedDate != "") == archived {
+			boards = append(boards, record.Board)
+		}
+	}
+	sort.SliceStable(boards, func(i, j int) bool {
+		return boards[i].Name < boards[j].Name
+	})
+
+	return
+}
+
+// GetBoard a mirrored board, ErrNotFound when it has not been synced
+func (m *Mirror) GetBoard(boardID string) (board *glo.Board, err error) {
+	record := &boardRecord{}
+	err = m.get(boardKey(boardID), record)
+	if errors.Is(err, ErrNotFound) {
+		err = fmt.Errorf("board %s is not mirrored: %w", boardID, err)
+	}
+	if err != nil {
+		return
+	}
+
+	return record.Board, nil
+}
+
+// LastSync when a board was last synced, ErrNotFound when it has not been
+func (m *Mirror) LastSync(boardID string) (synced time.Time, err error) {
+	record := &boardRecord{}
+	err = m.get(boardKey(boardID), record)
+
+	return record.SyncedAt, err
+}
+
+// GetCards the mirrored cards of a board, archived or not,
+// in column then position order
+func (m *Mirror) GetCards(boardID string, archived bool) (cards []*glo.Card, err error) {
+	board, err := m.GetBoard(boardID)
+	if err != nil {
+		return
+	}
+
+	keys, err := m.Store.List(path.Join("cards", boardID))
+	if err != nil {
+		return
+	}
+	for _, key := range keys {
+		var card *glo.Card
+		err = m.get(key, &card)
+		if err != nil {
+			return
+		}
+		if (card.ArchivedDate != "") == archived {
+			cards = append(cards, card)
+		}
+	}
+
+	columns := map[string]int{}
+	for _, col := range board.Columns {
+		columns[col.ID] = col.Position
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		if cards[i].ColumnID != cards[j].ColumnID {
+			return columns[cards[i].ColumnID] < columns[cards[j].ColumnID]
+		}
+		return cards[i].Position < cards[j].Position
+	})
+
+	return
+}
+
+// CardsByColumn the mirrored cards of a column, in position order
+func (m *Mirror) CardsByColumn(
+	boardID string,
+	columnID string,
+	archived bool,
+) (
+	cards []*glo.Card,
+	err error,
+) {
+	all, err := m.GetCards(boardID, archived)
+	if err != nil {
+		return
+	}
+	for _, card := range all {
+		if card.ColumnID == columnID {
+			cards = append(cards, card)
+		}
+	}
+
+	return
+}
+
+// GetCard a mirrored card, ErrNotFound when it is not mirrored
+func (m *Mirror) GetCard(boardID, cardID string) (card *glo.Card, err error) {
+	err = m.get(cardKey(boardID, cardID), &card)
+	if errors.Is(err, ErrNotFound) {
+		err = fmt.Errorf("card %s is not mirrored: %w", cardID, err)
+	}
+
+	return
+}
+
+// GetComments the mirrored comments of a card, oldest first
+func (m *Mirror) GetComments(boardID, cardID string) (comments []*glo.Comment, err error) {
+	err = m.get(commentsKey(boardID, cardID), &comments)
+	if err != nil {
+		return
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedDate < comments[j].CreatedDate
+	})
+
+	return
+}
+
+// GetAttachments the mirrored attachment metadata of a card
+func (m *Mirror) GetAttachments(boardID, cardID string) (attachments []*glo.Attachment, err error) {
+	err = m.get(attachmentsKey(boardID, cardID), &attachments)
+
+	return
+}
+
+// Deleted the tombstones of the boards, and of a board's cards,
+// which were deleted, every board's when boardID is empty
+func (m *Mirror) Deleted(boardID string) (tombstones []*Tombstone, err error) {
+	cards := path.Join("deleted", "cards")
+	if boardID != "" {
+		cards = path.Join(cards, boardID)
+	}
+
+	for _, prefix := range []string{path.Join("deleted", "boards"), cards} {
+		var keys []string
+		keys, err = m.Store.List(prefix)
+		if err != nil {
+			return
+		}
+		for _, key := range keys {
+			tombstone := &Tombstone{}
+			err = m.get(key, tombstone)
+			if err != nil {
+				return
+			}
+			if boardID == "" || tombstone.BoardID == boardID {
+				tombstones = append(tombstones, tombstone)
+			}
+		}
+	}
+	sort.SliceStable(tombstones, func(i, j int) bool {
+		return tombstones[i].DeletedAt.Before(tombstones[j].DeletedAt)
+	})
+
+	return
+}
+
+// Snapshot a snapshot of a mirrored board, for use with the
+// export and reporting packages, taken at its last sync
+func (m *Mirror) Snapshot(boardID string, opts *glo.SnapshotOptions) (snapshot *glo.BoardSnapshot, err error) {
+	if opts == nil {
+		opts = &glo.SnapshotOptions{}
+	}
+
+	board, err := m.GetBoard(boardID)
+	if err != nil {
+		return
+	}
+	synced, err := m.LastSync(boardID)
+	if err != nil {
+		return
+	}
+
+	snapshot = &glo.BoardSnapshot{
+		CapturedAt: synced,
+		Board:      board,
+	}
+	snapshot.Cards, err = m.GetCards(boardID, false)
+	if err != nil {
+		return
+	}
+	if opts.Archived {
+		var archived []*glo.Card
+		archived, err = m.GetCards(boardID, true)
+		if err != nil {
+			return
+		}
+		snapshot.Cards = append(snapshot.Cards, archived...)
+	}
+
+	if opts.Comments {
+		snapshot.Comments = map[string][]*glo.Comment{}
+		for _, card := range snapshot.Cards {
+			comments, commentsErr := m.GetComments(boardID, card.ID)
+			if commentsErr != nil && !errors.Is(commentsErr, ErrNotFound) {
+				return nil, commentsErr
+			}
+			if len(comments) > 0 {
+				snapshot.Comments[card.ID] = comments
+			}
+		}
+	}
+
+	return
+}
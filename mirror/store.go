@@ -0,0 +1,204 @@
+package mirror
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound returned by a Store when a key has no value
+var ErrNotFound = errors.New("not found")
+
+// Store a key-value store, keys are slash separated paths
+// of letters, digits, dashes and underscores
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	Delete(key string) error
+
+	// List the keys under a prefix, in order
+	List(prefix string) ([]string, error)
+}
+
+var validKey = regexp.MustCompile(`^[A-Za-z0-9_-]+(/[A-Za-z0-9_-]+)*$`)
+
+func checkKey(key string) error {
+	if !validKey.MatchString(key) {
+		return fmt.Errorf("invalid key %q", key)
+	}
+
+	return nil
+}
+
+// DirStore a Store keeping each value in a file of a directory
+type DirStore struct {
+	Dir string
+}
+
+// NewDirStore a store in a directory, created when it is first written
+func NewDirStore(dir string) *DirStore {
+	return &DirStore{Dir: dir}
+}
+
+const valueExt = ".json"
+
+func (s *DirStore) path(key string) (path string, err error) {
+	err = checkKey(key)
+	if err != nil {
+		return
+	}
+
+	return filepath.Join(s.Dir, filepath.FromSlash(key)+valueExt), nil
+}
+
+// Get reads the value of a key
+func (s *DirStore) Get(key string) (value []byte, err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	value, err = ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		err = ErrNotFound
+	}
+
+	return
+}
+
+// Put writes the value of a key, replacing the file atomically
+func (s *DirStore) Put(key string, value []byte) (err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return
+	}
+
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, value, 0600)
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Delete removes a key, deleting a missing key is not an error
+func (s *DirStore) Delete(key string) (err error) {
+	path, err := s.path(key)
+	if err != nil {
+		return
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		err = nil
+	}
+
+	return
+}
+
+// List the keys under a prefix
+func (s *DirStore) List(prefix string) (keys []string, err error) {
+	root := s.Dir
+	if prefix != "" {
+		if err = checkKey(prefix); err != nil {
+			return
+		}
+		root = filepath.Join(s.Dir, filepath.FromSlash(prefix))
+	}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if info.IsDir() || !strings.HasSuffix(path, valueExt) {
+			return nil
+		}
+
+		rel, relErr := filepath.Rel(s.Dir, path)
+		if relErr != nil {
+			return relErr
+		}
+		keys = append(keys, filepath.ToSlash(strings.TrimSuffix(rel, valueExt)))
+
+		return nil
+	})
+	if os.IsNotExist(err) {
+		err = nil
+	}
+	sort.Strings(keys)
+
+	return
+}
+
+// MemoryStore a Store held in memory
+type MemoryStore struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+// NewMemoryStore an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: map[string][]byte{}}
+}
+
+// Get reads the value of a key
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	value, ok := s.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return value, nil
+}
+
+// Put writes the value of a key
+func (s *MemoryStore) Put(key string, value []byte) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = append([]byte{}, value...)
+
+	return nil
+}
+
+// Delete removes a key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+
+	return nil
+}
+
+// List the keys under a prefix
+func (s *MemoryStore) List(prefix string) (keys []string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for key := range s.values {
+		if prefix == "" || key == prefix || strings.HasPrefix(key, prefix+"/") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return
+}
@@ -1,5 +1,7 @@
 package glo
 
+import "fmt"
+
 // Color information related to a Label's colour
 type Color struct {
 	R int     `json:"r"`
@@ -33,3 +35,14 @@ type PartialLabel struct {
 type PartialUser struct {
 	ID string `json:"id"`
 }
+
+// Hex formats the colour as #rrggbb, or #rrggbbaa
+// when it is not fully opaque
+func (c Color) Hex() string {
+	s := fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	if alpha := int(c.A*0xff + 0.5); alpha < 0xff {
+		s += fmt.Sprintf("%02x", alpha)
+	}
+
+	return s
+}
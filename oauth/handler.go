@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+)
+
+// CallbackHandler handles the redirect back from the authorization
+// server, verifying the state and exchanging the code for a token.
+//
+// fn is called with the token, or the error which prevented one
+// from being obtained, before a short response is written to the user.
+func (c *Config) CallbackHandler(
+	state string,
+	fn func(token *Token, err error),
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+
+		if errCode := q.Get("error"); errCode != "" {
+			err := &Error{
+				StatusCode:  http.StatusBadRequest,
+				Code:        errCode,
+				Description: q.Get("error_description"),
+			}
+			fn(nil, err)
+			http.Error(w, "authorization failed", http.StatusBadRequest)
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(q.Get("state")), []byte(state)) != 1 {
+			fn(nil, fmt.Errorf("callback state mismatch"))
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		}
+
+		code := q.Get("code")
+		if code == "" {
+			fn(nil, fmt.Errorf("callback did not contain an authorization code"))
+			http.Error(w, "missing code", http.StatusBadRequest)
+			return
+		}
+
+		token, err := c.Exchange(r.Context(), code)
+		fn(token, err)
+		if err != nil {
+			http.Error(w, "authorization failed", http.StatusBadGateway)
+			return
+		}
+
+		fmt.Fprintln(w, "Authorization complete, you may close this window.")
+	})
+}
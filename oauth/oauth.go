@@ -0,0 +1,261 @@
+// Package oauth implements the GitKraken OAuth authorization code flow
+// and a refreshing token source for the Glo API client.
+//
+// https://support.gitkraken.com/developers/oauth/
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GitKraken OAuth endpoints
+const (
+	AuthURL  = "https://app.gitkraken.com/oauth/authorize"
+	TokenURL = "https://api.gitkraken.com/oauth/access_token"
+)
+
+// Glo OAuth scopes
+const (
+	ScopeBoardRead  = "board:read"
+	ScopeBoardWrite = "board:write"
+	ScopeUserRead   = "user:read"
+	ScopeUserWrite  = "user:write"
+)
+
+// expiryDelta tokens are treated as expired slightly early
+// so that they do not expire while a request is in flight
+const expiryDelta = 10 * time.Second
+
+// Config contains the information related to an OAuth application
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// AuthURL and TokenURL default to the GitKraken endpoints
+	AuthURL  string
+	TokenURL string
+
+	// HTTPClient used for token requests, defaults to http.DefaultClient
+	HTTPClient *http.Client
+
+	// OnRefresh when set, is called with every token obtained
+	// by a refresh so that rotated tokens can be persisted
+	OnRefresh func(token *Token)
+}
+
+// Token contains the information related to an OAuth access token
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Expired reports whether the token has expired,
+// tokens without an expiry never expire
+func (t *Token) Expired() bool {
+	if t.Expiry.IsZero() {
+		return false
+	}
+
+	return time.Now().Add(expiryDelta).After(t.Expiry)
+}
+
+// Valid reports whether the token can be used to authorise requests
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && !t.Expired()
+}
+
+// Error an error returned by the token endpoint
+type Error struct {
+	StatusCode  int
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf(
+		"oauth error httpCode:%d error:%s description:%s",
+		e.StatusCode,
+		e.Code,
+		e.Description,
+	)
+}
+
+// NewState generates a random state value used
+// to protect the callback against forgery
+func NewState() (state string, err error) {
+	b := make([]byte, 16)
+	_, err = rand.Read(b)
+	if err != nil {
+		return
+	}
+	state = hex.EncodeToString(b)
+
+	return
+}
+
+// AuthCodeURL the URL users are sent to in order
+// to authorise the application
+func (c *Config) AuthCodeURL(state string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", c.ClientID)
+	q.Set("state", state)
+	if len(c.Scopes) > 0 {
+		q.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	if c.RedirectURL != "" {
+		q.Set("redirect_uri", c.RedirectURL)
+	}
+
+	addr := c.AuthURL
+	if addr == "" {
+		addr = AuthURL
+	}
+
+	sep := "?"
+	if strings.Contains(addr, "?") {
+		sep = "&"
+	}
+
+	return addr + sep + q.Encode()
+}
+
+// Exchange exchanges an authorization code for a token
+func (c *Config) Exchange(
+	ctx context.Context,
+	code string,
+) (
+	token *Token,
+	err error,
+) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	if c.RedirectURL != "" {
+		form.Set("redirect_uri", c.RedirectURL)
+	}
+
+	return c.requestToken(ctx, form)
+}
+
+// Refresh obtains a new token using a refresh token
+func (c *Config) Refresh(
+	ctx context.Context,
+	refreshToken string,
+) (
+	token *Token,
+	err error,
+) {
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+
+	token, err = c.requestToken(ctx, form)
+	if err != nil {
+		return
+	}
+
+	// the refresh token is not always rotated
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+
+	return
+}
+
+func (c *Config) requestToken(
+	ctx context.Context,
+	form url.Values,
+) (
+	token *Token,
+	err error,
+) {
+	form.Set("client_id", c.ClientID)
+	form.Set("client_secret", c.ClientSecret)
+
+	addr := c.TokenURL
+	if addr == "" {
+		addr = TokenURL
+	}
+
+	req, err := http.NewRequest(http.MethodPost, addr, strings.NewReader(form.Encode()))
+	if err != nil {
+		err = fmt.Errorf("failed to construct request err:%s", err)
+		return
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		oauthErr := &Error{}
+		// bodies which are not an OAuth error fall back to the status text
+		if jsonErr := json.Unmarshal(data, oauthErr); jsonErr != nil || oauthErr.Code == "" {
+			oauthErr.Code = http.StatusText(resp.StatusCode)
+			if jsonErr != nil || oauthErr.Description == "" {
+				oauthErr.Description = strings.TrimSpace(string(data))
+			}
+		}
+		oauthErr.StatusCode = resp.StatusCode
+		err = oauthErr
+		return
+	}
+
+	raw := struct {
+		AccessToken  string `json:"access_token"`
+		TokenType    string `json:"token_type"`
+		RefreshToken string `json:"refresh_token"`
+		Scope        string `json:"scope"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}{}
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		return
+	}
+	if raw.AccessToken == "" {
+		err = fmt.Errorf("token response did not contain an access token")
+		return
+	}
+
+	token = &Token{
+		AccessToken:  raw.AccessToken,
+		TokenType:    raw.TokenType,
+		RefreshToken: raw.RefreshToken,
+		Scope:        raw.Scope,
+	}
+	if raw.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(raw.ExpiresIn) * time.Second)
+	}
+
+	return
+}
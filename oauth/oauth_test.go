@@ -0,0 +1,271 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTokenServer serves the token endpoint with the provided
+// handler after checking the client credentials
+func newTokenServer(t *testing.T, handler http.HandlerFunc) *Config {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("client_id") != "id" || r.PostForm.Get("client_secret") != "secret" {
+			t.Errorf("got client credentials %v", r.PostForm)
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	return &Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/callback",
+		TokenURL:     srv.URL,
+	}
+}
+
+func TestExchange(t *testing.T) {
+	config := newTokenServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.PostForm.Get("grant_type") != "authorization_code" ||
+			r.PostForm.Get("code") != "code" ||
+			r.PostForm.Get("redirect_uri") != "http://localhost/callback" {
+			t.Errorf("got form %v", r.PostForm)
+		}
+		fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh","token_type":"bearer","expires_in":3600}`)
+	})
+
+	token, err := config.Exchange(context.Background(), "code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" || token.TokenType != "bearer" {
+		t.Errorf("got token %+v", token)
+	}
+	if !token.Valid() || token.Expiry.IsZero() {
+		t.Errorf("got invalid token %+v", token)
+	}
+}
+
+func TestRequestTokenErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+		want   *Error
+		errMsg string
+	}{
+		{
+			name:   "oauth error",
+			status: http.StatusBadRequest,
+			body:   `{"error":"invalid_grant","error_description":"code expired"}`,
+			want:   &Error{StatusCode: 400, Code: "invalid_grant", Description: "code expired"},
+		},
+		{
+			name:   "html error page",
+			status: http.StatusBadGateway,
+			body:   "<html>Bad Gateway</html>\n",
+			want:   &Error{StatusCode: 502, Code: "Bad Gateway", Description: "<html>Bad Gateway</html>"},
+		},
+		{
+			name:   "empty body",
+			status: http.StatusUnauthorized,
+			want:   &Error{StatusCode: 401, Code: "Unauthorized"},
+		},
+		{
+			name:   "json without an error code",
+			status: http.StatusInternalServerError,
+			body:   `{"error_description":"try again later"}`,
+			want:   &Error{StatusCode: 500, Code: "Internal Server Error", Description: "try again later"},
+		},
+		{
+			name:   "missing access token",
+			status: http.StatusOK,
+			body:   `{"token_type":"bearer"}`,
+			errMsg: "token response did not contain an access token",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTokenServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				fmt.Fprint(w, test.body)
+			})
+
+			for _, call := range []func() (*Token, error){
+				func() (*Token, error) { return config.Exchange(context.Background(), "code") },
+				func() (*Token, error) { return config.Refresh(context.Background(), "refresh") },
+			} {
+				token, err := call()
+				if token != nil {
+					t.Errorf("got token %+v", token)
+				}
+				if test.errMsg != "" {
+					if err == nil || err.Error() != test.errMsg {
+						t.Errorf("got err %v, want %q", err, test.errMsg)
+					}
+					continue
+				}
+
+				var oauthErr *Error
+				if !errors.As(err, &oauthErr) {
+					t.Fatalf("got err %v, want *Error", err)
+				}
+				if *oauthErr != *test.want {
+					t.Errorf("got %+v, want %+v", oauthErr, test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "rotated refresh token",
+			body: `{"access_token":"access","refresh_token":"rotated"}`,
+			want: "rotated",
+		},
+		{
+			name: "refresh token kept",
+			body: `{"access_token":"access"}`,
+			want: "refresh",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := newTokenServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh" {
+					t.Errorf("got form %v", r.PostForm)
+				}
+				fmt.Fprint(w, test.body)
+			})
+
+			token, err := config.Refresh(context.Background(), "refresh")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if token.RefreshToken != test.want {
+				t.Errorf("got refresh token %q, want %q", token.RefreshToken, test.want)
+			}
+		})
+	}
+}
+
+func TestTokenSourceRefreshesExpiredTokens(t *testing.T) {
+	config := newTokenServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token":"fresh","expires_in":3600}`)
+	})
+	var refreshed *Token
+	config.OnRefresh = func(token *Token) {
+		refreshed = token
+	}
+
+	expired := &Token{AccessToken: "stale", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Hour)}
+	source := config.TokenSource(context.Background(), expired)
+
+	token, err := source.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "fresh" || refreshed == nil || refreshed.AccessToken != "fresh" {
+		t.Errorf("got token %q, refreshed %+v", token, refreshed)
+	}
+	if source.Current().RefreshToken != "refresh" {
+		t.Errorf("got refresh token %q", source.Current().RefreshToken)
+	}
+}
+
+func TestCallbackHandler(t *testing.T) {
+	config := newTokenServer(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"access_token":"access"}`)
+	})
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+		token  string
+		err    string
+	}{
+		{
+			name:   "success",
+			query:  "state=state&code=code",
+			status: http.StatusOK,
+			token:  "access",
+		},
+		{
+			name:   "state mismatch",
+			query:  "state=forged&code=code",
+			status: http.StatusBadRequest,
+			err:    "callback state mismatch",
+		},
+		{
+			name:   "missing state",
+			query:  "code=code",
+			status: http.StatusBadRequest,
+			err:    "callback state mismatch",
+		},
+		{
+			name:   "missing code",
+			query:  "state=state",
+			status: http.StatusBadRequest,
+			err:    "callback did not contain an authorization code",
+		},
+		{
+			name:   "authorization denied",
+			query:  "error=access_denied&error_description=denied",
+			status: http.StatusBadRequest,
+			err:    "oauth error httpCode:400 error:access_denied description:denied",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			called := false
+			handler := config.CallbackHandler("state", func(token *Token, err error) {
+				called = true
+				if test.err != "" {
+					if err == nil || err.Error() != test.err {
+						t.Errorf("got err %v, want %q", err, test.err)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if token.AccessToken != test.token {
+					t.Errorf("got token %q, want %q", token.AccessToken, test.token)
+				}
+			})
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/callback?"+test.query, nil))
+			if !called {
+				t.Error("callback fn was not called")
+			}
+			if w.Code != test.status {
+				t.Errorf("got status %d, want %d", w.Code, test.status)
+			}
+			if test.status == http.StatusOK && !strings.Contains(w.Body.String(), "Authorization complete") {
+				t.Errorf("got body %q", w.Body.String())
+			}
+		})
+	}
+}
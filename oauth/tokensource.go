@@ -0,0 +1,69 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// TokenSource a glo.TokenSource which refreshes
+// its token when it has expired
+type TokenSource struct {
+	ctx    context.Context
+	config *Config
+
+	mu    sync.Mutex
+	token *Token
+}
+
+var _ glo.TokenSource = (*TokenSource)(nil)
+
+// TokenSource creates a TokenSource starting from the provided token
+//
+//	client := glo.NewClientWithTokenSource(config.TokenSource(ctx, token))
+func (c *Config) TokenSource(ctx context.Context, token *Token) *TokenSource {
+	return &TokenSource{
+		ctx:    ctx,
+		config: c,
+		token:  token,
+	}
+}
+
+// Token returns a valid access token, refreshing it if required
+func (s *TokenSource) Token() (token string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token.Valid() {
+		token = s.token.AccessToken
+		return
+	}
+
+	if s.token == nil || s.token.RefreshToken == "" {
+		err = fmt.Errorf("token expired and no refresh token is available")
+		return
+	}
+
+	refreshed, err := s.config.Refresh(s.ctx, s.token.RefreshToken)
+	if err != nil {
+		return
+	}
+	s.token = refreshed
+
+	if s.config.OnRefresh != nil {
+		s.config.OnRefresh(refreshed)
+	}
+	token = refreshed.AccessToken
+
+	return
+}
+
+// Current returns the token currently held by the source
+func (s *TokenSource) Current() *Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.token
+}
@@ -0,0 +1,231 @@
+package outbox
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Op a queued mutation
+type Op string
+
+// Queued mutations
+const (
+	OpCreateColumn  Op = "create_column"
+	OpEditColumn    Op = "edit_column"
+	OpDeleteColumn  Op = "delete_column"
+	OpCreateCard    Op = "create_card"
+	OpEditCard      Op = "edit_card"
+	OpDeleteCard    Op = "delete_card"
+	OpCreateComment Op = "create_comment"
+	OpEditComment   Op = "edit_comment"
+	OpDeleteComment Op = "delete_comment"
+)
+
+// Entry a mutation waiting to be replayed
+type Entry struct {
+	Seq       int64  `json:"seq"`
+	Op        Op     `json:"op"`
+	BoardID   string `json:"board_id"`
+	ColumnID  string `json:"column_id,omitempty"`
+	CardID    string `json:"card_id,omitempty"`
+	CommentID string `json:"comment_id,omitempty"`
+
+	// TempID the temporary ID returned when a create was queued
+	TempID string `json:"temp_id,omitempty"`
+
+	// LastSeen the UpdatedDate of the card an edit or delete was
+	// based on, the change conflicts when the card has since changed
+	LastSeen string `json:"last_seen,omitempty"`
+
+	Input    json.RawMessage `json:"input,omitempty"`
+	QueuedAt time.Time       `json:"queued_at"`
+
+	// Attempts the number of replays the API failed to serve
+	// and Error the error of the last of them
+	Attempts int    `json:"attempts,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// record a line of the journal
+type record struct {
+	Entry *Entry `json:"entry,omitempty"`
+
+	// Done the sequence number of a replayed or discarded entry
+	Done int64 `json:"done,omitempty"`
+
+	// Temp and ID map a temporary ID to the ID it was created with
+	Temp string `json:"temp,omitempty"`
+	ID   string `json:"id,omitempty"`
+
+	// Attempt the sequence number of an entry the API failed to
+	// replay with Error, Failed of an entry which is no longer replayed
+	Attempt int64  `json:"attempt,omitempty"`
+	Failed  int64  `json:"failed,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// journal an append-only file of records, entries are durable
+// once appended and marked done once replayed
+type journal struct {
+	path string
+}
+
+// load reads the pending and failed entries and ID mappings,
+// a final line torn by a crash while appending is ignored
+func (j *journal) load() (
+	pending []*Entry,
+	failed []*Entry,
+	ids map[string]string,
+	seq int64,
+	err error,
+) {
+	ids = map[string]string{}
+
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil, ids, 0, nil
+	}
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var entries []*Entry
+	done := map[int64]bool{}
+	failures := map[int64]bool{}
+	attempts := map[int64][]string{}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var bad error
+	for line := 1; scanner.Scan(); line++ {
+		if bad != nil {
+			err = bad
+			return
+		}
+
+		rec := &record{}
+		if jsonErr := json.Unmarshal(scanner.Bytes(), rec); jsonErr != nil {
+			bad = fmt.Errorf("%s:%d: corrupt journal record err:%s", j.path, line, jsonErr)
+			continue
+		}
+
+		switch {
+		case rec.Entry != nil:
+			entries = append(entries, rec.Entry)
+			if rec.Entry.Seq > seq {
+				seq = rec.Entry.Seq
+			}
+		case rec.Done != 0:
+			done[rec.Done] = true
+		case rec.Temp != "":
+			ids[rec.Temp] = rec.ID
+		case rec.Attempt != 0:
+			attempts[rec.Attempt] = append(attempts[rec.Attempt], rec.Error)
+		case rec.Failed != 0:
+			failures[rec.Failed] = true
+		}
+	}
+	err = scanner.Err()
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if errs := attempts[entry.Seq]; len(errs) > 0 {
+			entry.Attempts += len(errs)
+			entry.Error = errs[len(errs)-1]
+		}
+
+		switch {
+		case done[entry.Seq]:
+		case failures[entry.Seq]:
+			failed = append(failed, entry)
+		default:
+			pending = append(pending, entry)
+		}
+	}
+
+	return
+}
+
+// append writes records and syncs them to disk
+func (j *journal) append(records ...*record) (err error) {
+	err = os.MkdirAll(filepath.Dir(j.path), 0700)
+	if err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(j.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	var data []byte
+	for _, rec := range records {
+		line, marshalErr := json.Marshal(rec)
+		if marshalErr != nil {
+			return marshalErr
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	_, err = f.Write(data)
+	if err != nil {
+		return
+	}
+
+	return f.Sync()
+}
+
+// compact rewrites the journal with only the pending and failed
+// entries and the ID mappings, replacing it atomically
+func (j *journal) compact(pending []*Entry, failed []*Entry, ids map[string]string) (err error) {
+	var data []byte
+	for temp, id := range ids {
+		line, marshalErr := json.Marshal(&record{Temp: temp, ID: id})
+		if marshalErr != nil {
+			return marshalErr
+		}
+		data = append(append(data, line...), '\n')
+	}
+	for _, entry := range pending {
+		line, marshalErr := json.Marshal(&record{Entry: entry})
+		if marshalErr != nil {
+			return marshalErr
+		}
+		data = append(append(data, line...), '\n')
+	}
+	for _, entry := range failed {
+		for _, rec := range []*record{{Entry: entry}, {Failed: entry.Seq}} {
+			line, marshalErr := json.Marshal(rec)
+			if marshalErr != nil {
+				return marshalErr
+			}
+			data = append(append(data, line...), '\n')
+		}
+	}
+
+	err = os.MkdirAll(filepath.Dir(j.path), 0700)
+	if err != nil {
+		return
+	}
+
+	tmp := j.path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp, j.path)
+}
@@ -0,0 +1,552 @@
+// Package outbox queues mutations in a local journal while the
+// Glo API is unreachable and replays them, in order, once it can
+// be reached again.
+//
+// Cards, columns and comments created while offline are given
+// temporary IDs which may be used by later queued mutations, they
+// are replaced by the created IDs during replay. Card edits and
+// deletes record the UpdatedDate of the card they were based on,
+// a card which has since changed is reported as a conflict rather
+// than overwritten.
+//
+// Entries are replayed at least once, a crash between a request
+// succeeding and the entry being marked done replays it again. An
+// entry the API repeatedly fails to serve is marked failed, rather
+// than blocking the entries after it, and kept until discarded.
+package outbox
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// TempIDPrefix prefixes the temporary IDs of items created while offline
+const TempIDPrefix = "tmp-"
+
+// NewTempID generates a temporary ID
+func NewTempID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+
+	return TempIDPrefix + hex.EncodeToString(b)
+}
+
+// IsTempID reports whether an ID is a temporary ID
+func IsTempID(id string) bool {
+	return strings.HasPrefix(id, TempIDPrefix)
+}
+
+// DefaultPath the journal path, alongside the config file
+func DefaultPath() (path string, err error) {
+	config, err := glo.ConfigPath()
+	if err != nil {
+		return
+	}
+	path = filepath.Join(filepath.Dir(config), "outbox.jsonl")
+
+	return
+}
+
+// Offline reports whether an error indicates the API could not be
+// reached or could not serve the request, such requests are queued
+func Offline(err error) bool {
+	switch glo.ErrorClass(err) {
+	case glo.ErrorClassTransport, glo.ErrorClassServer, glo.ErrorClassRateLimited:
+		return true
+	}
+
+	return false
+}
+
+// Outbox applies mutations through the client, queueing them
+// when the API is unreachable or earlier mutations are queued
+type Outbox struct {
+	Client *glo.Glo
+
+	journal *journal
+
+	mu      sync.Mutex
+	pending []*Entry
+	failed  []*Entry
+	ids     map[string]string
+	seq     int64
+}
+
+// Open opens the journal, creating it when it does not exist
+func Open(client *glo.Glo, path string) (o *Outbox, err error) {
+	o = &Outbox{
+		Client:  client,
+		journal: &journal{path: path},
+	}
+
+	o.pending, o.failed, o.ids, o.seq, err = o.journal.load()
+	if err != nil {
+		return
+	}
+
+	// drop the records of replayed entries
+	if _, statErr := os.Stat(path); statErr == nil {
+		err = o.journal.compact(o.pending, o.failed, o.ids)
+	}
+
+	return
+}
+
+// Pending the queued entries, in replay order
+func (o *Outbox) Pending() []*Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]*Entry(nil), o.pending...)
+}
+
+// Failed the entries the API failed to replay too many times,
+// they are no longer replayed and remain until discarded
+func (o *Outbox) Failed() []*Entry {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return append([]*Entry(nil), o.failed...)
+}
+
+// Len the number of queued entries
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	return len(o.pending)
+}
+
+// Resolve resolves a temporary ID to the ID it was created with,
+// other IDs and temporary IDs which are still queued are returned as is
+func (o *Outbox) Resolve(id string) string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if mapped, ok := o.ids[id]; ok {
+		return mapped
+	}
+
+	return id
+}
+
+// Discard removes a queued or failed entry without replaying it
+func (o *Outbox) Discard(seq int64) (err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for _, entries := range []*[]*Entry{&o.pending, &o.failed} {
+		for i, entry := range *entries {
+			if entry.Seq != seq {
+				continue
+			}
+
+			err = o.journal.append(&record{Done: seq})
+			if err != nil {
+				return
+			}
+			*entries = append((*entries)[:i:i], (*entries)[i+1:]...)
+
+			return
+		}
+	}
+
+	return fmt.Errorf("no queued entry %d", seq)
+}
+
+// CreateColumn creates a column, a column with a temporary ID is
+// returned when the request is queued
+func (o *Outbox) CreateColumn(
+	boardID string,
+	input *glo.ColumnInput,
+) (
+	col *glo.Column,
+	queued bool,
+	err error,
+) {
+	entry := &Entry{Op: OpCreateColumn, BoardID: boardID}
+	result, queued, err := o.submit(entry, input)
+	if err != nil {
+		return
+	}
+
+	if queued {
+		col = &glo.Column{ID: entry.TempID, Name: input.Name, Position: input.Position}
+		return
+	}
+	col = result.(*glo.Column)
+
+	return
+}
+
+// EditColumn edits a column, nil is returned when the request is queued
+func (o *Outbox) EditColumn(
+	boardID string,
+	columnID string,
+	input *glo.ColumnInput,
+) (
+	col *glo.Column,
+	queued bool,
+	err error,
+) {
+	entry := &Entry{Op: OpEditColumn, BoardID: boardID, ColumnID: columnID}
+	result, queued, err := o.submit(entry, input)
+	if err == nil && !queued {
+		col = result.(*glo.Column)
+	}
+
+	return
+}
+
+// DeleteColumn deletes a column
+func (o *Outbox) DeleteColumn(boardID, columnID string) (queued bool, err error) {
+	entry := &Entry{Op: OpDeleteColumn, BoardID: boardID, ColumnID: columnID}
+	_, queued, err = o.submit(entry, nil)
+
+	return
+}
+
+// CreateCard creates a card, a card with a temporary ID is
+// returned when the request is queued
+func (o *Outbox) CreateCard(
+	boardID string,
+	input *glo.CardsInput,
+) (
+	card *glo.Card,
+	queued bool,
+	err error,
+) {
+	entry := &Entry{Op: OpCreateCard, BoardID: boardID, ColumnID: input.ColumnID}
+	result, queued, err := o.submit(entry, input)
+	if err != nil {
+		return
+	}
+
+	if queued {
+		card = &glo.Card{
+			ID:        entry.TempID,
+			BoardID:   boardID,
+			Name:      input.Name,
+			Position:  input.Position,
+			ColumnID:  input.ColumnID,
+			Assignees: input.Assignees,
+			Labels:    input.Labels,
+			DueDate:   input.DueDate,
+		}
+		if input.Description != nil {
+			card.Description = &glo.Description{Text: input.Description.Text}
+		}
+		return
+	}
+	card = result.(*glo.Card)
+
+	return
+}
+
+// EditCard edits a card, lastSeen is the UpdatedDate of the card the
+// edit was based on, it conflicts when the card has since been updated,
+// an empty lastSeen always overwrites the card. nil is returned when
+// the request is queued
+func (o *Outbox) EditCard(
+	boardID string,
+	cardID string,
+	input *glo.CardsInput,
+	lastSeen string,
+) (
+	card *glo.Card,
+	queued bool,
+	err error,
+) {
+	entry := &Entry{Op: OpEditCard, BoardID: boardID, CardID: cardID, LastSeen: lastSeen}
+	result, queued, err := o.submit(entry, input)
+	if err == nil && !queued {
+		card = result.(*glo.Card)
+	}
+
+	return
+}
+
+// DeleteCard deletes a card, lastSeen is as for EditCard
+func (o *Outbox) DeleteCard(boardID, cardID, lastSeen string) (queued bool, err error) {
+	entry := &Entry{Op: OpDeleteCard, BoardID: boardID, CardID: cardID, LastSeen: lastSeen}
+	_, queued, err = o.submit(entry, nil)
+
+	return
+}
+
+// CreateComment creates a comment, a comment with a temporary ID
+// is returned when the request is queued
+func (o *Outbox) CreateComment(
+	boardID string,
+	cardID string,
+	input *glo.CommentInput,
+) (
+	comment *glo.Comment,
+	queued bool,
+	err error,
+) {
+	entry := &Entry{Op: OpCreateComment, BoardID: boardID, CardID: cardID}
+	result, queued, err := o.submit(entry, input)
+	if err != nil {
+		return
+	}
+
+	if queued {
+		comment = &glo.Comment{ID: entry.TempID, BoardID: boardID, CardID: cardID, Text: input.Text}
+		return
+	}
+	comment = result.(*glo.Comment)
+
+	return
+}
+
+// EditComment edits a comment, nil is returned when the request is queued
+func (o *Outbox) EditComment(
+	boardID string,
+	cardID string,
+	commentID string,
+	input *glo.CommentInput,
+) (
+	comment *glo.Comment,
+	queued bool,
+	err error,
+) {
+	entry := &Entry{Op: OpEditComment, BoardID: boardID, CardID: cardID, CommentID: commentID}
+	result, queued, err := o.submit(entry, input)
+	if err == nil && !queued {
+		comment = result.(*glo.Comment)
+	}
+
+	return
+}
+
+// DeleteComment deletes a comment
+func (o *Outbox) DeleteComment(boardID, cardID, commentID string) (queued bool, err error) {
+	entry := &Entry{Op: OpDeleteComment, BoardID: boardID, CardID: cardID, CommentID: commentID}
+	_, queued, err = o.submit(entry, nil)
+
+	return
+}
+
+// submit applies a mutation directly when nothing is queued ahead of
+// it and the API is reachable, otherwise the mutation is queued
+func (o *Outbox) submit(entry *Entry, input interface{}) (result interface{}, queued bool, err error) {
+	if input != nil {
+		entry.Input, err = json.Marshal(input)
+		if err != nil {
+			return
+		}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if len(o.pending) == 0 {
+		var resolved *Entry
+		resolved, err = o.resolve(entry)
+		if err != nil {
+			return
+		}
+
+		result, err = o.apply(resolved, false)
+		if !Offline(err) {
+			return
+		}
+	}
+
+	err = o.enqueue(entry)
+	queued = err == nil
+
+	return
+}
+
+// enqueue appends a mutation to the journal
+func (o *Outbox) enqueue(entry *Entry) (err error) {
+	entry.Seq = o.seq + 1
+	entry.QueuedAt = time.Now().UTC()
+	switch entry.Op {
+	case OpCreateColumn, OpCreateCard, OpCreateComment:
+		entry.TempID = NewTempID()
+	}
+
+	// an earlier queued change to the card updates it before this
+	// one is replayed, so only the earliest detects conflicts
+	if entry.LastSeen != "" {
+		for _, queued := range o.pending {
+			if queued.CardID == entry.CardID && queued.Op != OpCreateComment {
+				entry.LastSeen = ""
+				break
+			}
+		}
+	}
+
+	err = o.journal.append(&record{Entry: entry})
+	if err != nil {
+		return
+	}
+	o.seq = entry.Seq
+	o.pending = append(o.pending, entry)
+
+	return
+}
+
+// resolve copies an entry, replacing the temporary IDs it references
+func (o *Outbox) resolve(entry *Entry) (resolved *Entry, err error) {
+	copied := *entry
+	resolved = &copied
+
+	for _, id := range []*string{&resolved.ColumnID, &resolved.CardID, &resolved.CommentID} {
+		*id, err = o.resolveID(*id)
+		if err != nil {
+			return
+		}
+	}
+
+	// cards reference their column within the input
+	if resolved.Op == OpCreateCard || resolved.Op == OpEditCard {
+		input := &glo.CardsInput{}
+		err = json.Unmarshal(resolved.Input, input)
+		if err != nil {
+			return
+		}
+		if !IsTempID(input.ColumnID) {
+			return
+		}
+
+		input.ColumnID, err = o.resolveID(input.ColumnID)
+		if err != nil {
+			return
+		}
+		resolved.Input, err = json.Marshal(input)
+	}
+
+	return
+}
+
+func (o *Outbox) resolveID(id string) (string, error) {
+	if !IsTempID(id) {
+		return id, nil
+	}
+	if mapped, ok := o.ids[id]; ok {
+		return mapped, nil
+	}
+
+	return "", fmt.Errorf("%s has not been created, its create is queued or was discarded", id)
+}
+
+// apply performs a mutation whose IDs have been resolved, the card's
+// UpdatedDate is checked first unless the conflict is being overwritten
+func (o *Outbox) apply(entry *Entry, overwrite bool) (result interface{}, err error) {
+	if entry.LastSeen != "" && !overwrite {
+		err = o.checkConflict(entry)
+		if err != nil {
+			return
+		}
+	}
+
+	c := o.Client
+	switch entry.Op {
+	case OpCreateColumn, OpEditColumn:
+		input := &glo.ColumnInput{}
+		err = json.Unmarshal(entry.Input, input)
+		if err != nil {
+			return
+		}
+		if entry.Op == OpCreateColumn {
+			return c.CreateColumn(entry.BoardID, input)
+		}
+		return c.EditColumn(entry.BoardID, entry.ColumnID, input)
+
+	case OpDeleteColumn:
+		err = c.DeteleColumn(entry.BoardID, entry.ColumnID)
+
+	case OpCreateCard, OpEditCard:
+		input := &glo.CardsInput{}
+		err = json.Unmarshal(entry.Input, input)
+		if err != nil {
+			return
+		}
+		if entry.Op == OpCreateCard {
+			return c.CreateCard(entry.BoardID, input)
+		}
+		return c.EditCard(entry.BoardID, entry.CardID, input)
+
+	case OpDeleteCard:
+		err = c.DeleteCard(entry.BoardID, entry.CardID)
+
+	case OpCreateComment, OpEditComment:
+		input := &glo.CommentInput{}
+		err = json.Unmarshal(entry.Input, input)
+		if err != nil {
+			return
+		}
+		if entry.Op == OpCreateComment {
+			return c.CreateComment(entry.BoardID, entry.CardID, input)
+		}
+		return c.EditComment(entry.BoardID, entry.CardID, entry.CommentID, input)
+
+	case OpDeleteComment:
+		err = c.DeleteComment(entry.BoardID, entry.CardID, entry.CommentID)
+
+	default:
+		err = fmt.Errorf("unsupported operation %q", entry.Op)
+	}
+
+	return
+}
+
+// checkConflict fetches the card an entry is based on
+func (o *Outbox) checkConflict(entry *Entry) (err error) {
+	current, err := o.Client.GetCard(
+		entry.BoardID,
+		entry.CardID,
+		glo.Fields(glo.CardFieldName, glo.CardFieldUpdatedDate),
+	)
+	var statusErr *glo.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return &ConflictError{Entry: entry}
+	}
+	if err != nil {
+		return
+	}
+	current.ID = entry.CardID
+
+	if current.UpdatedDate != entry.LastSeen {
+		err = &ConflictError{Entry: entry, Current: current}
+	}
+
+	return
+}
+
+// ConflictError returned when a card has changed since
+// the change to it was made
+type ConflictError struct {
+	Entry *Entry
+
+	// Current the card as it is now, nil when it has been deleted
+	Current *glo.Card
+}
+
+func (e *ConflictError) Error() string {
+	if e.Current == nil {
+		return fmt.Sprintf("card %s no longer exists", e.Entry.CardID)
+	}
+
+	return fmt.Sprintf(
+		"card %s was updated at %s after the change was made at %s",
+		e.Entry.CardID,
+		e.Current.UpdatedDate,
+		e.Entry.LastSeen,
+	)
+}
@@ -0,0 +1,494 @@
+package outbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// fakeAPI serves columns, cards and comments, every request fails
+// with a server error while offline and requests for the broken
+// path always do, the mutations made are recorded in order
+type fakeAPI struct {
+	mu       sync.Mutex
+	offline  bool
+	broken   string
+	nextID   int
+	columns  map[string]*glo.Column
+	cards    map[string]*glo.Card
+	comments map[string]*glo.Comment
+	requests []string
+}
+
+func newFakeAPI() *fakeAPI {
+	return &fakeAPI{
+		columns:  map[string]*glo.Column{"todo": {ID: "todo", Name: "Todo"}},
+		cards:    map[string]*glo.Card{"c1": {ID: "c1", Name: "Valve", ColumnID: "todo", UpdatedDate: "t1"}},
+		comments: map[string]*glo.Comment{},
+	}
+}
+
+func (f *fakeAPI) id(prefix string) string {
+	f.nextID++
+	return fmt.Sprintf("%s%d", prefix, f.nextID)
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.offline || r.URL.Path == f.broken {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if r.Method != http.MethodGet {
+		f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	var v interface{}
+	switch {
+	case len(parts) == 3 && parts[2] == "columns" && r.Method == http.MethodPost:
+		input := &glo.ColumnInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		col := &glo.Column{ID: f.id("col"), Name: input.Name}
+		f.columns[col.ID] = col
+		v = col
+	case len(parts) == 4 && parts[2] == "columns" && r.Method == http.MethodDelete:
+		if f.columns[parts[3]] != nil {
+			delete(f.columns, parts[3])
+			v = struct{}{}
+		}
+	case len(parts) == 3 && parts[2] == "cards" && r.Method == http.MethodPost:
+		input := &glo.CardsInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		card := &glo.Card{ID: f.id("card"), Name: input.Name, ColumnID: input.ColumnID, UpdatedDate: "t1"}
+		f.cards[card.ID] = card
+		v = card
+	case len(parts) == 4 && parts[2] == "cards":
+		card := f.cards[parts[3]]
+		if card == nil {
+			break
+		}
+		switch r.Method {
+		case http.MethodPost:
+			input := &glo.CardsInput{}
+			json.NewDecoder(r.Body).Decode(input)
+			card.Name = input.Name
+			card.UpdatedDate = f.id("t")
+		case http.MethodDelete:
+			delete(f.cards, card.ID)
+		}
+		v = card
+	case len(parts) == 5 && parts[4] == "comments" && f.cards[parts[3]] != nil:
+		input := &glo.CommentInput{}
+		json.NewDecoder(r.Body).Decode(input)
+		comment := &glo.Comment{ID: f.id("comment"), CardID: parts[3], Text: input.Text}
+		f.comments[comment.ID] = comment
+		v = comment
+	case len(parts) == 6 && parts[4] == "comments" && r.Method == http.MethodDelete:
+		if f.comments[parts[5]] != nil {
+			delete(f.comments, parts[5])
+			v = struct{}{}
+		}
+	}
+	if v == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	json.NewEncoder(w).Encode(v)
+}
+
+func (f *fakeAPI) setOffline(offline bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.offline = offline
+}
+
+func newClient(t *testing.T, fake *fakeAPI) *glo.Glo {
+	t.Helper()
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client := glo.NewClient("token")
+	client.BaseURI = srv.URL
+
+	return client
+}
+
+func open(t *testing.T, client *glo.Glo, path string) *Outbox {
+	t.Helper()
+
+	o, err := Open(client, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return o
+}
+
+func TestQueueAndReplay(t *testing.T) {
+	fake := newFakeAPI()
+	client := newClient(t, fake)
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := open(t, client, path)
+
+	// applied directly while online
+	card, queued, err := o.CreateCard("b1", &glo.CardsInput{Name: "Online", ColumnID: "todo"})
+	if err != nil || queued || card.ID != "card1" {
+		t.Fatalf("got card %+v queued:%t err:%v", card, queued, err)
+	}
+
+	fake.setOffline(true)
+	col, queued, err := o.CreateColumn("b1", &glo.ColumnInput{Name: "Doing"})
+	if err != nil || !queued || !IsTempID(col.ID) {
+		t.Fatalf("got column %+v queued:%t err:%v", col, queued, err)
+	}
+	card, queued, err = o.CreateCard("b1", &glo.CardsInput{Name: "Offline", ColumnID: col.ID})
+	if err != nil || !queued || !IsTempID(card.ID) || card.ColumnID != col.ID {
+		t.Fatalf("got card %+v queued:%t err:%v", card, queued, err)
+	}
+	comment, queued, err := o.CreateComment("b1", card.ID, &glo.CommentInput{Text: "parts ordered"})
+	if err != nil || !queued || !IsTempID(comment.ID) {
+		t.Fatalf("got comment %+v queued:%t err:%v", comment, queued, err)
+	}
+
+	// queued behind the earlier entries although the API is reachable
+	fake.setOffline(false)
+	_, queued, err = o.EditCard("b1", "c1", &glo.CardsInput{Name: "Valve 2"}, "t1")
+	if err != nil || !queued {
+		t.Fatalf("edit was not queued queued:%t err:%v", queued, err)
+	}
+	if o.Len() != 4 {
+		t.Fatalf("got %d queued, want 4", o.Len())
+	}
+
+	// the queue survives reopening the journal
+	o = open(t, client, path)
+	if o.Len() != 4 {
+		t.Fatalf("got %d queued after reopening, want 4", o.Len())
+	}
+
+	result, err := o.Replay(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Replayed != 4 || result.Remaining != 0 {
+		t.Errorf("got result %+v", result)
+	}
+
+	colID, cardID := o.Resolve(col.ID), o.Resolve(card.ID)
+	want := []string{
+		"POST /boards/b1/cards",
+		"POST /boards/b1/columns",
+		"POST /boards/b1/cards",
+		"POST /boards/b1/cards/" + cardID + "/comments",
+		"POST /boards/b1/cards/c1",
+	}
+	if strings.Join(fake.requests, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got requests\n%s\nwant\n%s", strings.Join(fake.requests, "\n"), strings.Join(want, "\n"))
+	}
+	if fake.cards[cardID] == nil || fake.cards[cardID].ColumnID != colID || IsTempID(colID) {
+		t.Errorf("card %s was created in column %s", cardID, colID)
+	}
+	if fake.cards["c1"].Name != "Valve 2" {
+		t.Errorf("card was not edited")
+	}
+
+	// created IDs still resolve once the journal is compacted
+	o = open(t, client, path)
+	if o.Len() != 0 || o.Resolve(card.ID) != cardID {
+		t.Errorf("got %d queued and %s resolved to %s", o.Len(), card.ID, o.Resolve(card.ID))
+	}
+}
+
+func TestReplayConflicts(t *testing.T) {
+	tests := []struct {
+		name       string
+		resolution *Resolution
+		replayed   int
+		skipped    int
+		remaining  int
+		cardName   string
+	}{
+		{name: "no resolver stops", replayed: 0, remaining: 2, cardName: "Theirs"},
+		{name: "stop", resolution: resolution(Stop), replayed: 0, remaining: 2, cardName: "Theirs"},
+		{name: "skip", resolution: resolution(Skip), replayed: 1, skipped: 1, cardName: "Theirs"},
+		{name: "overwrite", resolution: resolution(Overwrite), replayed: 2, cardName: "Mine"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fake := newFakeAPI()
+			o := open(t, newClient(t, fake), filepath.Join(t.TempDir(), "outbox.jsonl"))
+
+			fake.setOffline(true)
+			if _, _, err := o.EditCard("b1", "c1", &glo.CardsInput{Name: "Mine"}, "t1"); err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err := o.CreateComment("b1", "c1", &glo.CommentInput{Text: "done"}); err != nil {
+				t.Fatal(err)
+			}
+
+			// the card changes before the queue is replayed
+			fake.offline = false
+			fake.cards["c1"].Name = "Theirs"
+			fake.cards["c1"].UpdatedDate = "t2"
+
+			opts := &ReplayOptions{}
+			var conflicts []*ConflictError
+			if test.resolution != nil {
+				opts.OnConflict = func(conflict *ConflictError) Resolution {
+					conflicts = append(conflicts, conflict)
+					return *test.resolution
+				}
+			}
+
+			result, err := o.Replay(opts)
+			if result.Replayed != test.replayed || result.Skipped != test.skipped || result.Remaining != test.remaining {
+				t.Errorf("got result %+v", result)
+			}
+			if fake.cards["c1"].Name != test.cardName {
+				t.Errorf("card named %q, want %q", fake.cards["c1"].Name, test.cardName)
+			}
+			if test.resolution != nil && (len(conflicts) != 1 || conflicts[0].Current.UpdatedDate != "t2") {
+				t.Errorf("got conflicts %v", conflicts)
+			}
+
+			var conflict *ConflictError
+			if test.remaining > 0 {
+				var replayErr *ReplayError
+				if !errors.As(err, &replayErr) || replayErr.Entry.Seq != 1 || !errors.As(err, &conflict) {
+					t.Fatalf("got err %v, want a conflict replaying entry 1", err)
+				}
+				if conflict.Current == nil || conflict.Current.Name != "Theirs" {
+					t.Errorf("got conflict %+v", conflict)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func resolution(r Resolution) *Resolution {
+	return &r
+}
+
+func TestReplayDeletesMissing(t *testing.T) {
+	fake := newFakeAPI()
+	fake.comments["comment1"] = &glo.Comment{ID: "comment1", CardID: "c1"}
+	o := open(t, newClient(t, fake), filepath.Join(t.TempDir(), "outbox.jsonl"))
+
+	fake.setOffline(true)
+	for _, queue := range []func() (bool, error){
+		func() (bool, error) { return o.DeleteCard("b1", "c1", "t1") },
+		func() (bool, error) { return o.DeleteComment("b1", "c1", "comment1") },
+		func() (bool, error) { return o.DeleteColumn("b1", "gone") },
+		func() (bool, error) { return o.DeleteCard("b1", "gone", "") },
+	} {
+		if queued, err := queue(); err != nil || !queued {
+			t.Fatalf("delete was not queued queued:%t err:%v", queued, err)
+		}
+	}
+
+	// the card and its comment are deleted before the queue is replayed
+	fake.offline = false
+	delete(fake.cards, "c1")
+	delete(fake.comments, "comment1")
+
+	result, err := o.Replay(&ReplayOptions{
+		OnConflict: func(conflict *ConflictError) Resolution {
+			t.Errorf("got conflict %v", conflict)
+			return Stop
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Replayed != 4 || result.Remaining != 0 {
+		t.Errorf("got result %+v", result)
+	}
+
+	// a delete of a missing item is still an error when applied directly
+	_, err = o.DeleteCard("b1", "gone", "")
+	var statusErr *glo.StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("got err %v, want not found", err)
+	}
+}
+
+func TestReplayMaxAttempts(t *testing.T) {
+	fake := newFakeAPI()
+	client := newClient(t, fake)
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := open(t, client, path)
+
+	fake.setOffline(true)
+	col, _, err := o.CreateColumn("b1", &glo.ColumnInput{Name: "Doing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = o.EditCard("b1", "c1", &glo.CardsInput{Name: "Mine"}, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = o.CreateComment("b1", "c1", &glo.CommentInput{Text: "after"}); err != nil {
+		t.Fatal(err)
+	}
+	fake.offline = false
+	fake.broken = "/boards/b1/cards/c1"
+
+	opts := &ReplayOptions{MaxAttempts: 2}
+	result, err := o.Replay(opts)
+	if glo.ErrorClass(err) != glo.ErrorClassServer {
+		t.Fatalf("got err %v, want a server error", err)
+	}
+	if result.Replayed != 1 || result.Failed != 0 || result.Remaining != 2 {
+		t.Errorf("got result %+v", result)
+	}
+	if pending := o.Pending(); pending[0].Attempts != 1 || pending[0].Error == "" {
+		t.Errorf("got entry %+v", pending[0])
+	}
+
+	// attempts are counted across reopening the journal
+	o = open(t, client, path)
+	result, err = o.Replay(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Replayed != 1 || result.Failed != 1 || result.Remaining != 0 {
+		t.Errorf("got result %+v", result)
+	}
+	if len(fake.comments) != 1 {
+		t.Errorf("the entry after the failed one was not replayed")
+	}
+
+	// compaction keeps the created IDs and the failed entry
+	o = open(t, client, path)
+	failed := o.Failed()
+	if o.Len() != 0 || len(failed) != 1 || failed[0].Op != OpEditCard || failed[0].Attempts != 2 {
+		t.Fatalf("got %d pending and failed %+v", o.Len(), failed)
+	}
+	if IsTempID(o.Resolve(col.ID)) {
+		t.Errorf("column %s no longer resolves", col.ID)
+	}
+	if lines := journalLines(t, path); len(lines) != 4 {
+		t.Errorf("got compacted journal\n%s", strings.Join(lines, "\n"))
+	}
+
+	// failed entries are no longer replayed
+	result, err = o.Replay(opts)
+	if err != nil || result.Replayed != 0 || result.Failed != 0 || len(o.Failed()) != 1 {
+		t.Errorf("got result %+v err:%v", result, err)
+	}
+
+	err = o.Discard(failed[0].Seq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o = open(t, client, path); len(o.Failed()) != 0 {
+		t.Errorf("discarded entry is still failed")
+	}
+	if err = o.Discard(failed[0].Seq); err == nil {
+		t.Error("discarded an entry twice")
+	}
+}
+
+func TestOpenTornJournal(t *testing.T) {
+	fake := newFakeAPI()
+	client := newClient(t, fake)
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	o := open(t, client, path)
+
+	fake.setOffline(true)
+	for _, name := range []string{"one", "two"} {
+		if _, _, err := o.CreateCard("b1", &glo.CardsInput{Name: name, ColumnID: "todo"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// a crash while appending the third entry
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"entry":{"seq":3,"op":"create_ca`)
+	f.Close()
+
+	o = open(t, client, path)
+	if o.Len() != 2 {
+		t.Fatalf("got %d queued, want 2", o.Len())
+	}
+	for _, line := range journalLines(t, path) {
+		if !json.Valid([]byte(line)) {
+			t.Errorf("torn line was kept %q", line)
+		}
+	}
+
+	// the next entry reuses the torn entry's sequence number
+	if _, _, err = o.CreateCard("b1", &glo.CardsInput{Name: "three", ColumnID: "todo"}); err != nil {
+		t.Fatal(err)
+	}
+	pending := o.Pending()
+	if len(pending) != 3 || pending[2].Seq != 3 {
+		t.Fatalf("got pending %+v", pending)
+	}
+
+	fake.setOffline(false)
+	if result, err := o.Replay(nil); err != nil || result.Replayed != 3 {
+		t.Fatalf("got result %+v err:%v", result, err)
+	}
+	var creates []string
+	for _, request := range fake.requests {
+		if request == "POST /boards/b1/cards" {
+			creates = append(creates, request)
+		}
+	}
+	if len(creates) != 3 {
+		t.Errorf("got requests %v", fake.requests)
+	}
+}
+
+func TestOpenCorruptJournal(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "outbox.jsonl")
+	data := `{"entry":{"seq":1,"op":"delete_card","board_id":"b1","card_id":"c1"}}` + "\n" +
+		"not json\n" +
+		`{"entry":{"seq":2,"op":"delete_card","board_id":"b1","card_id":"c2"}}` + "\n"
+	if err := ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Open(nil, path)
+	if err == nil || !strings.Contains(err.Error(), ":2: corrupt journal record") {
+		t.Errorf("got err %v, want a corrupt record on line 2", err)
+	}
+	if got, _ := ioutil.ReadFile(path); string(got) != data {
+		t.Error("corrupt journal was rewritten")
+	}
+}
+
+func journalLines(t *testing.T, path string) []string {
+	t.Helper()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
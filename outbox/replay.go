@@ -0,0 +1,228 @@
+package outbox
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Resolution how a conflicting entry is replayed
+type Resolution int
+
+// Conflict resolutions
+const (
+	// Stop stops replaying, leaving the entry queued
+	Stop Resolution = iota
+
+	// Skip discards the entry and continues replaying
+	Skip
+
+	// Overwrite applies the entry regardless of the card's changes
+	Overwrite
+)
+
+// DefaultMaxAttempts the number of times the API may fail to
+// replay an entry before it is marked failed
+const DefaultMaxAttempts = 5
+
+// ReplayOptions options used when replaying queued entries
+type ReplayOptions struct {
+	// OnConflict resolves conflicts, replay stops at the
+	// first conflict when nil
+	OnConflict func(conflict *ConflictError) Resolution
+
+	// MaxAttempts the number of replays, including those of earlier
+	// calls to Replay, the API may fail to serve an entry with a server
+	// error before it is marked failed, DefaultMaxAttempts when zero
+	MaxAttempts int
+}
+
+// ReplayResult the outcome of a replay
+type ReplayResult struct {
+	Replayed  int
+	Skipped   int
+	Failed    int
+	Remaining int
+}
+
+// ReplayError returned when an entry could not be replayed,
+// the entry and those after it remain queued
+type ReplayError struct {
+	Entry *Entry
+	Err   error
+}
+
+func (e *ReplayError) Error() string {
+	return fmt.Sprintf("failed to replay entry %d %s err:%s", e.Entry.Seq, e.Entry.Op, e.Err)
+}
+
+func (e *ReplayError) Unwrap() error {
+	return e.Err
+}
+
+// Replay applies the queued entries in order, stopping at the first
+// which fails, the created IDs of queued creates are recorded so later
+// entries referencing their temporary IDs can be replayed. An entry the
+// API fails to serve MaxAttempts times is marked failed and skipped
+func (o *Outbox) Replay(opts *ReplayOptions) (result *ReplayResult, err error) {
+	if opts == nil {
+		opts = &ReplayOptions{}
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	result = &ReplayResult{}
+	defer func() {
+		result.Remaining = len(o.pending)
+		if err == nil && len(o.pending) == 0 {
+			err = o.journal.compact(nil, o.failed, o.ids)
+		}
+	}()
+
+	for len(o.pending) > 0 {
+		entry := o.pending[0]
+
+		var resolved *Entry
+		resolved, err = o.resolve(entry)
+		if err != nil {
+			err = &ReplayError{Entry: entry, Err: err}
+			return
+		}
+
+		var applied interface{}
+		applied, err = o.replay(resolved, false)
+
+		var conflict *ConflictError
+		if errors.As(err, &conflict) && opts.OnConflict != nil {
+			switch opts.OnConflict(conflict) {
+			case Skip:
+				err = o.done(&record{Done: entry.Seq})
+				if err != nil {
+					return
+				}
+				result.Skipped++
+				continue
+			case Overwrite:
+				applied, err = o.replay(resolved, true)
+			}
+		}
+		if err != nil && glo.ErrorClass(err) == glo.ErrorClassServer {
+			var failed bool
+			failed, err = o.attempt(entry, err, maxAttempts)
+			if failed {
+				result.Failed++
+				continue
+			}
+		}
+		if err != nil {
+			err = &ReplayError{Entry: entry, Err: err}
+			return
+		}
+
+		records := []*record{{Done: entry.Seq}}
+		if entry.TempID != "" {
+			id := createdID(applied)
+			if id == "" {
+				err = &ReplayError{Entry: entry, Err: errors.New("created item has no ID")}
+				return
+			}
+			o.ids[entry.TempID] = id
+			records = append([]*record{{Temp: entry.TempID, ID: id}}, records...)
+		}
+
+		err = o.done(records...)
+		if err != nil {
+			return
+		}
+		result.Replayed++
+	}
+
+	return
+}
+
+// replay applies an entry, a delete of an item which no longer exists,
+// such as one replayed again after a crash, has nothing left to do
+func (o *Outbox) replay(entry *Entry, overwrite bool) (result interface{}, err error) {
+	result, err = o.apply(entry, overwrite)
+	if err != nil && deleted(entry, err) {
+		err = nil
+	}
+
+	return
+}
+
+// deleted reports whether a delete failed as its item no longer exists
+func deleted(entry *Entry, err error) bool {
+	switch entry.Op {
+	case OpDeleteColumn, OpDeleteCard, OpDeleteComment:
+	default:
+		return false
+	}
+
+	var conflict *ConflictError
+	if errors.As(err, &conflict) {
+		return conflict.Current == nil
+	}
+	var statusErr *glo.StatusError
+
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound
+}
+
+// attempt journals a server error replaying the first pending entry,
+// once it has failed maxAttempts times the entry is marked failed so
+// the entries after it can be replayed. The error is returned while
+// the entry remains pending
+func (o *Outbox) attempt(entry *Entry, cause error, maxAttempts int) (failed bool, err error) {
+	records := []*record{{Attempt: entry.Seq, Error: cause.Error()}}
+	failed = entry.Attempts+1 >= maxAttempts
+	if failed {
+		records = append(records, &record{Failed: entry.Seq})
+	}
+
+	err = o.journal.append(records...)
+	if err != nil {
+		return false, err
+	}
+	entry.Attempts++
+	entry.Error = cause.Error()
+
+	if !failed {
+		return false, cause
+	}
+	o.pending = o.pending[1:]
+	o.failed = append(o.failed, entry)
+
+	return
+}
+
+// done journals records completing the first pending entry
+func (o *Outbox) done(records ...*record) (err error) {
+	err = o.journal.append(records...)
+	if err != nil {
+		return
+	}
+	o.pending = o.pending[1:]
+
+	return
+}
+
+// createdID the ID of a created column, card or comment
+func createdID(created interface{}) string {
+	switch v := created.(type) {
+	case *glo.Column:
+		return v.ID
+	case *glo.Card:
+		return v.ID
+	case *glo.Comment:
+		return v.ID
+	}
+
+	return ""
+}
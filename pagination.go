@@ -0,0 +1,118 @@
+package glo
+
+const pageSize = 100
+
+// AllBoards Get every Board, following pagination
+func (a *Glo) AllBoards(
+	archived bool,
+	opts ...CallOption,
+) (
+	boards []*Board,
+	err error,
+) {
+	for page := 1; ; page++ {
+		resp, pageErr := a.GetBoards(page, pageSize, false, archived, opts...)
+		if pageErr != nil {
+			err = pageErr
+			return
+		}
+		boards = append(boards, resp.Boards...)
+
+		if !resp.HasMore {
+			return
+		}
+	}
+}
+
+// AllCards Get every Card of a Board, following pagination
+func (a *Glo) AllCards(
+	boardID string,
+	archived bool,
+	opts ...CallOption,
+) (
+	cards []*Card,
+	err error,
+) {
+	for page := 1; ; page++ {
+		resp, pageErr := a.GetCards(boardID, page, pageSize, false, archived, opts...)
+		if pageErr != nil {
+			err = pageErr
+			return
+		}
+		cards = append(cards, resp.Cards...)
+
+		if !resp.HasMore {
+			return
+		}
+	}
+}
+
+// AllCardsByColumn Get every Card of a Column, following pagination
+func (a *Glo) AllCardsByColumn(
+	boardID string,
+	columnID string,
+	archived bool,
+	opts ...CallOption,
+) (
+	cards []*Card,
+	err error,
+) {
+	for page := 1; ; page++ {
+		resp, pageErr := a.CardsByColumn(boardID, columnID, page, pageSize, false, archived, opts...)
+		if pageErr != nil {
+			err = pageErr
+			return
+		}
+		cards = append(cards, resp.Cards...)
+
+		if !resp.HasMore {
+			return
+		}
+	}
+}
+
+// AllComments Get every Comment of a Card, following pagination
+func (a *Glo) AllComments(
+	boardID string,
+	cardID string,
+	opts ...CallOption,
+) (
+	comments []*Comment,
+	err error,
+) {
+	for page := 1; ; page++ {
+		resp, pageErr := a.GetComments(boardID, cardID, page, pageSize, false, opts...)
+		if pageErr != nil {
+			err = pageErr
+			return
+		}
+		comments = append(comments, resp.Comments...)
+
+		if !resp.HasMore {
+			return
+		}
+	}
+}
+
+// AllAttachments Get every Attachment of a Card, following pagination
+func (a *Glo) AllAttachments(
+	boardID string,
+	cardID string,
+	opts ...CallOption,
+) (
+	attachments []*Attachment,
+	err error,
+) {
+	for page := 1; ; page++ {
+		resp, pageErr := a.GetAttachments(boardID, cardID, page, pageSize, false, opts...)
+		if pageErr != nil {
+			err = pageErr
+			return
+		}
+		attachments = append(attachments, resp.Attachments...)
+
+		if !resp.HasMore {
+			return
+		}
+	}
+}
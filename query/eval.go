@@ -0,0 +1,396 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Env the context queries are evaluated in
+type Env struct {
+	// Board the card's board, used to resolve label, column,
+	// member and board names
+	Board *glo.Board
+
+	// Me the ID of the user assignee:me refers to
+	Me string
+
+	// Now the time relative times are measured from, defaults to the current time
+	Now time.Time
+}
+
+func (env *Env) now() time.Time {
+	if env.Now.IsZero() {
+		return time.Now().UTC()
+	}
+
+	return env.Now.UTC()
+}
+
+// Match reports whether a card matches the query, archived cards
+// only match queries with an archived term
+func (q *Query) Match(env *Env, card *glo.Card) bool {
+	if env == nil {
+		env = &Env{}
+	}
+	if card.ArchivedDate != "" && !q.IncludesArchived() {
+		return false
+	}
+
+	return q.root.match(env, card)
+}
+
+// Filter the cards matching the query
+func (q *Query) Filter(env *Env, cards []*glo.Card) []*glo.Card {
+	var matched []*glo.Card
+	for _, card := range cards {
+		if q.Match(env, card) {
+			matched = append(matched, card)
+		}
+	}
+
+	return matched
+}
+
+// IncludesArchived reports whether the query has an archived term,
+// and so may match archived cards
+func (q *Query) IncludesArchived() bool {
+	found := false
+	walk(q.root, func(n node) {
+		if _, ok := n.(*archivedNode); ok {
+			found = true
+		}
+	})
+
+	return found
+}
+
+// UsesMe reports whether the query has an assignee:me term,
+// which requires Env.Me
+func (q *Query) UsesMe() bool {
+	found := false
+	walk(q.root, func(n node) {
+		if s, ok := n.(*stringNode); ok && s.field == "assignee" && strings.EqualFold(s.value, "me") {
+			found = true
+		}
+	})
+
+	return found
+}
+
+// Boards the board names or IDs the query is limited to,
+// nil when it may match cards of any board
+func (q *Query) Boards() []string {
+	and, ok := q.root.(*andNode)
+	nodes := []node{q.root}
+	if ok {
+		nodes = and.nodes
+	}
+
+	var boards []string
+	for _, n := range nodes {
+		if s, ok := n.(*stringNode); ok && s.field == "board" {
+			boards = append(boards, s.value)
+		}
+	}
+
+	return boards
+}
+
+type node interface {
+	match(env *Env, card *glo.Card) bool
+}
+
+func walk(n node, fn func(node)) {
+	fn(n)
+	switch n := n.(type) {
+	case *andNode:
+		for _, child := range n.nodes {
+			walk(child, fn)
+		}
+	case *orNode:
+		for _, child := range n.nodes {
+			walk(child, fn)
+		}
+	case *notNode:
+		walk(n.node, fn)
+	}
+}
+
+type andNode struct {
+	nodes []node
+}
+
+func (n *andNode) match(env *Env, card *glo.Card) bool {
+	for _, child := range n.nodes {
+		if !child.match(env, card) {
+			return false
+		}
+	}
+
+	return true
+}
+
+type orNode struct {
+	nodes []node
+}
+
+func (n *orNode) match(env *Env, card *glo.Card) bool {
+	for _, child := range n.nodes {
+		if child.match(env, card) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type notNode struct {
+	node node
+}
+
+func (n *notNode) match(env *Env, card *glo.Card) bool {
+	return !n.node.match(env, card)
+}
+
+// stringNode matches a label, assignee, column or board by ID or name
+type stringNode struct {
+	field string
+	value string
+}
+
+func (n *stringNode) match(env *Env, card *glo.Card) bool {
+	switch n.field {
+	case "label":
+		for _, partial := range card.Labels {
+			if n.is(partial.ID, partial.Name, labelName(env.Board, partial.ID)) {
+				return true
+			}
+		}
+	case "assignee":
+		value := n.value
+		if strings.EqualFold(value, "me") && env.Me != "" {
+			value = env.Me
+		}
+		for _, user := range card.Assignees {
+			if user.ID == value || strings.EqualFold(memberName(env.Board, user.ID), value) {
+				return true
+			}
+		}
+	case "column":
+		return n.is(card.ColumnID, columnName(env.Board, card.ColumnID))
+	case "board":
+		if env.Board != nil {
+			return n.is(env.Board.ID, env.Board.Name)
+		}
+		return n.is(card.BoardID)
+	}
+
+	return false
+}
+
+// is reports whether the value is an ID or, case-insensitively, a name
+func (n *stringNode) is(id string, names ...string) bool {
+	if id != "" && id == n.value {
+		return true
+	}
+	for _, name := range names {
+		if name != "" && strings.EqualFold(name, n.value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func labelName(board *glo.Board, id string) string {
+	if board == nil {
+		return ""
+	}
+	for _, label := range board.Labels {
+		if label.ID == id {
+			return label.Name
+		}
+	}
+
+	return ""
+}
+
+func columnName(board *glo.Board, id string) string {
+	if board == nil {
+		return ""
+	}
+	for _, group := range [][]*glo.Column{board.Columns, board.ArchivedColumns} {
+		for _, col := range group {
+			if col.ID == id {
+				return col.Name
+			}
+		}
+	}
+
+	return ""
+}
+
+func memberName(board *glo.Board, id string) string {
+	if board == nil {
+		return ""
+	}
+	for _, member := range board.Members {
+		if member.ID == id {
+			return member.Username
+		}
+	}
+
+	return ""
+}
+
+// textNode matches a case-insensitive substring
+type textNode struct {
+	field string
+	value string
+}
+
+func (n *textNode) match(env *Env, card *glo.Card) bool {
+	var description string
+	if card.Description != nil {
+		description = card.Description.Text
+	}
+
+	switch n.field {
+	case "name":
+		return contains(card.Name, n.value)
+	case "description":
+		return contains(description, n.value)
+	}
+
+	return contains(card.Name, n.value) || contains(description, n.value)
+}
+
+func contains(s, lower string) bool {
+	return strings.Contains(strings.ToLower(s), lower)
+}
+
+// timeNode compares a card's due, created or updated date
+type timeNode struct {
+	field string
+	op    string
+	value *timeValue
+}
+
+func (n *timeNode) match(env *Env, card *glo.Card) bool {
+	var raw string
+	switch n.field {
+	case "due":
+		raw = card.DueDate
+	case "created":
+		raw = card.CreatedDate
+	case "updated":
+		raw = card.UpdatedDate
+	}
+	if raw == "" {
+		return false
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return false
+	}
+
+	start, end := n.value.interval(env.now())
+	instant := start.Equal(end)
+
+	switch n.op {
+	case "<":
+		return t.Before(start)
+	case "<=":
+		if instant {
+			return !t.After(start)
+		}
+		return t.Before(end)
+	case ">":
+		if instant {
+			return t.After(start)
+		}
+		return !t.Before(end)
+	case ">=":
+		return !t.Before(start)
+	}
+
+	// equality with an instant matches its whole day
+	if instant {
+		start = truncateDay(start.UTC())
+		end = start.AddDate(0, 0, 1)
+	}
+
+	return !t.Before(start) && t.Before(end)
+}
+
+// numberNode compares a card's counts
+type numberNode struct {
+	field string
+	op    string
+	value int
+}
+
+func (n *numberNode) match(env *Env, card *glo.Card) bool {
+	var count int
+	switch n.field {
+	case "comments":
+		count = card.CommentCount
+	case "attachments":
+		count = card.AttachmentCount
+	case "tasks":
+		count = card.TotalTaskCount
+	case "done":
+		count = card.CompletedTaskCount
+	}
+
+	switch n.op {
+	case "<":
+		return count < n.value
+	case "<=":
+		return count <= n.value
+	case ">":
+		return count > n.value
+	case ">=":
+		return count >= n.value
+	}
+
+	return count == n.value
+}
+
+type archivedNode struct {
+	value bool
+}
+
+func (n *archivedNode) match(env *Env, card *glo.Card) bool {
+	return (card.ArchivedDate != "") == n.value
+}
+
+// presenceNode matches cards with, or without, a value
+type presenceNode struct {
+	what string
+	has  bool
+}
+
+func (n *presenceNode) match(env *Env, card *glo.Card) bool {
+	var present bool
+	switch n.what {
+	case "labels":
+		present = len(card.Labels) > 0
+	case "assignees":
+		present = len(card.Assignees) > 0
+	case "due":
+		present = card.DueDate != ""
+	case "description":
+		present = card.Description != nil && strings.TrimSpace(card.Description.Text) != ""
+	case "comments":
+		present = card.CommentCount > 0
+	case "attachments":
+		present = card.AttachmentCount > 0
+	case "tasks":
+		present = card.TotalTaskCount > 0
+	}
+
+	return present == n.has
+}
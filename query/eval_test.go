@@ -0,0 +1,170 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+func TestMatch(t *testing.T) {
+	env := &Env{
+		Board: &glo.Board{
+			ID:              "b1",
+			Name:            "Team",
+			Columns:         []*glo.Column{{ID: "c1", Name: "To Do"}, {ID: "c2", Name: "In Progress"}},
+			ArchivedColumns: []*glo.Column{{ID: "c3", Name: "Old"}},
+			Labels:          []*glo.Label{{ID: "l1", Name: "bug"}, {ID: "l2", Name: "ui"}},
+			Members:         []*glo.BoardMember{{ID: "u1", Username: "alice"}, {ID: "u2", Username: "bob"}},
+		},
+		Me:  "u1",
+		Now: time.Date(2019, 6, 10, 12, 0, 0, 0, time.UTC),
+	}
+
+	card := &glo.Card{
+		ID:                 "k1",
+		Name:               "Fix login page",
+		Description:        &glo.Description{Text: "Crashes on submit\n- [x] reproduce\n- [ ] fix"},
+		BoardID:            "b1",
+		ColumnID:           "c2",
+		CreatedDate:        "2019-06-01T09:00:00Z",
+		UpdatedDate:        "2019-06-10T08:00:00Z",
+		DueDate:            "2019-06-12T17:00:00Z",
+		Assignees:          []*glo.PartialUser{{ID: "u1"}},
+		Labels:             []*glo.PartialLabel{{ID: "l1"}},
+		CommentCount:       3,
+		CompletedTaskCount: 1,
+		TotalTaskCount:     2,
+	}
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{``, true},
+		{`login`, true},
+		{`LOGIN`, true},
+		{`logout`, false},
+		{`"login page"`, true},
+		{`"page login"`, false},
+		{`crashes`, true},
+		{`name:crashes`, false},
+		{`description:crashes`, true},
+		{`text:submit`, true},
+		{`label:bug`, true},
+		{`label:BUG`, true},
+		{`label:l1`, true},
+		{`label:ui`, false},
+		{`assignee:me`, true},
+		{`assignee:alice`, true},
+		{`assignee:u1`, true},
+		{`assignee:bob`, false},
+		{`column:"in progress"`, true},
+		{`column:c2`, true},
+		{`column:"To Do"`, false},
+		{`board:team`, true},
+		{`board:b1`, true},
+		{`board:other`, false},
+		{`due<7d`, true},
+		{`due<1d`, false},
+		{`due>now`, true},
+		{`due:2019-06-12`, true},
+		{`due:2019-06-11`, false},
+		{`due<=2019-06-12`, true},
+		{`due>2019-06-12`, false},
+		{`due>=2019-06-12`, true},
+		{`due<2019-06-12`, false},
+		{`updated:today`, true},
+		{`updated<today`, false},
+		{`created<-1w`, true},
+		{`created>-1w`, false},
+		{`created:2019-06-01T09:00:00Z`, true},
+		{`updated>-6h`, true},
+		{`updated>-3h`, false},
+		{`comments>2`, true},
+		{`comments:3`, true},
+		{`comments<3`, false},
+		{`attachments:0`, true},
+		{`tasks:2 done:1`, true},
+		{`done>=2`, false},
+		{`has:labels has:assignees has:due has:description has:comments has:tasks`, true},
+		{`has:attachments`, false},
+		{`no:attachments`, true},
+		{`no:labels`, false},
+		{`archived:false`, true},
+		{`archived:true`, false},
+		{`label:bug label:ui`, false},
+		{`label:bug OR label:ui`, true},
+		{`label:ui OR assignee:bob`, false},
+		{`-label:ui`, true},
+		{`NOT label:bug`, false},
+		{`NOT (label:ui OR assignee:bob)`, true},
+		{`-(label:bug assignee:me)`, false},
+		{`(label:ui OR login) column:c2`, true},
+	}
+
+	for _, test := range tests {
+		if got := MustParse(test.query).Match(env, card); got != test.want {
+			t.Errorf("%q matched %t, want %t", test.query, got, test.want)
+		}
+	}
+}
+
+func TestMatchArchived(t *testing.T) {
+	active := &glo.Card{ID: "active", Name: "a"}
+	archived := &glo.Card{ID: "archived", Name: "a", ArchivedDate: "2019-06-01T00:00:00Z"}
+	cards := []*glo.Card{active, archived}
+
+	tests := []struct {
+		query string
+		want  []*glo.Card
+	}{
+		{`a`, []*glo.Card{active}},
+		{`archived:true`, []*glo.Card{archived}},
+		{`archived:false`, []*glo.Card{active}},
+		{`archived:true OR archived:false`, cards},
+		{`-archived:true`, []*glo.Card{active}},
+	}
+
+	for _, test := range tests {
+		got := MustParse(test.query).Filter(nil, cards)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q matched %d cards, want %d", test.query, len(got), len(test.want))
+		}
+	}
+}
+
+func TestQueryInfo(t *testing.T) {
+	tests := []struct {
+		query    string
+		archived bool
+		me       bool
+		boards   []string
+	}{
+		{``, false, false, nil},
+		{`label:bug`, false, false, nil},
+		{`archived:true`, true, false, nil},
+		{`NOT (label:bug OR archived:false)`, true, false, nil},
+		{`assignee:me`, false, true, nil},
+		{`assignee:ME`, false, true, nil},
+		{`-assignee:me`, false, true, nil},
+		{`assignee:alice`, false, false, nil},
+		{`board:Team`, false, false, []string{"Team"}},
+		{`board:Team board:b2 label:bug`, false, false, []string{"Team", "b2"}},
+		{`board:Team OR board:b2`, false, false, nil},
+	}
+
+	for _, test := range tests {
+		q := MustParse(test.query)
+		if got := q.IncludesArchived(); got != test.archived {
+			t.Errorf("%q IncludesArchived() = %t, want %t", test.query, got, test.archived)
+		}
+		if got := q.UsesMe(); got != test.me {
+			t.Errorf("%q UsesMe() = %t, want %t", test.query, got, test.me)
+		}
+		if got := q.Boards(); !reflect.DeepEqual(got, test.boards) {
+			t.Errorf("%q Boards() = %v, want %v", test.query, got, test.boards)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+// Package query implements a small query language for filtering cards.
+//
+//	label:bug assignee:me column:"In Progress" due<7d archived:false text:"login"
+//
+// Terms are combined with AND, OR combines alternatives and a leading
+// "-" or NOT negates a term or a parenthesised group. Bare words and
+// quoted phrases match card names and descriptions.
+//
+// Fields:
+//
+//	label:<name|id>          the card has the label
+//	assignee:<user|me>       the card is assigned to the user, by ID or username
+//	column:<name|id>         the card is in the column
+//	board:<name|id>          the card is on the board
+//	text:, name:, description:
+//	                         the text contains the value, case-insensitively
+//	due, created, updated    compared with <, <=, >, >= or : to a date (2006-01-02),
+//	                         a time (RFC 3339), today, now or an offset from now
+//	                         such as 7d, -2w or 12h
+//	comments, attachments, tasks, done
+//	                         compared to a number, done counts completed tasks
+//	archived:<true|false>    the card is archived
+//	has:<field>, no:<field>  the card has, or lacks, labels, assignees, due,
+//	                         description, comments or attachments
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SyntaxError an error parsing a query
+type SyntaxError struct {
+	// Pos the byte offset of the error in the query
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("query: position %d: %s", e.Pos+1, e.Msg)
+}
+
+// Query a parsed query
+type Query struct {
+	root node
+	src  string
+}
+
+// String the query as it was parsed
+func (q *Query) String() string {
+	return q.src
+}
+
+// Parse parses a query, the empty query matches every card
+func Parse(s string) (q *Query, err error) {
+	p := &parser{src: s}
+	q = &Query{src: s}
+
+	p.skipSpace()
+	if p.pos == len(p.src) {
+		q.root = &andNode{}
+		return
+	}
+
+	q.root, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+
+	return
+}
+
+// MustParse parses a query, panicking when it is invalid
+func MustParse(s string) *Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return q
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Pos: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+// keyword consumes an upper case keyword followed by a space or parenthesis
+func (p *parser) keyword(word string) bool {
+	end := p.pos + len(word)
+	if !strings.HasPrefix(p.src[p.pos:], word) {
+		return false
+	}
+	if end < len(p.src) && !unicode.IsSpace(rune(p.src[end])) && p.src[end] != '(' {
+		return false
+	}
+	p.pos = end
+
+	return true
+}
+
+// parseOr parses and-expressions separated by OR
+func (p *parser) parseOr() (n node, err error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return
+	}
+
+	or := &orNode{nodes: []node{left}}
+	for {
+		p.skipSpace()
+		if !p.keyword("OR") {
+			break
+		}
+		p.skipSpace()
+		var right node
+		right, err = p.parseAnd()
+		if err != nil {
+			return
+		}
+		or.nodes = append(or.nodes, right)
+	}
+	if len(or.nodes) == 1 {
+		return left, nil
+	}
+
+	return or, nil
+}
+
+// parseAnd parses adjacent terms
+func (p *parser) parseAnd() (n node, err error) {
+	and := &andNode{}
+	for {
+		p.skipSpace()
+		if p.pos == len(p.src) || p.src[p.pos] == ')' {
+			break
+		}
+		save := p.pos
+		if p.keyword("OR") {
+			p.pos = save
+			break
+		}
+
+		var term node
+		term, err = p.parseUnary()
+		if err != nil {
+			return
+		}
+		and.nodes = append(and.nodes, term)
+	}
+
+	switch len(and.nodes) {
+	case 0:
+		err = p.errorf("expected a term")
+		return
+	case 1:
+		return and.nodes[0], nil
+	}
+
+	return and, nil
+}
+
+// parseUnary parses a negated term, a group or a term
+func (p *parser) parseUnary() (n node, err error) {
+	if p.pos == len(p.src) || p.src[p.pos] == ')' {
+		err = p.errorf("expected a term")
+		return
+	}
+
+	if p.keyword("NOT") {
+		p.skipSpace()
+		n, err = p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: n}, nil
+	}
+
+	if p.src[p.pos] == '-' && p.pos+1 < len(p.src) && !unicode.IsSpace(rune(p.src[p.pos+1])) {
+		p.pos++
+		n, err = p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notNode{node: n}, nil
+	}
+
+	if p.src[p.pos] == '(' {
+		p.pos++
+		n, err = p.parseOr()
+		if err != nil {
+			return
+		}
+		p.skipSpace()
+		if p.pos == len(p.src) || p.src[p.pos] != ')' {
+			err = p.errorf("expected )")
+			return
+		}
+		p.pos++
+		return
+	}
+
+	return p.parseTerm()
+}
+
+// parseTerm parses field<op>value, a quoted phrase or a bare word
+func (p *parser) parseTerm() (n node, err error) {
+	start := p.pos
+
+	if p.src[p.pos] == '"' {
+		var phrase string
+		phrase, err = p.parseQuoted()
+		if err != nil {
+			return
+		}
+		return &textNode{field: "text", value: strings.ToLower(phrase)}, nil
+	}
+
+	key := p.scanWhile(func(r byte) bool {
+		return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	})
+	opStart := p.pos
+	op := p.scanWhile(func(r byte) bool {
+		return r == ':' || r == '=' || r == '<' || r == '>'
+	})
+
+	if op == "" {
+		// a bare word
+		p.pos = start
+		word := p.scanValue()
+		return &textNode{field: "text", value: strings.ToLower(word)}, nil
+	}
+
+	field := strings.ToLower(key)
+	kind, ok := fields[field]
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown field %q", key)
+	}
+
+	// ":<" and ":>" are accepted for those used to GitHub's syntax
+	op = strings.TrimPrefix(op, ":")
+	if op == "" || op == "=" {
+		op = ":"
+	}
+	switch op {
+	case ":", "<", "<=", ">", ">=":
+	default:
+		p.pos = opStart
+		return nil, p.errorf("invalid operator %q", op)
+	}
+
+	valueStart := p.pos
+	var value string
+	if p.pos < len(p.src) && p.src[p.pos] == '"' {
+		value, err = p.parseQuoted()
+		if err != nil {
+			return
+		}
+	} else {
+		value = p.scanValue()
+	}
+	if value == "" {
+		return nil, p.errorf("%s requires a value", field)
+	}
+
+	if op != ":" && kind != kindTime && kind != kindNumber {
+		p.pos = opStart
+		return nil, p.errorf("%s can not be compared with %s", field, op)
+	}
+
+	n, err = newTerm(field, kind, op, value)
+	if err != nil {
+		return nil, &SyntaxError{Pos: valueStart, Msg: err.Error()}
+	}
+
+	return
+}
+
+func (p *parser) scanWhile(fn func(byte) bool) string {
+	start := p.pos
+	for p.pos < len(p.src) && fn(p.src[p.pos]) {
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// scanValue scans an unquoted value, up to a space or closing parenthesis
+func (p *parser) scanValue() string {
+	return p.scanWhile(func(r byte) bool {
+		return !unicode.IsSpace(rune(r)) && r != ')' && r != '('
+	})
+}
+
+// parseQuoted parses a double quoted string, \" and \\ are escapes
+func (p *parser) parseQuoted() (s string, err error) {
+	start := p.pos
+	p.pos++
+
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		switch {
+		case c == '\\' && p.pos+1 < len(p.src):
+			b.WriteByte(p.src[p.pos+1])
+			p.pos += 2
+		case c == '"':
+			p.pos++
+			return b.String(), nil
+		default:
+			b.WriteByte(c)
+			p.pos++
+		}
+	}
+
+	p.pos = start
+	err = p.errorf("unterminated quoted string")
+
+	return
+}
+
+type kind int
+
+const (
+	kindString kind = iota
+	kindText
+	kindTime
+	kindNumber
+	kindBool
+	kindPresence
+)
+
+var fields = map[string]kind{
+	"label":       kindString,
+	"assignee":    kindString,
+	"column":      kindString,
+	"board":       kindString,
+	"text":        kindText,
+	"name":        kindText,
+	"description": kindText,
+	"due":         kindTime,
+	"created":     kindTime,
+	"updated":     kindTime,
+	"comments":    kindNumber,
+	"attachments": kindNumber,
+	"tasks":       kindNumber,
+	"done":        kindNumber,
+	"archived":    kindBool,
+	"has":         kindPresence,
+	"no":          kindPresence,
+}
+
+var presence = map[string]string{
+	"label":       "labels",
+	"labels":      "labels",
+	"assignee":    "assignees",
+	"assignees":   "assignees",
+	"due":         "due",
+	"description": "description",
+	"comments":    "comments",
+	"attachments": "attachments",
+	"tasks":       "tasks",
+}
+
+func newTerm(field string, k kind, op string, value string) (n node, err error) {
+	switch k {
+	case kindString:
+		n = &stringNode{field: field, value: value}
+	case kindText:
+		n = &textNode{field: field, value: strings.ToLower(value)}
+	case kindTime:
+		t := &timeNode{field: field, op: op}
+		t.value, err = parseTime(value)
+		n = t
+	case kindNumber:
+		num := &numberNode{field: field, op: op}
+		num.value, err = strconv.Atoi(value)
+		if err != nil {
+			err = fmt.Errorf("%s requires a number, got %q", field, value)
+		}
+		n = num
+	case kindBool:
+		var b bool
+		b, err = strconv.ParseBool(value)
+		if err != nil {
+			err = fmt.Errorf("%s requires true or false, got %q", field, value)
+		}
+		n = &archivedNode{value: b}
+	case kindPresence:
+		what, ok := presence[strings.ToLower(value)]
+		if !ok {
+			err = fmt.Errorf("unknown %s value %q", field, value)
+		}
+		n = &presenceNode{what: what, has: field == "has"}
+	}
+
+	return
+}
+
+// timeValue a point in time relative to now, or a fixed interval
+type timeValue struct {
+	// offset from now, when relative
+	relative bool
+	offset   time.Duration
+	// day the value is a whole day
+	day bool
+	// today the value is the current day
+	today bool
+	at    time.Time
+}
+
+// interval the time span of the value, end is exclusive
+func (v *timeValue) interval(now time.Time) (start, end time.Time) {
+	switch {
+	case v.today:
+		start = truncateDay(now)
+		return start, start.AddDate(0, 0, 1)
+	case v.relative:
+		start = now.Add(v.offset)
+		return start, start
+	case v.day:
+		return v.at, v.at.AddDate(0, 0, 1)
+	}
+
+	return v.at, v.at
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+var units = map[byte]time.Duration{
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+func parseTime(value string) (v *timeValue, err error) {
+	switch strings.ToLower(value) {
+	case "now":
+		return &timeValue{relative: true}, nil
+	case "today":
+		return &timeValue{today: true}, nil
+	}
+
+	if t, parseErr := time.Parse("2006-01-02", value); parseErr == nil {
+		return &timeValue{day: true, at: t}, nil
+	}
+	if t, parseErr := time.Parse(time.RFC3339, value); parseErr == nil {
+		return &timeValue{at: t}, nil
+	}
+
+	unit, ok := units[value[len(value)-1]]
+	if ok {
+		n, convErr := strconv.Atoi(value[:len(value)-1])
+		if convErr == nil {
+			return &timeValue{relative: true, offset: time.Duration(n) * unit}, nil
+		}
+	}
+
+	err = fmt.Errorf("invalid time %q, expected a date, today, now or an offset such as 7d", value)
+
+	return
+}
@@ -0,0 +1,227 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// dump a canonical form of a parsed query
+func dump(n node) string {
+	switch n := n.(type) {
+	case *andNode:
+		return "(and" + dumpAll(n.nodes) + ")"
+	case *orNode:
+		return "(or" + dumpAll(n.nodes) + ")"
+	case *notNode:
+		return "(not " + dump(n.node) + ")"
+	case *stringNode:
+		return fmt.Sprintf("%s:%q", n.field, n.value)
+	case *textNode:
+		return fmt.Sprintf("%s:%q", n.field, n.value)
+	case *timeNode:
+		return fmt.Sprintf("%s%s%+v", n.field, n.op, *n.value)
+	case *numberNode:
+		return fmt.Sprintf("%s%s%d", n.field, n.op, n.value)
+	case *archivedNode:
+		return fmt.Sprintf("archived:%t", n.value)
+	case *presenceNode:
+		if n.has {
+			return "has:" + n.what
+		}
+		return "no:" + n.what
+	}
+
+	return fmt.Sprintf("%T", n)
+}
+
+func dumpAll(nodes []node) string {
+	var b strings.Builder
+	for _, n := range nodes {
+		b.WriteString(" ")
+		b.WriteString(dump(n))
+	}
+
+	return b.String()
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{``, `(and)`},
+		{`   `, `(and)`},
+		{`login`, `text:"login"`},
+		{`Login Page`, `(and text:"login" text:"page")`},
+		{`"Login Page"`, `text:"login page"`},
+		{`"say \"hi\" \\ bye"`, `text:"say \"hi\" \\ bye"`},
+		{`label:bug`, `label:"bug"`},
+		{`LABEL:bug`, `label:"bug"`},
+		{`label=bug`, `label:"bug"`},
+		{`column:"In Progress"`, `column:"In Progress"`},
+		{`assignee:me board:Team`, `(and assignee:"me" board:"Team")`},
+		{`name:Fix description:"Steps To"`, `(and name:"fix" description:"steps to")`},
+		{`comments>2`, `comments>2`},
+		{`comments:>2`, `comments>2`},
+		{`tasks>=3 done<=1`, `(and tasks>=3 done<=1)`},
+		{`attachments:0`, `attachments:0`},
+		{`archived:true`, `archived:true`},
+		{`archived:false`, `archived:false`},
+		{`has:labels no:due`, `(and has:labels no:due)`},
+		{`has:assignee`, `has:assignees`},
+		{`label:bug OR label:feature`, `(or label:"bug" label:"feature")`},
+		{`a b OR c`, `(or (and text:"a" text:"b") text:"c")`},
+		{`a OR b OR c`, `(or text:"a" text:"b" text:"c")`},
+		{`-label:bug`, `(not label:"bug")`},
+		{`NOT label:bug`, `(not label:"bug")`},
+		{`NOT(label:bug OR label:ui)`, `(not (or label:"bug" label:"ui"))`},
+		{`-(a b)`, `(not (and text:"a" text:"b"))`},
+		{`NOT NOT a`, `(not (not text:"a"))`},
+		{`(a OR b) c`, `(and (or text:"a" text:"b") text:"c")`},
+		{`ORDER`, `text:"order"`},
+		{`NOTE`, `text:"note"`},
+		{`- a`, `(and text:"-" text:"a")`},
+	}
+
+	for _, test := range tests {
+		q, err := Parse(test.query)
+		if err != nil {
+			t.Errorf("Parse(%q) err:%s", test.query, err)
+			continue
+		}
+		if got := dump(q.root); got != test.want {
+			t.Errorf("Parse(%q) = %s, want %s", test.query, got, test.want)
+		}
+		if q.String() != test.query {
+			t.Errorf("Parse(%q).String() = %q", test.query, q.String())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{`NOT`, 3, "expected a term"},
+		{`NOT `, 4, "expected a term"},
+		{`label:bug NOT`, 13, "expected a term"},
+		{`-NOT`, 4, "expected a term"},
+		{`-NOT `, 5, "expected a term"},
+		{`NOT )`, 4, "expected a term"},
+		{`-)`, 1, "expected a term"},
+		{`()`, 1, "expected a term"},
+		{`(a`, 2, "expected )"},
+		{`a)`, 1, "unexpected ')'"},
+		{`a OR`, 4, "expected a term"},
+		{`OR a`, 0, "expected a term"},
+		{`"open`, 0, "unterminated quoted string"},
+		{`colour:red`, 0, `unknown field "colour"`},
+		{`label:`, 6, "label requires a value"},
+		{`label<bug`, 5, "label can not be compared with <"},
+		{`comments:=>2`, 8, `invalid operator "=>"`},
+		{`comments>many`, 9, `comments requires a number, got "many"`},
+		{`archived:maybe`, 9, `archived requires true or false, got "maybe"`},
+		{`has:colour`, 4, `unknown has value "colour"`},
+		{`due<soon`, 4, `invalid time "soon", expected a date, today, now or an offset such as 7d`},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.query)
+		syntaxErr, ok := err.(*SyntaxError)
+		if !ok {
+			t.Errorf("Parse(%q) err:%v, want a SyntaxError", test.query, err)
+			continue
+		}
+		if syntaxErr.Pos != test.pos || syntaxErr.Msg != test.msg {
+			t.Errorf("Parse(%q) err at %d %q, want at %d %q", test.query, syntaxErr.Pos, syntaxErr.Msg, test.pos, test.msg)
+		}
+	}
+}
+
+// TestParseDocumented parses the grammar of the package documentation
+// and checks every query parses back to the same tree from its canonical form
+func TestParseDocumented(t *testing.T) {
+	queries := []string{
+		`label:bug assignee:me column:"In Progress" due<7d archived:false text:"login"`,
+		`label:bug OR label:feature`,
+		`-label:bug NOT column:Done`,
+		`NOT (label:bug OR -assignee:me)`,
+		`board:Team name:fix description:crash`,
+		`due<2019-01-02 created>=2019-01-01T10:00:00Z updated>today due<=now due>-2w updated<12h created:30m`,
+		`comments>0 attachments<=2 tasks>=1 done:0`,
+		`archived:true has:label no:assignees has:due no:description has:comments has:attachments has:tasks`,
+	}
+
+	for _, query := range queries {
+		q, err := Parse(query)
+		if err != nil {
+			t.Errorf("Parse(%q) err:%s", query, err)
+			continue
+		}
+
+		canonical := format(q.root)
+		again, err := Parse(canonical)
+		if err != nil {
+			t.Errorf("Parse(%q) of %q err:%s", canonical, query, err)
+			continue
+		}
+		if dump(again.root) != dump(q.root) {
+			t.Errorf("%q parsed as %s, its canonical form %q as %s", query, dump(q.root), canonical, dump(again.root))
+		}
+	}
+}
+
+// format a parsed query back into the query language
+func format(n node) string {
+	switch n := n.(type) {
+	case *andNode:
+		var parts []string
+		for _, child := range n.nodes {
+			parts = append(parts, format(child))
+		}
+		return "(" + strings.Join(parts, " ") + ")"
+	case *orNode:
+		var parts []string
+		for _, child := range n.nodes {
+			parts = append(parts, format(child))
+		}
+		return "(" + strings.Join(parts, " OR ") + ")"
+	case *notNode:
+		return "NOT " + format(n.node)
+	case *stringNode:
+		return fmt.Sprintf("%s:%q", n.field, n.value)
+	case *textNode:
+		return fmt.Sprintf("%s:%q", n.field, n.value)
+	case *timeNode:
+		return n.field + n.op + formatTime(n.value)
+	case *numberNode:
+		return fmt.Sprintf("%s%s%d", n.field, n.op, n.value)
+	case *archivedNode:
+		return fmt.Sprintf("archived:%t", n.value)
+	case *presenceNode:
+		if n.has {
+			return "has:" + n.what
+		}
+		return "no:" + n.what
+	}
+
+	return ""
+}
+
+func formatTime(v *timeValue) string {
+	switch {
+	case v.today:
+		return "today"
+	case v.relative && v.offset == 0:
+		return "now"
+	case v.relative:
+		return fmt.Sprintf("%dm", int(v.offset.Minutes()))
+	case v.day:
+		return v.at.Format("2006-01-02")
+	}
+
+	return v.at.Format("2006-01-02T15:04:05Z07:00")
+}
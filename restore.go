@@ -0,0 +1,540 @@
+package glo
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RestoreOptions contains information used to restore a backup
+type RestoreOptions struct {
+	// BoardIDs restricts the restore to these boards of the backup,
+	// every board is restored when empty
+	BoardIDs []string
+
+	// BoardName restores a single board into a new board with this name
+	BoardName string
+
+	// IncludeArchived restores archived cards, the API cannot
+	// archive cards so they are restored as active cards
+	IncludeArchived bool
+
+	// IDMap the mapping returned by a previous restore which failed,
+	// objects which were already restored are skipped
+	IDMap *RestoreIDMap
+
+	// Incrementals incremental backups taken after the restored archive,
+	// oldest first, the boards are restored as of the latest of them
+	Incrementals []io.Reader
+}
+
+// RestoreIDMap maps the IDs of backed up objects to the IDs of their
+// restored copies, it is returned even when a restore fails so that
+// it can be persisted and used to resume the restore
+type RestoreIDMap struct {
+	Boards      map[string]string `json:"boards"`
+	Columns     map[string]string `json:"columns"`
+	Labels      map[string]string `json:"labels"`
+	Cards       map[string]string `json:"cards"`
+	Comments    map[string]string `json:"comments"`
+	Attachments map[string]string `json:"attachments"`
+}
+
+// NewRestoreIDMap creates an empty RestoreIDMap
+func NewRestoreIDMap() *RestoreIDMap {
+	return &RestoreIDMap{
+		Boards:      map[string]string{},
+		Columns:     map[string]string{},
+		Labels:      map[string]string{},
+		Cards:       map[string]string{},
+		Comments:    map[string]string{},
+		Attachments: map[string]string{},
+	}
+}
+
+// init allocates maps missing from a decoded mapping
+func (m *RestoreIDMap) init() {
+	for _, ids := range []*map[string]string{
+		&m.Boards,
+		&m.Columns,
+		&m.Labels,
+		&m.Cards,
+		&m.Comments,
+		&m.Attachments,
+	} {
+		if *ids == nil {
+			*ids = map[string]string{}
+		}
+	}
+}
+
+// backupArchive the entries of a backup archive
+type backupArchive struct {
+	manifest *BackupManifest
+	files    map[string][]byte
+
+	// chain the creation times of the backups merged into the archive
+	chain []time.Time
+}
+
+func readBackupArchive(r io.Reader) (archive *backupArchive, err error) {
+	archive = &backupArchive{files: map[string][]byte{}}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, nextErr := tr.Next()
+		if nextErr == io.EOF {
+			break
+		}
+		if nextErr != nil {
+			err = nextErr
+			return
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		archive.files[hdr.Name], err = ioutil.ReadAll(tr)
+		if err != nil {
+			return
+		}
+	}
+
+	data, ok := archive.files[backupManifestName]
+	if !ok {
+		err = fmt.Errorf("backup archive does not contain a manifest")
+		return
+	}
+	archive.manifest = &BackupManifest{}
+	err = json.Unmarshal(data, archive.manifest)
+	if err != nil {
+		return
+	}
+	if archive.manifest.Version > BackupVersion {
+		err = fmt.Errorf("unsupported backup version:%d", archive.manifest.Version)
+		return
+	}
+	archive.chain = []time.Time{archive.manifest.CreatedAt}
+
+	return
+}
+
+// apply merges an incremental backup into the archive, boards and cards
+// are taken from the incremental backup, the data of cards it does not
+// include is kept from the archive
+func (b *backupArchive) apply(incremental *backupArchive) (err error) {
+	manifest := incremental.manifest
+	if !manifest.Incremental {
+		return fmt.Errorf("backup created at %s is not incremental", manifest.CreatedAt)
+	}
+	if manifest.BaseCreatedAt == nil {
+		return fmt.Errorf("incremental backup created at %s has no base", manifest.CreatedAt)
+	}
+
+	based := false
+	for _, createdAt := range b.chain {
+		if createdAt.Equal(*manifest.BaseCreatedAt) {
+			based = true
+		}
+	}
+	if !based {
+		return fmt.Errorf(
+			"incremental backup created at %s is based on a backup created at %s which was not restored",
+			manifest.CreatedAt,
+			manifest.BaseCreatedAt,
+		)
+	}
+
+	previous := map[string]*BackupCard{}
+	for _, board := range b.manifest.Boards {
+		for _, card := range board.Cards {
+			previous[card.ID] = card
+		}
+	}
+
+	files := map[string][]byte{}
+	merged := &BackupManifest{
+		Version:   manifest.Version,
+		CreatedAt: manifest.CreatedAt,
+	}
+	for _, board := range manifest.Boards {
+		mergedBoard := *board
+		mergedBoard.Cards = nil
+		files[board.Path] = incremental.files[board.Path]
+
+		for _, card := range board.Cards {
+			source := incremental
+			if !card.Included {
+				source = b
+
+				prev, ok := previous[card.ID]
+				if !ok {
+					return fmt.Errorf("card:%s was not included in any backup", card.ID)
+				}
+				kept := *prev
+				kept.Archived = card.Archived
+				card = &kept
+			}
+
+			if card.Included {
+				dir := path.Dir(card.Path)
+				for _, name := range []string{backupCardName, backupCommentsName, backupAttachmentsName} {
+					name = path.Join(dir, name)
+					files[name] = source.files[name]
+				}
+				for _, attachment := range card.Attachments {
+					if attachment.Path != "" {
+						files[attachment.Path] = source.files[attachment.Path]
+					}
+				}
+			}
+			mergedBoard.Cards = append(mergedBoard.Cards, card)
+		}
+		merged.Boards = append(merged.Boards, &mergedBoard)
+	}
+
+	b.manifest = merged
+	b.files = files
+	b.chain = append(b.chain, manifest.CreatedAt)
+
+	return
+}
+
+func (b *backupArchive) readJSON(name string, v interface{}) error {
+	data, ok := b.files[name]
+	if !ok {
+		return fmt.Errorf("backup archive does not contain %s", name)
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// Restore recreates the boards of a backup archive, with their columns,
+// labels, cards, comments and attachments, returning the IDs of the
+// restored objects.
+//
+// Comments and attachments are created by the authenticated user,
+// comments linking to attachments are replaced by the comments
+// linking to their uploaded copies.
+func (a *Glo) Restore(
+	ctx context.Context,
+	r io.Reader,
+	opts *RestoreOptions,
+) (
+	idMap *RestoreIDMap,
+	err error,
+) {
+	if opts == nil {
+		opts = &RestoreOptions{}
+	}
+	a = a.WithContext(ctx)
+
+	idMap = opts.IDMap
+	if idMap == nil {
+		idMap = NewRestoreIDMap()
+	}
+	idMap.init()
+
+	archive, err := readBackupArchive(r)
+	if err != nil {
+		return
+	}
+	if archive.manifest.Incremental {
+		err = fmt.Errorf("incremental backups only contain changed cards, restore the full backup they are based on with the incremental backups in RestoreOptions.Incrementals")
+		return
+	}
+
+	for i, incremental := range opts.Incrementals {
+		var next *backupArchive
+		next, err = readBackupArchive(incremental)
+		if err != nil {
+			err = fmt.Errorf("failed to read incremental backup:%d err:%s", i, err)
+			return
+		}
+		err = archive.apply(next)
+		if err != nil {
+			err = fmt.Errorf("failed to apply incremental backup:%d err:%s", i, err)
+			return
+		}
+	}
+
+	selected := map[string]bool{}
+	for _, id := range opts.BoardIDs {
+		selected[id] = true
+	}
+
+	var boards []*BackupBoard
+	for _, board := range archive.manifest.Boards {
+		if len(selected) == 0 || selected[board.ID] {
+			boards = append(boards, board)
+		}
+	}
+	if opts.BoardName != "" && len(boards) != 1 {
+		err = fmt.Errorf("a new board name can only be used when restoring a single board, found:%d", len(boards))
+		return
+	}
+	if len(boards) == 0 {
+		err = fmt.Errorf("no boards to restore")
+		return
+	}
+
+	for _, board := range boards {
+		err = a.restoreBoard(ctx, archive, board, opts, idMap)
+		if err != nil {
+			err = fmt.Errorf("failed to restore board:%s err:%s", board.ID, err)
+			return
+		}
+	}
+
+	return
+}
+
+func (a *Glo) restoreBoard(
+	ctx context.Context,
+	archive *backupArchive,
+	entry *BackupBoard,
+	opts *RestoreOptions,
+	idMap *RestoreIDMap,
+) (err error) {
+	source := &Board{}
+	err = archive.readJSON(entry.Path, source)
+	if err != nil {
+		return
+	}
+
+	boardID, ok := idMap.Boards[source.ID]
+	if !ok {
+		name := source.Name
+		if opts.BoardName != "" {
+			name = opts.BoardName
+		}
+
+		var board *Board
+		board, err = a.CreateBoard(&BoardInput{Name: name})
+		if err != nil {
+			return
+		}
+		boardID = board.ID
+		idMap.Boards[source.ID] = boardID
+	}
+
+	var cards []*Card
+	for _, cardEntry := range entry.Cards {
+		if !cardEntry.Included || (cardEntry.Archived && !opts.IncludeArchived) {
+			continue
+		}
+
+		card := &Card{}
+		err = archive.readJSON(cardEntry.Path, card)
+		if err != nil {
+			return
+		}
+		cards = append(cards, card)
+	}
+
+	err = a.restoreColumns(ctx, boardID, source, cards, idMap)
+	if err != nil {
+		return
+	}
+
+	for _, label := range source.Labels {
+		if _, ok := idMap.Labels[label.ID]; ok {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var restored *Label
+		restored, err = a.CreateLabel(boardID, &LabelInput{Name: label.Name, Color: label.Color})
+		if err != nil {
+			return
+		}
+		idMap.Labels[label.ID] = restored.ID
+	}
+
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].Position < cards[j].Position
+	})
+
+	cardEntries := map[string]*BackupCard{}
+	for _, cardEntry := range entry.Cards {
+		cardEntries[cardEntry.ID] = cardEntry
+	}
+
+	for _, card := range cards {
+		err = a.restoreCard(ctx, archive, boardID, card, cardEntries[card.ID], idMap)
+		if err != nil {
+			err = fmt.Errorf("failed to restore card:%s err:%s", card.ID, err)
+			return
+		}
+	}
+
+	return
+}
+
+// restoreColumns restores the board's columns in position order, archived
+// columns are only restored when restored cards belong to them
+func (a *Glo) restoreColumns(
+	ctx context.Context,
+	boardID string,
+	source *Board,
+	cards []*Card,
+	idMap *RestoreIDMap,
+) (err error) {
+	used := map[string]bool{}
+	for _, card := range cards {
+		used[card.ColumnID] = true
+	}
+
+	columns := append([]*Column{}, source.Columns...)
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Position < columns[j].Position
+	})
+
+	archived := append([]*Column{}, source.ArchivedColumns...)
+	sort.SliceStable(archived, func(i, j int) bool {
+		return archived[i].Position < archived[j].Position
+	})
+	for _, col := range archived {
+		if used[col.ID] {
+			columns = append(columns, col)
+		}
+	}
+
+	for position, col := range columns {
+		if _, ok := idMap.Columns[col.ID]; ok {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var restored *Column
+		restored, err = a.CreateColumn(boardID, &ColumnInput{Name: col.Name, Position: position})
+		if err != nil {
+			return
+		}
+		idMap.Columns[col.ID] = restored.ID
+	}
+
+	return
+}
+
+func (a *Glo) restoreCard(
+	ctx context.Context,
+	archive *backupArchive,
+	boardID string,
+	card *Card,
+	entry *BackupCard,
+	idMap *RestoreIDMap,
+) (err error) {
+	cardID, ok := idMap.Cards[card.ID]
+	if !ok {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		columnID, ok := idMap.Columns[card.ColumnID]
+		if !ok {
+			return fmt.Errorf("column:%s of the card was not restored", card.ColumnID)
+		}
+
+		input := card.Input()
+		input.ColumnID = columnID
+		input.Labels = nil
+		for _, label := range card.Labels {
+			if labelID, ok := idMap.Labels[label.ID]; ok {
+				input.Labels = append(input.Labels, &PartialLabel{ID: labelID})
+			}
+		}
+
+		var restored *Card
+		restored, err = a.CreateCard(boardID, input)
+		if err != nil {
+			return
+		}
+		cardID = restored.ID
+		idMap.Cards[card.ID] = cardID
+	}
+
+	dir := path.Dir(entry.Path)
+
+	var comments []*Comment
+	err = archive.readJSON(path.Join(dir, backupCommentsName), &comments)
+	if err != nil {
+		return
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		return comments[i].CreatedDate < comments[j].CreatedDate
+	})
+
+	// uploading an attachment creates a comment linking to it, the
+	// comments linking to the backed up copies are replaced by them
+	links := map[string]*Comment{}
+	for _, attachment := range entry.Attachments {
+		if attachment.Path == "" {
+			continue
+		}
+		for _, comment := range comments {
+			if strings.Contains(comment.Text, attachment.ID) {
+				links[attachment.ID] = comment
+				break
+			}
+		}
+	}
+	replaced := map[*Comment]bool{}
+	for _, comment := range links {
+		replaced[comment] = true
+	}
+
+	for _, comment := range comments {
+		if _, ok := idMap.Comments[comment.ID]; ok || replaced[comment] {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		var restored *Comment
+		restored, err = a.CreateComment(boardID, cardID, &CommentInput{Text: comment.Text})
+		if err != nil {
+			return
+		}
+		idMap.Comments[comment.ID] = restored.ID
+	}
+
+	for _, attachment := range entry.Attachments {
+		if _, ok := idMap.Attachments[attachment.ID]; ok || attachment.Path == "" {
+			continue
+		}
+		if err = ctx.Err(); err != nil {
+			return
+		}
+
+		data, ok := archive.files[attachment.Path]
+		if !ok {
+			return fmt.Errorf("backup archive does not contain %s", attachment.Path)
+		}
+
+		var generated *GeneratedAttachment
+		generated, err = a.CreateAttachment(boardID, cardID, attachment.Filename, bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		idMap.Attachments[attachment.ID] = generated.Attachment.ID
+		if link, ok := links[attachment.ID]; ok && generated.Comment != nil {
+			idMap.Comments[link.ID] = generated.Comment.ID
+		}
+	}
+
+	return
+}
@@ -0,0 +1,153 @@
+package glo
+
+import (
+	"sort"
+	"time"
+)
+
+// BoardSnapshot a capture of a board with its cards
+// and, optionally, their comments
+type BoardSnapshot struct {
+	CapturedAt time.Time `json:"captured_at"`
+	Board      *Board    `json:"board"`
+	Cards      []*Card   `json:"cards"`
+
+	// Comments the comments of each card by card ID
+	Comments map[string][]*Comment `json:"comments,omitempty"`
+}
+
+// SnapshotOptions contains information used to capture a board
+type SnapshotOptions struct {
+	// Archived includes archived cards
+	Archived bool
+
+	// Comments includes the comments of every card
+	Comments bool
+}
+
+// Snapshot Captures a board with its cards
+func (a *Glo) Snapshot(
+	boardID string,
+	opts *SnapshotOptions,
+) (
+	snapshot *BoardSnapshot,
+	err error,
+) {
+	if opts == nil {
+		opts = &SnapshotOptions{}
+	}
+
+	board, err := a.GetBoard(boardID)
+	if err != nil {
+		return
+	}
+
+	snapshot = &BoardSnapshot{
+		CapturedAt: time.Now().UTC(),
+		Board:      board,
+	}
+
+	snapshot.Cards, err = a.AllCards(boardID, false)
+	if err != nil {
+		return
+	}
+
+	if opts.Archived {
+		var archived []*Card
+		archived, err = a.AllCards(boardID, true)
+		if err != nil {
+			return
+		}
+
+		seen := map[string]bool{}
+		for _, card := range snapshot.Cards {
+			seen[card.ID] = true
+		}
+		for _, card := range archived {
+			if !seen[card.ID] {
+				snapshot.Cards = append(snapshot.Cards, card)
+			}
+		}
+	}
+
+	if !opts.Comments {
+		return
+	}
+
+	snapshot.Comments = map[string][]*Comment{}
+	for _, card := range snapshot.Cards {
+		var comments []*Comment
+		comments, err = a.AllComments(boardID, card.ID)
+		if err != nil {
+			return
+		}
+		snapshot.Comments[card.ID] = comments
+	}
+
+	return
+}
+
+// Column finds an active or archived column by ID
+func (s *BoardSnapshot) Column(id string) *Column {
+	for _, columns := range [][]*Column{s.Board.Columns, s.Board.ArchivedColumns} {
+		for _, col := range columns {
+			if col.ID == id {
+				return col
+			}
+		}
+	}
+
+	return nil
+}
+
+// Label finds a label by ID
+func (s *BoardSnapshot) Label(id string) *Label {
+	for _, label := range s.Board.Labels {
+		if label.ID == id {
+			return label
+		}
+	}
+
+	return nil
+}
+
+// Member finds a board member by user ID
+func (s *BoardSnapshot) Member(id string) *BoardMember {
+	for _, member := range s.Board.Members {
+		if member.ID == id {
+			return member
+		}
+	}
+
+	return nil
+}
+
+// SortedColumns the active columns followed by the
+// archived columns, each in position order
+func (s *BoardSnapshot) SortedColumns() []*Column {
+	var sorted []*Column
+	for _, columns := range [][]*Column{s.Board.Columns, s.Board.ArchivedColumns} {
+		group := append([]*Column{}, columns...)
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].Position < group[j].Position
+		})
+		sorted = append(sorted, group...)
+	}
+
+	return sorted
+}
+
+// CardsByColumn the cards of a column in position order
+func (s *BoardSnapshot) CardsByColumn(columnID string) []*Card {
+	var cards []*Card
+	for _, card := range s.Cards {
+		if card.ColumnID == columnID {
+			cards = append(cards, card)
+		}
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].Position < cards[j].Position
+	})
+
+	return cards
+}
@@ -0,0 +1,352 @@
+// Package tasklist parses and edits the Markdown task lists of card
+// descriptions.
+//
+// Only the lines of edited tasks change, the rest of the Markdown,
+// including its line endings, is written back untouched. Task items
+// inside fenced code blocks are ignored.
+package tasklist
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Task a task list item
+type Task struct {
+	Text string `json:"text"`
+	Done bool   `json:"done"`
+
+	// Line the line of the item in the document, starting at 1
+	Line int `json:"line"`
+}
+
+// item a parsed task list item, prefix is everything before
+// the checkbox and suffix everything after it up to the text
+type item struct {
+	line   int
+	prefix string
+	done   bool
+	suffix string
+	text   string
+	eol    string
+}
+
+func (it *item) String() string {
+	mark := " "
+	if it.done {
+		mark = "x"
+	}
+
+	return it.prefix + "[" + mark + "]" + it.suffix + it.text + it.eol
+}
+
+// taskItem matches list items with a checkbox, including those
+// nested in block quotes and ordered lists
+var taskItem = regexp.MustCompile(`^(\s*(?:>\s*)*(?:[-*+]|\d{1,9}[.)])\s+)\[([ xX])\](\s+|$)(.*)$`)
+
+var fence = regexp.MustCompile("^\\s{0,3}(```|~~~)")
+
+// Document a Markdown document and its task list items
+type Document struct {
+	lines []string
+	items []*item
+}
+
+// Parse parses the task list items of a Markdown document
+func Parse(text string) *Document {
+	d := &Document{}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if line != "" {
+			d.lines = append(d.lines, line)
+		}
+	}
+	d.index()
+
+	return d
+}
+
+// index locates the task items, outside of fenced code blocks
+func (d *Document) index() {
+	d.items = nil
+	inFence := ""
+
+	for i, raw := range d.lines {
+		line, eol := splitEOL(raw)
+
+		if m := fence.FindStringSubmatch(line); m != nil {
+			switch {
+			case inFence == "":
+				inFence = m[1]
+			case inFence == m[1]:
+				inFence = ""
+			}
+			continue
+		}
+		if inFence != "" {
+			continue
+		}
+
+		m := taskItem.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		d.items = append(d.items, &item{
+			line:   i,
+			prefix: m[1],
+			done:   m[2] != " ",
+			suffix: m[3],
+			text:   m[4],
+			eol:    eol,
+		})
+	}
+}
+
+func splitEOL(raw string) (line, eol string) {
+	switch {
+	case strings.HasSuffix(raw, "\r\n"):
+		return raw[:len(raw)-2], "\r\n"
+	case strings.HasSuffix(raw, "\n"):
+		return raw[:len(raw)-1], "\n"
+	}
+
+	return raw, ""
+}
+
+// String the document with its edits
+func (d *Document) String() string {
+	return strings.Join(d.lines, "")
+}
+
+// Tasks the task items of the document in order
+func (d *Document) Tasks() []*Task {
+	tasks := make([]*Task, len(d.items))
+	for i, it := range d.items {
+		tasks[i] = &Task{
+			Text: strings.TrimSpace(it.text),
+			Done: it.done,
+			Line: it.line + 1,
+		}
+	}
+
+	return tasks
+}
+
+// Checklist the lines of the task items alone, without the
+// rest of the document, separated by its line ending
+func (d *Document) Checklist() string {
+	eol := d.lineEnding()
+
+	var b strings.Builder
+	for i, it := range d.items {
+		if i > 0 {
+			b.WriteString(eol)
+		}
+		line, _ := splitEOL(d.lines[it.line])
+		b.WriteString(line)
+	}
+
+	return b.String()
+}
+
+// Len the number of task items
+func (d *Document) Len() int {
+	return len(d.items)
+}
+
+// Completed the number of completed task items
+func (d *Document) Completed() (n int) {
+	for _, it := range d.items {
+		if it.done {
+			n++
+		}
+	}
+
+	return
+}
+
+// Find the index of the first task whose text matches,
+// case-insensitively, or -1
+func (d *Document) Find(text string) int {
+	text = strings.TrimSpace(text)
+	for i, it := range d.items {
+		if strings.EqualFold(strings.TrimSpace(it.text), text) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (d *Document) item(i int) (it *item, err error) {
+	if i < 0 || i >= len(d.items) {
+		err = fmt.Errorf("task %d out of range, the document has %d tasks", i, len(d.items))
+		return
+	}
+
+	return d.items[i], nil
+}
+
+// update rewrites the line of an item
+func (d *Document) update(it *item) {
+	d.lines[it.line] = it.String()
+}
+
+// SetDone checks or unchecks the task at index i
+func (d *Document) SetDone(i int, done bool) (err error) {
+	it, err := d.item(i)
+	if err != nil {
+		return
+	}
+	it.done = done
+	d.update(it)
+
+	return
+}
+
+// Toggle flips the state of the task at index i
+func (d *Document) Toggle(i int) (err error) {
+	it, err := d.item(i)
+	if err != nil {
+		return
+	}
+
+	return d.SetDone(i, !it.done)
+}
+
+// SetText replaces the text of the task at index i
+func (d *Document) SetText(i int, text string) (err error) {
+	it, err := d.item(i)
+	if err != nil {
+		return
+	}
+	if strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("task text must be a single line")
+	}
+	if it.suffix == "" {
+		it.suffix = " "
+	}
+	it.text = text
+	d.update(it)
+
+	return
+}
+
+// Remove deletes the line of the task at index i
+func (d *Document) Remove(i int) (err error) {
+	it, err := d.item(i)
+	if err != nil {
+		return
+	}
+
+	// the document still ends without a line ending
+	if it.line == len(d.lines)-1 && it.eol == "" && it.line > 0 {
+		prev, _ := splitEOL(d.lines[it.line-1])
+		d.lines[it.line-1] = prev
+	}
+	d.lines = append(d.lines[:it.line], d.lines[it.line+1:]...)
+	d.index()
+
+	return
+}
+
+// Insert adds a task before the task at index i, matching its
+// indentation, an index of Len appends the task
+func (d *Document) Insert(i int, text string, done bool) (err error) {
+	if i == len(d.items) {
+		return d.Add(text, done)
+	}
+
+	it, err := d.item(i)
+	if err != nil {
+		return
+	}
+	if strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("task text must be a single line")
+	}
+
+	added := &item{prefix: it.prefix, done: done, suffix: " ", text: text, eol: it.eol}
+	if added.eol == "" {
+		added.eol = "\n"
+	}
+	d.insertLine(it.line, added.String())
+
+	return
+}
+
+// Add appends a task after the last task item, matching its marker,
+// or when the document has none, as a new list at the end of the document
+func (d *Document) Add(text string, done bool) (err error) {
+	if strings.ContainsAny(text, "\r\n") {
+		return fmt.Errorf("task text must be a single line")
+	}
+
+	eol := d.lineEnding()
+	added := &item{prefix: "- ", done: done, suffix: " ", text: text}
+
+	if len(d.items) == 0 {
+		// separate the new list from any preceding paragraph
+		if n := len(d.lines); n > 0 {
+			last, lastEOL := splitEOL(d.lines[n-1])
+			switch {
+			case strings.TrimSpace(last) == "" && lastEOL != "":
+			case lastEOL != "":
+				d.lines = append(d.lines, eol)
+			default:
+				d.lines[n-1] = last + eol
+				d.lines = append(d.lines, eol)
+			}
+		}
+		d.lines = append(d.lines, added.String())
+		d.index()
+		return
+	}
+
+	last := d.items[len(d.items)-1]
+	added.prefix = nextMarker(last.prefix)
+	added.eol = last.eol
+	if last.eol == "" {
+		// the document ended with the last item
+		last.eol = eol
+		d.update(last)
+	}
+	d.insertLine(last.line+1, added.String())
+
+	return
+}
+
+var orderedMarker = regexp.MustCompile(`^(\s*(?:>\s*)*)(\d{1,9})([.)]\s+)$`)
+
+// nextMarker the prefix of the item following one with the prefix,
+// ordered list numbers are incremented
+func nextMarker(prefix string) string {
+	m := orderedMarker.FindStringSubmatch(prefix)
+	if m == nil {
+		return prefix
+	}
+	n, _ := strconv.Atoi(m[2])
+
+	return m[1] + strconv.Itoa(n+1) + m[3]
+}
+
+func (d *Document) insertLine(at int, line string) {
+	d.lines = append(d.lines, "")
+	copy(d.lines[at+1:], d.lines[at:])
+	d.lines[at] = line
+	d.index()
+}
+
+// lineEnding the line ending used by the document
+func (d *Document) lineEnding() string {
+	for _, line := range d.lines {
+		if strings.HasSuffix(line, "\r\n") {
+			return "\r\n"
+		}
+		if strings.HasSuffix(line, "\n") {
+			return "\n"
+		}
+	}
+
+	return "\n"
+}
@@ -0,0 +1,426 @@
+package tasklist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []*Task
+	}{
+		{
+			name: "empty",
+			text: "",
+			want: []*Task{},
+		},
+		{
+			name: "bullets",
+			text: "- [ ] dash\n* [x] star\n+ [X] plus\n",
+			want: []*Task{
+				{Text: "dash", Line: 1},
+				{Text: "star", Done: true, Line: 2},
+				{Text: "plus", Done: true, Line: 3},
+			},
+		},
+		{
+			name: "numbered",
+			text: "1. [ ] first\n2) [x] second\n10. [ ] tenth",
+			want: []*Task{
+				{Text: "first", Line: 1},
+				{Text: "second", Done: true, Line: 2},
+				{Text: "tenth", Line: 3},
+			},
+		},
+		{
+			name: "nested and indented",
+			text: "- [ ] parent\n  - [x] child\n    1. [ ] grandchild\n\t- [ ] tabbed",
+			want: []*Task{
+				{Text: "parent", Line: 1},
+				{Text: "child", Done: true, Line: 2},
+				{Text: "grandchild", Line: 3},
+				{Text: "tabbed", Line: 4},
+			},
+		},
+		{
+			name: "block quotes",
+			text: "> - [ ] quoted\n> > * [x] nested quote",
+			want: []*Task{
+				{Text: "quoted", Line: 1},
+				{Text: "nested quote", Done: true, Line: 2},
+			},
+		},
+		{
+			name: "empty task",
+			text: "- [ ]\n- [x] ",
+			want: []*Task{
+				{Text: "", Line: 1},
+				{Text: "", Done: true, Line: 2},
+			},
+		},
+		{
+			name: "not tasks",
+			text: "[ ] no bullet\n- [] no space\n-[ ] no gap\n- [y] bad mark\n- [ ]text\n- plain item",
+			want: []*Task{},
+		},
+		{
+			name: "fenced code blocks are ignored",
+			text: "- [ ] before\n```md\n- [ ] in backticks\n~~~\n- [ ] still in backticks\n```\n" +
+				"~~~\n- [x] in tildes\n~~~\n- [ ] after",
+			want: []*Task{
+				{Text: "before", Line: 1},
+				{Text: "after", Line: 10},
+			},
+		},
+		{
+			name: "unclosed fence",
+			text: "- [ ] before\n```\n- [ ] code",
+			want: []*Task{{Text: "before", Line: 1}},
+		},
+		{
+			name: "crlf",
+			text: "Intro\r\n- [x] one\r\n- [ ] two\r\n",
+			want: []*Task{
+				{Text: "one", Done: true, Line: 2},
+				{Text: "two", Line: 3},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := Parse(test.text)
+			if got := d.Tasks(); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %v, want %v", tasks(got), tasks(test.want))
+			}
+			if d.String() != test.text {
+				t.Errorf("round trip got %q, want %q", d.String(), test.text)
+			}
+		})
+	}
+}
+
+// tasks formats tasks for failure messages
+func tasks(list []*Task) (out []Task) {
+	for _, task := range list {
+		out = append(out, *task)
+	}
+
+	return
+}
+
+func TestCounts(t *testing.T) {
+	d := Parse("- [x] one\n- [ ] two\n```\n- [x] code\n```\n- [X] three")
+	if d.Len() != 3 || d.Completed() != 2 {
+		t.Errorf("got %d of %d completed, want 2 of 3", d.Completed(), d.Len())
+	}
+	if i := d.Find("  TWO "); i != 1 {
+		t.Errorf("Find got %d, want 1", i)
+	}
+	if i := d.Find("code"); i != -1 {
+		t.Errorf("Find got %d, want -1", i)
+	}
+}
+
+// edit applies an edit to a document, the text
+// outside of the edited line must be untouched
+type edit struct {
+	name string
+	text string
+	edit func(d *Document) error
+	want string
+	err  string
+}
+
+func runEdits(t *testing.T, tests []edit) {
+	t.Helper()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := Parse(test.text)
+			err := test.edit(d)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Fatalf("got err %v, want %q", err, test.err)
+				}
+				if d.String() != test.text {
+					t.Errorf("failed edit changed the document to %q", d.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := d.String(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+
+			// the edited document parses to the same tasks
+			if got, want := Parse(d.String()).Tasks(), d.Tasks(); !reflect.DeepEqual(got, want) {
+				t.Errorf("reparsed %v, want %v", tasks(got), tasks(want))
+			}
+		})
+	}
+}
+
+func TestToggle(t *testing.T) {
+	runEdits(t, []edit{
+		{
+			name: "check",
+			text: "# Todo\n\n- [ ] one\n- [ ] two\n\nNotes",
+			edit: func(d *Document) error { return d.Toggle(1) },
+			want: "# Todo\n\n- [ ] one\n- [x] two\n\nNotes",
+		},
+		{
+			name: "uncheck keeps marker and spacing",
+			text: "  * [X]   spaced  \n",
+			edit: func(d *Document) error { return d.Toggle(0) },
+			want: "  * [ ]   spaced  \n",
+		},
+		{
+			name: "numbered nested in a quote",
+			text: "> 3) [ ] quoted",
+			edit: func(d *Document) error { return d.Toggle(0) },
+			want: "> 3) [x] quoted",
+		},
+		{
+			name: "crlf",
+			text: "- [ ] one\r\n- [ ] two\r\n",
+			edit: func(d *Document) error { return d.Toggle(0) },
+			want: "- [x] one\r\n- [ ] two\r\n",
+		},
+		{
+			name: "skips fenced items",
+			text: "```\n- [ ] code\n```\n- [ ] real",
+			edit: func(d *Document) error { return d.Toggle(0) },
+			want: "```\n- [ ] code\n```\n- [x] real",
+		},
+		{
+			name: "set done is idempotent",
+			text: "- [x] done",
+			edit: func(d *Document) error { return d.SetDone(0, true) },
+			want: "- [x] done",
+		},
+		{
+			name: "out of range",
+			text: "- [ ] one",
+			edit: func(d *Document) error { return d.Toggle(1) },
+			err:  "task 1 out of range, the document has 1 tasks",
+		},
+		{
+			name: "negative",
+			text: "- [ ] one",
+			edit: func(d *Document) error { return d.Toggle(-1) },
+			err:  "task -1 out of range, the document has 1 tasks",
+		},
+	})
+}
+
+func TestSetText(t *testing.T) {
+	runEdits(t, []edit{
+		{
+			name: "rename",
+			text: "Intro\n  - [x] old\nOutro",
+			edit: func(d *Document) error { return d.SetText(0, "new") },
+			want: "Intro\n  - [x] new\nOutro",
+		},
+		{
+			name: "empty task gains a space",
+			text: "- [ ]\n",
+			edit: func(d *Document) error { return d.SetText(0, "named") },
+			want: "- [ ] named\n",
+		},
+		{
+			name: "crlf",
+			text: "1. [ ] old\r\n",
+			edit: func(d *Document) error { return d.SetText(0, "new") },
+			want: "1. [ ] new\r\n",
+		},
+		{
+			name: "multiple lines",
+			text: "- [ ] old",
+			edit: func(d *Document) error { return d.SetText(0, "one\ntwo") },
+			err:  "task text must be a single line",
+		},
+	})
+}
+
+func TestAdd(t *testing.T) {
+	runEdits(t, []edit{
+		{
+			name: "empty document",
+			text: "",
+			edit: func(d *Document) error { return d.Add("first", false) },
+			want: "- [ ] first",
+		},
+		{
+			name: "no list after a paragraph",
+			text: "Some notes",
+			edit: func(d *Document) error { return d.Add("first", true) },
+			want: "Some notes\n\n- [x] first",
+		},
+		{
+			name: "no list after a paragraph with a line ending",
+			text: "Some notes\n",
+			edit: func(d *Document) error { return d.Add("first", false) },
+			want: "Some notes\n\n- [ ] first",
+		},
+		{
+			name: "no list after a blank line",
+			text: "Some notes\n\n",
+			edit: func(d *Document) error { return d.Add("first", false) },
+			want: "Some notes\n\n- [ ] first",
+		},
+		{
+			name: "no list with crlf",
+			text: "Some notes\r\n",
+			edit: func(d *Document) error { return d.Add("first", false) },
+			want: "Some notes\r\n\r\n- [ ] first",
+		},
+		{
+			name: "only fenced items",
+			text: "```\n- [ ] code\n```\n",
+			edit: func(d *Document) error { return d.Add("real", false) },
+			want: "```\n- [ ] code\n```\n\n- [ ] real",
+		},
+		{
+			name: "after the last item",
+			text: "- [ ] one\n- [x] two\n\nNotes\n",
+			edit: func(d *Document) error { return d.Add("three", false) },
+			want: "- [ ] one\n- [x] two\n- [ ] three\n\nNotes\n",
+		},
+		{
+			name: "last item ends the document",
+			text: "* [ ] one",
+			edit: func(d *Document) error { return d.Add("two", false) },
+			want: "* [ ] one\n* [ ] two",
+		},
+		{
+			name: "numbered",
+			text: "  9. [ ] nine\n",
+			edit: func(d *Document) error { return d.Add("ten", false) },
+			want: "  9. [ ] nine\n  10. [ ] ten\n",
+		},
+		{
+			name: "crlf",
+			text: "- [ ] one\r\n",
+			edit: func(d *Document) error { return d.Add("two", false) },
+			want: "- [ ] one\r\n- [ ] two\r\n",
+		},
+		{
+			name: "multiple lines",
+			text: "",
+			edit: func(d *Document) error { return d.Add("one\r\ntwo", false) },
+			err:  "task text must be a single line",
+		},
+	})
+}
+
+func TestInsert(t *testing.T) {
+	runEdits(t, []edit{
+		{
+			name: "before the first task",
+			text: "Intro\n- [ ] two\n",
+			edit: func(d *Document) error { return d.Insert(0, "one", true) },
+			want: "Intro\n- [x] one\n- [ ] two\n",
+		},
+		{
+			name: "matches nested indentation",
+			text: "- [ ] parent\n  - [ ] b\n",
+			edit: func(d *Document) error { return d.Insert(1, "a", false) },
+			want: "- [ ] parent\n  - [ ] a\n  - [ ] b\n",
+		},
+		{
+			name: "before the last task without a line ending",
+			text: "- [ ] b",
+			edit: func(d *Document) error { return d.Insert(0, "a", false) },
+			want: "- [ ] a\n- [ ] b",
+		},
+		{
+			name: "crlf",
+			text: "- [ ] b\r\n",
+			edit: func(d *Document) error { return d.Insert(0, "a", false) },
+			want: "- [ ] a\r\n- [ ] b\r\n",
+		},
+		{
+			name: "at the end appends",
+			text: "- [ ] a\n",
+			edit: func(d *Document) error { return d.Insert(1, "b", false) },
+			want: "- [ ] a\n- [ ] b\n",
+		},
+		{
+			name: "out of range",
+			text: "- [ ] a\n",
+			edit: func(d *Document) error { return d.Insert(2, "c", false) },
+			err:  "task 2 out of range, the document has 1 tasks",
+		},
+	})
+}
+
+func TestRemove(t *testing.T) {
+	runEdits(t, []edit{
+		{
+			name: "middle",
+			text: "- [ ] one\n- [ ] two\n- [ ] three\n",
+			edit: func(d *Document) error { return d.Remove(1) },
+			want: "- [ ] one\n- [ ] three\n",
+		},
+		{
+			name: "last item with a line ending",
+			text: "Intro\n- [ ] one\n- [ ] two\n",
+			edit: func(d *Document) error { return d.Remove(1) },
+			want: "Intro\n- [ ] one\n",
+		},
+		{
+			name: "last item ends the document",
+			text: "- [ ] one\n- [ ] two",
+			edit: func(d *Document) error { return d.Remove(1) },
+			want: "- [ ] one",
+		},
+		{
+			name: "last item ends a crlf document",
+			text: "- [ ] one\r\n- [ ] two",
+			edit: func(d *Document) error { return d.Remove(1) },
+			want: "- [ ] one",
+		},
+		{
+			name: "only item",
+			text: "- [x] only",
+			edit: func(d *Document) error { return d.Remove(0) },
+			want: "",
+		},
+		{
+			name: "keeps fenced items",
+			text: "```\n- [ ] code\n```\n- [ ] real\nOutro",
+			edit: func(d *Document) error { return d.Remove(0) },
+			want: "```\n- [ ] code\n```\nOutro",
+		},
+		{
+			name: "out of range",
+			text: "",
+			edit: func(d *Document) error { return d.Remove(0) },
+			err:  "task 0 out of range, the document has 0 tasks",
+		},
+	})
+}
+
+func TestChecklist(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"no tasks", "Just notes", ""},
+		{"tasks only", "Intro\n- [ ] one\n  - [x] nested\nOutro\n", "- [ ] one\n  - [x] nested"},
+		{"fenced tasks", "```\n- [ ] code\n```\n1. [ ] real", "1. [ ] real"},
+		{"crlf", "- [ ] one\r\nNotes\r\n- [x] two\r\n", "- [ ] one\r\n- [x] two"},
+	}
+
+	for _, test := range tests {
+		if got := Parse(test.text).Checklist(); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.name, got, test.want)
+		}
+	}
+}
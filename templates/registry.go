@@ -0,0 +1,146 @@
+package templates
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+const templateExt = ".json"
+
+var templateName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Registry stores templates as JSON files within a directory
+type Registry struct {
+	Dir string
+}
+
+// NewRegistry creates a Registry stored within dir
+func NewRegistry(dir string) *Registry {
+	return &Registry{Dir: dir}
+}
+
+// DefaultRegistry the registry stored alongside the glo config file
+func DefaultRegistry() (registry *Registry, err error) {
+	path, err := glo.ConfigPath()
+	if err != nil {
+		return
+	}
+	registry = NewRegistry(filepath.Join(filepath.Dir(path), "templates"))
+
+	return
+}
+
+func (r *Registry) path(name string) (path string, err error) {
+	if !templateName.MatchString(name) {
+		err = fmt.Errorf("invalid template name %q", name)
+		return
+	}
+	path = filepath.Join(r.Dir, name+templateExt)
+
+	return
+}
+
+// Save stores a template, replacing any template with the same name
+func (r *Registry) Save(t *Template) (err error) {
+	err = t.Validate()
+	if err != nil {
+		return
+	}
+
+	path, err := r.path(t.Name)
+	if err != nil {
+		return
+	}
+
+	err = os.MkdirAll(r.Dir, 0700)
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return
+	}
+
+	// write then rename so that a failed save does not corrupt the template
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Load reads a template by name
+func (r *Registry) Load(name string) (t *Template, err error) {
+	path, err := r.path(name)
+	if err != nil {
+		return
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		err = fmt.Errorf("template %q not found in %s", name, r.Dir)
+		return
+	}
+	if err != nil {
+		return
+	}
+
+	t = &Template{}
+	err = json.Unmarshal(data, t)
+	if err != nil {
+		err = fmt.Errorf("%s: %s", path, err)
+		return
+	}
+
+	err = t.Validate()
+
+	return
+}
+
+// List the names of the stored templates
+func (r *Registry) List() (names []string, err error) {
+	entries, err := ioutil.ReadDir(r.Dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, templateExt) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(name, templateExt))
+	}
+	sort.Strings(names)
+
+	return
+}
+
+// Delete removes a stored template
+func (r *Registry) Delete(name string) (err error) {
+	path, err := r.path(name)
+	if err != nil {
+		return
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		err = fmt.Errorf("template %q not found in %s", name, r.Dir)
+	}
+
+	return
+}
@@ -0,0 +1,297 @@
+// Package templates saves the layout of Glo boards as reusable templates
+// which can be instantiated with variables substituted into card
+// names and descriptions.
+//
+// Variables are written as {{name}}, e.g. a card named
+// "Kick off {{project}}" instantiated with project=Apollo
+// is created as "Kick off Apollo".
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jackmcguire1/go-glo"
+)
+
+// Version the version of the template format
+const Version = 1
+
+// Template the layout of a board
+type Template struct {
+	Version     int              `json:"version"`
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Columns     []string         `json:"columns"`
+	Labels      []*TemplateLabel `json:"labels"`
+	Cards       []*TemplateCard  `json:"cards,omitempty"`
+}
+
+// TemplateLabel a label of a template
+type TemplateLabel struct {
+	Name  string    `json:"name"`
+	Color glo.Color `json:"color"`
+}
+
+// TemplateCard a card of a template, referencing
+// its column and labels by name
+type TemplateCard struct {
+	Column      string   `json:"column"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+}
+
+// FromBoardOptions contains information used to create a template from a board
+type FromBoardOptions struct {
+	// Cards includes the active cards of the board
+	Cards bool
+
+	// Descriptions includes the descriptions of included cards
+	Descriptions bool
+}
+
+var variable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// FromBoard creates a template from the layout of an existing board
+func FromBoard(
+	client *glo.Glo,
+	boardID string,
+	name string,
+	opts *FromBoardOptions,
+) (
+	t *Template,
+	err error,
+) {
+	if opts == nil {
+		opts = &FromBoardOptions{}
+	}
+
+	board, err := client.GetBoard(boardID)
+	if err != nil {
+		return
+	}
+
+	t = &Template{
+		Version: Version,
+		Name:    name,
+		Columns: []string{},
+		Labels:  []*TemplateLabel{},
+	}
+
+	columns := append([]*glo.Column{}, board.Columns...)
+	sort.SliceStable(columns, func(i, j int) bool {
+		return columns[i].Position < columns[j].Position
+	})
+
+	columnNames := map[string]string{}
+	for _, col := range columns {
+		t.Columns = append(t.Columns, col.Name)
+		columnNames[col.ID] = col.Name
+	}
+
+	labelNames := map[string]string{}
+	for _, label := range board.Labels {
+		t.Labels = append(t.Labels, &TemplateLabel{Name: label.Name, Color: label.Color})
+		labelNames[label.ID] = label.Name
+	}
+
+	if !opts.Cards {
+		return
+	}
+
+	cards, err := client.AllCards(boardID, false)
+	if err != nil {
+		return
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		return cards[i].Position < cards[j].Position
+	})
+
+	for _, card := range cards {
+		column, ok := columnNames[card.ColumnID]
+		if !ok {
+			continue
+		}
+
+		tc := &TemplateCard{Column: column, Name: card.Name}
+		if opts.Descriptions && card.Description != nil {
+			tc.Description = card.Description.Text
+		}
+		for _, label := range card.Labels {
+			if name, ok := labelNames[label.ID]; ok {
+				tc.Labels = append(tc.Labels, name)
+			}
+		}
+		t.Cards = append(t.Cards, tc)
+	}
+
+	return
+}
+
+// Variables the names of the variables used by the template
+func (t *Template) Variables() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, card := range t.Cards {
+		for _, text := range []string{card.Name, card.Description} {
+			for _, match := range variable.FindAllStringSubmatch(text, -1) {
+				if !seen[match[1]] {
+					seen[match[1]] = true
+					names = append(names, match[1])
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Validate ensures column and label names are unique and
+// cards reference the template's columns and labels
+func (t *Template) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if t.Version > Version {
+		return fmt.Errorf("unsupported template version:%d", t.Version)
+	}
+
+	columns := map[string]bool{}
+	for _, col := range t.Columns {
+		if columns[col] {
+			return fmt.Errorf("column %q is listed more than once", col)
+		}
+		columns[col] = true
+	}
+	labels := map[string]bool{}
+	for _, label := range t.Labels {
+		if labels[label.Name] {
+			return fmt.Errorf("label %q is listed more than once", label.Name)
+		}
+		labels[label.Name] = true
+	}
+
+	for _, card := range t.Cards {
+		if !columns[card.Column] {
+			return fmt.Errorf("card %q references unknown column %q", card.Name, card.Column)
+		}
+		for _, label := range card.Labels {
+			if !labels[label] {
+				return fmt.Errorf("card %q references unknown label %q", card.Name, label)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Substitute replaces the variables of text, every
+// variable must have a value
+func Substitute(text string, vars map[string]string) (string, error) {
+	var missing []string
+	result := variable.ReplaceAllStringFunc(text, func(match string) string {
+		name := variable.FindStringSubmatch(match)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("no value for variables: %s", strings.Join(missing, ", "))
+	}
+
+	return result, nil
+}
+
+// Instantiate creates a new board from the template,
+// substituting variables into card names and descriptions
+func (t *Template) Instantiate(
+	client *glo.Glo,
+	boardName string,
+	vars map[string]string,
+) (
+	board *glo.Board,
+	err error,
+) {
+	err = t.Validate()
+	if err != nil {
+		return
+	}
+
+	// substitute everything up front so that a missing
+	// variable does not leave a partially created board
+	type pendingCard struct {
+		card        *TemplateCard
+		name        string
+		description string
+	}
+	var cards []*pendingCard
+	for _, card := range t.Cards {
+		p := &pendingCard{card: card}
+		if p.name, err = Substitute(card.Name, vars); err != nil {
+			return
+		}
+		if p.description, err = Substitute(card.Description, vars); err != nil {
+			return
+		}
+		cards = append(cards, p)
+	}
+
+	board, err = client.CreateBoard(&glo.BoardInput{Name: boardName})
+	if err != nil {
+		return
+	}
+
+	columnIDs := map[string]string{}
+	for position, name := range t.Columns {
+		var col *glo.Column
+		col, err = client.CreateColumn(board.ID, &glo.ColumnInput{Name: name, Position: position})
+		if err != nil {
+			return
+		}
+		columnIDs[name] = col.ID
+		board.Columns = append(board.Columns, col)
+	}
+
+	labelIDs := map[string]string{}
+	for _, tl := range t.Labels {
+		var label *glo.Label
+		label, err = client.CreateLabel(board.ID, &glo.LabelInput{Name: tl.Name, Color: tl.Color})
+		if err != nil {
+			return
+		}
+		labelIDs[tl.Name] = label.ID
+		board.Labels = append(board.Labels, label)
+	}
+
+	positions := map[string]int{}
+	for _, p := range cards {
+		input := &glo.CardsInput{
+			Name:     p.name,
+			ColumnID: columnIDs[p.card.Column],
+			Position: positions[p.card.Column],
+		}
+		positions[p.card.Column]++
+
+		if p.description != "" {
+			input.Description = &glo.MinimizedDescription{Text: p.description}
+		}
+		for _, label := range p.card.Labels {
+			input.Labels = append(input.Labels, &glo.PartialLabel{ID: labelIDs[label]})
+		}
+
+		_, err = client.CreateCard(board.ID, input)
+		if err != nil {
+			return
+		}
+	}
+
+	return
+}
@@ -0,0 +1,82 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template *Template
+		err      string
+	}{
+		{
+			name: "valid",
+			template: &Template{
+				Name:    "sprint",
+				Columns: []string{"Todo", "Done"},
+				Labels:  []*TemplateLabel{{Name: "bug"}, {Name: "feature"}},
+				Cards: []*TemplateCard{
+					{Column: "Todo", Name: "Plan {{sprint}}", Labels: []string{"feature"}},
+				},
+			},
+		},
+		{
+			name:     "missing name",
+			template: &Template{},
+			err:      "template name is required",
+		},
+		{
+			name:     "unsupported version",
+			template: &Template{Name: "sprint", Version: Version + 1},
+			err:      "unsupported template version",
+		},
+		{
+			name:     "duplicate column",
+			template: &Template{Name: "sprint", Columns: []string{"Todo", "Done", "Todo"}},
+			err:      `column "Todo" is listed more than once`,
+		},
+		{
+			name: "duplicate label",
+			template: &Template{
+				Name:   "sprint",
+				Labels: []*TemplateLabel{{Name: "bug"}, {Name: "bug"}},
+			},
+			err: `label "bug" is listed more than once`,
+		},
+		{
+			name: "unknown column",
+			template: &Template{
+				Name:    "sprint",
+				Columns: []string{"Todo"},
+				Cards:   []*TemplateCard{{Column: "Doing", Name: "card"}},
+			},
+			err: `card "card" references unknown column "Doing"`,
+		},
+		{
+			name: "unknown label",
+			template: &Template{
+				Name:    "sprint",
+				Columns: []string{"Todo"},
+				Cards:   []*TemplateCard{{Column: "Todo", Name: "card", Labels: []string{"bug"}}},
+			},
+			err: `card "card" references unknown label "bug"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.template.Validate()
+			if test.err == "" {
+				if err != nil {
+					t.Errorf("got err %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got err %v, want %q", err, test.err)
+			}
+		})
+	}
+}
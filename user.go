@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
 )
 
 // User contains information related to a User
@@ -19,13 +18,12 @@ var userFields = []string{"created_date", "email", "name", "username"}
 
 // GetUser get authenticated user
 // https://gloapi.gitkraken.com/v1/docs/#/Users/get_user
-func (a *Glo) GetUser() (user *User, err error) {
+func (a *Glo) GetUser(opts ...CallOption) (user *User, err error) {
 	addr := fmt.Sprintf("%s/user", a.BaseURI)
 
-	q := url.Values{}
-
-	for _, field := range userFields {
-		q.Add("fields", field)
+	q, err := fieldsQuery(userResource, userFields, opts)
+	if err != nil {
+		return
 	}
 
 	data, _, err := a.jsonReq(http.MethodGet, addr, nil, q)